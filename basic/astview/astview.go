@@ -0,0 +1,299 @@
+// Package astview shows what the compiler sees in a Go file: the syntax
+// tree of each function with the types go/types worked out for it, and
+// the function's SSA form from golang.org/x/tools/go/ssa, the
+// intermediate representation most analysis tools work on.
+//
+// Load gathers everything into a Report, which can be written as JSON, as
+// a Graphviz graph or as an HTML page with collapsible tree nodes linked
+// to the source.
+package astview
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// Pos is a position in the source file.
+type Pos struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Col    int `json:"col"`
+}
+
+// Node is one syntax tree node.
+type Node struct {
+	ID int `json:"id"`
+	// Kind is the go/ast type, e.g. "AssignStmt".
+	Kind string `json:"kind"`
+	// Field is the field of the parent that holds the node, e.g. "Lhs".
+	Field string `json:"field,omitempty"`
+	// Label is a short summary such as an identifier or operator.
+	Label string `json:"label,omitempty"`
+	// Type is the type of an expression, as go/types inferred it.
+	Type string `json:"type,omitempty"`
+	// Value is the value of a constant expression.
+	Value string `json:"value,omitempty"`
+	// Object says what an identifier declares or refers to.
+	Object   string  `json:"object,omitempty"`
+	Start    Pos     `json:"start"`
+	End      Pos     `json:"end"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Block is a basic block of an SSA function.
+type Block struct {
+	Index   int      `json:"index"`
+	Comment string   `json:"comment,omitempty"`
+	Instrs  []string `json:"instrs"`
+	Succs   []int    `json:"succs,omitempty"`
+}
+
+// Func is everything shown for one function.
+type Func struct {
+	Name      string  `json:"name"`
+	Signature string  `json:"signature"`
+	Source    string  `json:"source"`
+	AST       *Node   `json:"ast"`
+	Blocks    []Block `json:"ssa"`
+	// SSA is the function as ssa prints it.
+	SSA string `json:"ssa_text"`
+}
+
+// Report covers the selected functions of one file.
+type Report struct {
+	File  string `json:"file"`
+	Funcs []Func `json:"funcs"`
+}
+
+// Load type-checks the package of the file at path, builds its SSA form
+// and reports on the named functions: all of them when names is empty.
+// A method is named T.m or (*T).m.
+func Load(path string, names []string) (*Report, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedTypes |
+			packages.NeedTypesInfo | packages.NeedTypesSizes | packages.NeedImports | packages.NeedDeps,
+		Dir: filepath.Dir(abs),
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("astview: %s: expected one package, found %d", path, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("astview: %v", pkg.Errors[0])
+	}
+	var file *ast.File
+	for i, name := range pkg.CompiledGoFiles {
+		if name == abs {
+			file = pkg.Syntax[i]
+		}
+	}
+	if file == nil {
+		return nil, fmt.Errorf("astview: %s is not part of package %s", path, pkg.PkgPath)
+	}
+	src, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	prog, ssaPkgs := ssautil.Packages(pkgs, ssa.InstantiateGenerics)
+	ssaPkgs[0].Build()
+
+	r := &Report{File: path}
+	found := map[string]bool{}
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		name := funcName(fd)
+		if len(names) > 0 && !slices.Contains(names, name) {
+			continue
+		}
+		found[name] = true
+		obj, _ := pkg.TypesInfo.Defs[fd.Name].(*types.Func)
+		if obj == nil {
+			continue
+		}
+		b := &builder{fset: pkg.Fset, info: pkg.TypesInfo, pkg: pkg.Types}
+		f := Func{
+			Name:      name,
+			Signature: types.TypeString(obj.Type(), b.qualifier),
+			Source:    string(src[pkg.Fset.Position(fd.Pos()).Offset:pkg.Fset.Position(fd.End()).Offset]),
+			AST:       b.node(fd, ""),
+		}
+		if fn := prog.FuncValue(obj); fn != nil {
+			f.Blocks, f.SSA = ssaBlocks(fn)
+		}
+		r.Funcs = append(r.Funcs, f)
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("astview: %s has no function %s", path, name)
+		}
+	}
+	return r, nil
+}
+
+// funcName returns the name a function is selected by: "sum", "T.m" or
+// "(*T).m".
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	recv := types.ExprString(fd.Recv.List[0].Type)
+	if strings.HasPrefix(recv, "*") {
+		recv = "(" + recv + ")"
+	}
+	return recv + "." + fd.Name.Name
+}
+
+func ssaBlocks(fn *ssa.Function) ([]Block, string) {
+	var text bytes.Buffer
+	ssa.WriteFunction(&text, fn)
+	var blocks []Block
+	for _, b := range fn.Blocks {
+		blk := Block{Index: b.Index, Comment: b.Comment}
+		for _, instr := range b.Instrs {
+			if v, ok := instr.(ssa.Value); ok && v.Name() != "" {
+				blk.Instrs = append(blk.Instrs, fmt.Sprintf("%s = %s  (%s)", v.Name(), instr, relType(v.Type(), fn.Pkg)))
+			} else {
+				blk.Instrs = append(blk.Instrs, instr.String())
+			}
+		}
+		for _, s := range b.Succs {
+			blk.Succs = append(blk.Succs, s.Index)
+		}
+		blocks = append(blocks, blk)
+	}
+	return blocks, text.String()
+}
+
+func relType(t types.Type, pkg *ssa.Package) string {
+	if pkg == nil {
+		return t.String()
+	}
+	return types.TypeString(t, types.RelativeTo(pkg.Pkg))
+}
+
+// builder turns go/ast nodes into Nodes.
+type builder struct {
+	fset   *token.FileSet
+	info   *types.Info
+	pkg    *types.Package
+	nextID int
+}
+
+func (b *builder) qualifier(p *types.Package) string {
+	if p == b.pkg {
+		return ""
+	}
+	return p.Name()
+}
+
+func (b *builder) pos(p token.Pos) Pos {
+	position := b.fset.Position(p)
+	return Pos{Offset: position.Offset, Line: position.Line, Col: position.Column}
+}
+
+var nodeType = reflect.TypeFor[ast.Node]()
+
+// node converts n and its subtree. The children are found by reflection,
+// the way ast.Print walks the tree, so every node type is covered and
+// each child knows the field it came from.
+func (b *builder) node(n ast.Node, field string) *Node {
+	out := &Node{
+		ID:    b.nextID,
+		Kind:  strings.TrimPrefix(reflect.TypeOf(n).String(), "*ast."),
+		Field: field,
+		Start: b.pos(n.Pos()),
+		End:   b.pos(n.End()),
+	}
+	b.nextID++
+	b.annotate(out, n)
+
+	v := reflect.ValueOf(n).Elem()
+	for i := range v.NumField() {
+		f, name := v.Field(i), v.Type().Field(i).Name
+		if name == "Doc" || name == "Comment" {
+			continue
+		}
+		switch {
+		case f.Kind() == reflect.Slice && f.Type().Elem().Implements(nodeType):
+			for j := range f.Len() {
+				if child, ok := f.Index(j).Interface().(ast.Node); ok && !isNil(f.Index(j)) {
+					out.Children = append(out.Children, b.node(child, fmt.Sprintf("%s[%d]", name, j)))
+				}
+			}
+		case f.Type().Implements(nodeType) && !isNil(f):
+			out.Children = append(out.Children, b.node(f.Interface().(ast.Node), name))
+		}
+	}
+	return out
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// annotate fills in the label and the type information of a node.
+func (b *builder) annotate(out *Node, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Ident:
+		out.Label = n.Name
+		if obj := b.info.Defs[n]; obj != nil {
+			out.Object = "defines " + types.ObjectString(obj, b.qualifier)
+		} else if obj := b.info.Uses[n]; obj != nil {
+			out.Object = "uses " + types.ObjectString(obj, b.qualifier)
+		}
+	case *ast.BasicLit:
+		out.Label = n.Value
+	case *ast.BinaryExpr:
+		out.Label = n.Op.String()
+	case *ast.UnaryExpr:
+		out.Label = n.Op.String()
+	case *ast.AssignStmt:
+		out.Label = n.Tok.String()
+	case *ast.IncDecStmt:
+		out.Label = n.Tok.String()
+	case *ast.BranchStmt:
+		out.Label = n.Tok.String()
+	case *ast.GenDecl:
+		out.Label = n.Tok.String()
+	case *ast.FuncDecl:
+		out.Label = n.Name.Name
+	case *ast.SelectorExpr:
+		out.Label = "." + n.Sel.Name
+	}
+	if e, ok := n.(ast.Expr); ok {
+		if tv, ok := b.info.Types[e]; ok && tv.Type != nil {
+			out.Type = types.TypeString(tv.Type, b.qualifier)
+			if tv.Value != nil {
+				out.Value = tv.Value.ExactString()
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package astview
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var fixture = filepath.Join("testdata", "lesson", "lesson.go")
+
+// golden compares got with the file testdata/name, or rewrites the file
+// when the tests run with -update. The absolute path of testdata, which
+// the SSA listing mentions, is replaced so the files match on any machine.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	abs, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = bytes.ReplaceAll(got, []byte(abs), []byte("testdata"))
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (rerun with -update to accept it):\n%s", path, got)
+	}
+}
+
+func load(t *testing.T, names ...string) *Report {
+	t.Helper()
+	r, err := Load(fixture, names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestLoad(t *testing.T) {
+	r := load(t)
+	var names []string
+	for _, f := range r.Funcs {
+		names = append(names, f.Name)
+	}
+	if want := []string{"sum", "passbyreference", "count", "(*counter).add"}; !slices.Equal(names, want) {
+		t.Errorf("functions %q, want %q", names, want)
+	}
+	if _, err := Load(fixture, []string{"missing"}); err == nil {
+		t.Error("Load with an unknown function succeeded")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(load(t, "sum", "(*counter).add")); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "lesson.json", buf.Bytes())
+}
+
+func TestWriteDot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDot(&buf, load(t, "count")); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "count.dot", buf.Bytes())
+}
+
+func TestWriteHTML(t *testing.T) {
+	r := load(t, "passbyreference")
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "passbyreference.html", buf.Bytes())
+	// The load, add and store through the pointer.
+	ssa := r.Funcs[0].SSA
+	for _, want := range []string{"t0 = *x", "t1 = t0 + 10:int", "*x = t1"} {
+		if !strings.Contains(ssa, want) {
+			t.Errorf("SSA lacks %q:\n%s", want, ssa)
+		}
+	}
+}
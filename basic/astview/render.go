@@ -0,0 +1,132 @@
+package astview
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// WriteDot writes the report as one Graphviz digraph. Each function gets
+// two clusters: its syntax tree, and its SSA control-flow graph with the
+// instructions of each block.
+func WriteDot(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %q {\n", r.File)
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\", fontsize=10];")
+	for i, f := range r.Funcs {
+		fmt.Fprintf(bw, "\tsubgraph \"cluster_ast_%d\" {\n\t\tlabel=%q;\n", i, f.Name+": syntax tree")
+		var walk func(n *Node)
+		walk = func(n *Node) {
+			fmt.Fprintf(bw, "\t\t\"f%d_n%d\" [label=%q];\n", i, n.ID, nodeLabel(n))
+			for _, c := range n.Children {
+				fmt.Fprintf(bw, "\t\t\"f%d_n%d\" -> \"f%d_n%d\" [label=%q];\n", i, n.ID, i, c.ID, c.Field)
+				walk(c)
+			}
+		}
+		walk(f.AST)
+		fmt.Fprintln(bw, "\t}")
+
+		fmt.Fprintf(bw, "\tsubgraph \"cluster_ssa_%d\" {\n\t\tlabel=%q;\n", i, f.Name+": SSA")
+		for _, b := range f.Blocks {
+			label := fmt.Sprintf("%d: %s\\l", b.Index, b.Comment)
+			for _, instr := range b.Instrs {
+				label += dotEscape(instr) + "\\l"
+			}
+			fmt.Fprintf(bw, "\t\t\"f%d_b%d\" [label=\"%s\"];\n", i, b.Index, label)
+			for _, s := range b.Succs {
+				fmt.Fprintf(bw, "\t\t\"f%d_b%d\" -> \"f%d_b%d\";\n", i, b.Index, i, s)
+			}
+		}
+		fmt.Fprintln(bw, "\t}")
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// nodeLabel is the text of a tree node: kind, label and type on separate
+// lines.
+func nodeLabel(n *Node) string {
+	parts := []string{n.Kind}
+	if n.Label != "" {
+		parts = append(parts, n.Label)
+	}
+	if n.Type != "" && n.Type != n.Label {
+		parts = append(parts, n.Type)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// dotEscape escapes s for a left-justified ("\l") label, which cannot go
+// through %q: the escapes have to reach Graphviz as written.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\l`).Replace(s)
+}
+
+// WriteHTML writes the report as a page: for each function the source,
+// the syntax tree as nested collapsible nodes and the SSA blocks. Hovering
+// a tree node highlights its source range.
+func WriteHTML(w io.Writer, r *Report) error {
+	return pageTemplate.Execute(w, r)
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.File}}: what the compiler sees</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+.func { display: grid; grid-template-columns: 1fr 1fr; gap: 1em; margin-bottom: 2em; }
+pre { font-size: 13px; background: #f7f7f7; padding: .5em; tab-size: 4; overflow-x: auto; }
+details { margin-left: 1em; font-size: 13px; }
+summary { cursor: pointer; font-family: monospace; }
+.field { color: #888; }
+.label { font-weight: bold; }
+.type { color: #2a7; }
+.obj { color: #a62; }
+mark { background: #fd6; }
+</style>
+</head>
+<body>
+<h1>{{.File}}</h1>
+{{range .Funcs}}
+<h2>{{.Name}} <code>{{.Signature}}</code></h2>
+<div class="func">
+<div>
+<h3>Source</h3>
+<pre class="src" data-start="{{.AST.Start.Offset}}">{{.Source}}</pre>
+<h3>SSA</h3>
+{{range .Blocks}}<pre>{{.Index}}: {{.Comment}}{{if .Succs}}  → {{range $i, $s := .Succs}}{{if $i}}, {{end}}{{$s}}{{end}}{{end}}
+{{range .Instrs}}	{{.}}
+{{end}}</pre>{{end}}
+</div>
+<div>
+<h3>Syntax tree</h3>
+{{template "node" .AST}}
+</div>
+</div>
+{{end}}
+<script>
+// Hovering a node marks its source range in the source of its function.
+document.querySelectorAll(".func").forEach(fn => {
+	const pre = fn.querySelector("pre.src");
+	const base = +pre.dataset.start, text = pre.textContent;
+	const esc = s => s.replace(/&/g, "&amp;").replace(/</g, "&lt;");
+	fn.querySelectorAll("summary").forEach(s => {
+		const d = s.parentElement;
+		s.addEventListener("mouseenter", () => {
+			const a = d.dataset.start - base, b = d.dataset.end - base;
+			pre.innerHTML = esc(text.slice(0, a)) + "<mark>" + esc(text.slice(a, b)) + "</mark>" + esc(text.slice(b));
+		});
+		s.addEventListener("mouseleave", () => { pre.textContent = text; });
+	});
+});
+</script>
+</body>
+</html>
+{{define "node"}}<details open data-start="{{.Start.Offset}}" data-end="{{.End.Offset}}">
+<summary>{{if .Field}}<span class="field">{{.Field}}:</span> {{end}}{{.Kind}}{{if .Label}} <span class="label">{{.Label}}</span>{{end}}{{if .Type}} <span class="type">{{.Type}}</span>{{end}}{{if .Value}} = {{.Value}}{{end}}{{if .Object}} <span class="obj">{{.Object}}</span>{{end}} <span class="field">{{.Start.Line}}:{{.Start.Col}}</span></summary>
+{{range .Children}}{{template "node" .}}{{end}}</details>{{end}}
+`))
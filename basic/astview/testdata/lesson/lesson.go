@@ -0,0 +1,27 @@
+package lesson
+
+import "fmt"
+
+const bonus = 10
+
+func sum(a int, b int) int {
+	return a + b
+}
+
+func passbyreference(x *int) {
+	*x = *x + bonus
+	fmt.Println("Inside passbyreference, number:", *x)
+}
+
+func count(n int) (evens int) {
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			evens++
+		}
+	}
+	return evens
+}
+
+type counter struct{ n int }
+
+func (c *counter) add(d int) { c.n += d }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/astview"
+)
+
+func init() {
+	register(&command{
+		name:    "ast",
+		summary: "show the syntax tree, types and SSA form of functions",
+		run:     runAST,
+	})
+}
+
+func runAST(args []string) error {
+	fs := flag.NewFlagSet("ast", flag.ContinueOnError)
+	var funcs []string
+	fs.Func("func", "function to show, e.g. sum or (*T).m (repeatable; default all)", func(s string) error {
+		funcs = append(funcs, strings.Split(s, ",")...)
+		return nil
+	})
+	format := fs.String("format", "html", "output format: dot, html or json")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic ast [-func name] [-format dot|html|json] file.go")
+		fs.PrintDefaults()
+	}
+	// Accept the file before the flags too, as in `basic ast main.go -func sum`.
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append(args[1:], args[0])
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one file")
+	}
+	r, err := astview.Load(fs.Arg(0), funcs)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "dot":
+		return astview.WriteDot(os.Stdout, r)
+	case "html":
+		return astview.WriteHTML(os.Stdout, r)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/amiiralihassanpour/golang_learning/explain"
+)
+
+// readme is embedded so the notes only point at sections that exist.
+//
+//go:embed README.md
+var readme []byte
+
+func init() {
+	register(&command{
+		name:    "explain",
+		summary: "annotate a Go file with what each construct does",
+		run:     runExplain,
+	})
+}
+
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	html := fs.Bool("html", false, "write an HTML page instead of text")
+	width := fs.Int("width", 60, "width of the source column in text output")
+	readmeURL := fs.String("readme", "README.md", "URL the HTML links to README sections under")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic explain [-html] [-width n] file.go")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one file")
+	}
+	name := fs.Arg(0)
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	notes, err := explain.File(name)
+	if err != nil {
+		return err
+	}
+	headings := explain.Headings(readme)
+	for i := range notes {
+		if !headings[notes[i].Section] {
+			notes[i].Section = ""
+		}
+	}
+	if *html {
+		return explain.WriteHTML(os.Stdout, name, src, notes, *readmeURL)
+	}
+	return explain.WriteText(os.Stdout, src, notes, *width)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/amiiralihassanpour/golang_learning/grader"
+)
+
+func init() {
+	register(&command{
+		name:    "grade",
+		summary: "build and test submitted programs in a sandbox",
+		run:     runGrade,
+	})
+}
+
+func runGrade(args []string) error {
+	fs := flag.NewFlagSet("grade", flag.ContinueOnError)
+	casesFile := fs.String("cases", "", "JSON `file` with the test cases and normalisation")
+	limits := grader.DefaultLimits
+	fs.DurationVar(&limits.CPU, "cpu", limits.CPU, "CPU time limit per case, 0 for none")
+	fs.DurationVar(&limits.Wall, "timeout", limits.Wall, "wall-clock limit per case, 0 for none")
+	mem := fs.Int64("mem", limits.Memory>>20, "memory limit per case in MiB, 0 for none")
+	output := fs.Int64("output", limits.Output>>10, "cap on stdout and stderr in KiB, 0 for none")
+	workers := fs.Int("j", runtime.GOMAXPROCS(0), "submissions graded in parallel")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic grade -cases cases.json [flags] submission-dir...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *casesFile == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("need -cases and at least one submission")
+	}
+	data, err := os.ReadFile(*casesFile)
+	if err != nil {
+		return err
+	}
+	cfg := grader.Config{Normalize: grader.DefaultNormalize}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("%s: %w", *casesFile, err)
+	}
+	limits.Memory, limits.Output = *mem<<20, *output<<10
+	if limits.CPU < 0 || limits.Wall < 0 || limits.Memory < 0 || limits.Output < 0 {
+		return errors.New("-cpu, -timeout, -mem and -output must not be negative")
+	}
+	if limits == (grader.Limits{}) {
+		// The grader would read this as "use the defaults".
+		return errors.New("-cpu, -timeout, -mem and -output cannot all be 0")
+	}
+	cfg.Limits = limits
+
+	reports := grader.GradeAll(context.Background(), fs.Args(), cfg, *workers)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(reports); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		fmt.Fprintf(os.Stderr, "%-40s %d/%d\n", r.Submission, r.Passed, r.Total)
+	}
+	return nil
+}
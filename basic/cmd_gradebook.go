@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/gradebook"
+)
+
+func init() {
+	register(&command{
+		name:    "gradebook",
+		summary: "grade a class from a CSV of weighted assessments, with statistics, curves and report cards",
+		run:     runGradebook,
+	})
+}
+
+func runGradebook(args []string) error {
+	const usage = "usage: basic gradebook report [-curve name] [-student name] [file.csv]\n" +
+		"       basic gradebook export [-curve name] [file.csv]\n" +
+		"       basic gradebook sample"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "report", "export":
+		return gradebookReport(args[0], args[1:])
+	case "sample":
+		return gradebook.Sample().Write(os.Stdout)
+	}
+	return errors.New(usage)
+}
+
+// loadBook reads a book from a CSV file, or returns the sample class if
+// no file is given.
+func loadBook(args []string) (*gradebook.Book, error) {
+	switch len(args) {
+	case 0:
+		return gradebook.Sample(), nil
+	case 1:
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		b, err := gradebook.Read(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", args[0], err)
+		}
+		return b, nil
+	}
+	return nil, errors.New("want at most one file")
+}
+
+func curveNames() string {
+	var names []string
+	for _, c := range gradebook.Curves {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func gradebookReport(mode string, args []string) error {
+	fs := flag.NewFlagSet("gradebook "+mode, flag.ContinueOnError)
+	curveName := fs.String("curve", "straight", "how to assign letters: "+curveNames())
+	var student *string
+	if mode == "report" {
+		student = fs.String("student", "", "print only this student's report card")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: basic gradebook %s [flags] [file.csv]\n\nWithout a file the sample class is used (see basic gradebook sample).\n\n", mode)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	curve, err := gradebook.LookupCurve(*curveName)
+	if err != nil {
+		return err
+	}
+	b, err := loadBook(fs.Args())
+	if err != nil {
+		return err
+	}
+	r := b.Report(curve)
+	switch {
+	case mode == "export":
+		return r.WriteCSV(os.Stdout)
+	case *student != "":
+		card, ok := r.Card(*student)
+		if !ok {
+			return fmt.Errorf("no student called %q", *student)
+		}
+		return card.Write(os.Stdout)
+	}
+	return r.Write(os.Stdout)
+}
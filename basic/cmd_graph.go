@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/graph"
+)
+
+func init() {
+	register(&command{
+		name:    "graph",
+		summary: "run graph algorithms on a graph file or draw it as DOT",
+		run:     runGraph,
+	})
+}
+
+func runGraph(args []string) error {
+	const usage = "usage: basic graph run [-algo name] [-from node] [-to node] [-dot] file"
+	if len(args) == 0 || args[0] != "run" {
+		return errors.New(usage)
+	}
+	return graphRun(args[1:])
+}
+
+// loadGraph reads a graph file.
+func loadGraph(name string) (*graph.Graph[string], error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return graph.Read(f)
+}
+
+func graphRun(args []string) error {
+	fs := flag.NewFlagSet("graph run", flag.ContinueOnError)
+	algo := fs.String("algo", "", "bfs, dfs, topo, dijkstra, bellman-ford, kruskal, prim, scc or maxflow")
+	from := fs.String("from", "", "start node (default: the first node)")
+	to := fs.String("to", "", "target node: the path end for shortest paths, the sink for maxflow")
+	dot := fs.Bool("dot", false, "write the graph in Graphviz DOT with the result highlighted")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic graph run [flags] file")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("want one graph")
+	}
+	g, err := loadGraph(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *from == "" && g.Order() > 0 {
+		*from = g.Nodes()[0]
+	}
+	if *from != "" && !g.Has(*from) {
+		return fmt.Errorf("no node %q", *from)
+	}
+
+	// Text goes to stdout unless DOT does, in which case it is dropped so
+	// the output can be piped straight into dot.
+	var out io.Writer = os.Stdout
+	if *dot {
+		out = io.Discard
+	}
+	opts := graph.DOTOptions[string]{Name: strings.TrimSuffix(path.Base(fs.Arg(0)), ".txt")}
+	switch *algo {
+	case "":
+	case "bfs":
+		var order []string
+		for n, depth := range g.BFS(*from) {
+			fmt.Fprintf(out, "%d  %s\n", depth, n)
+			order = append(order, n)
+		}
+		opts.Clusters = [][]string{order}
+	case "dfs":
+		order := slices.Collect(g.DFS(*from))
+		fmt.Fprintln(out, strings.Join(order, " "))
+		opts.Clusters = [][]string{order}
+	case "topo":
+		order, err := g.TopoSort()
+		var cycle *graph.CycleError[string]
+		if errors.As(err, &cycle) && *dot {
+			opts.Highlight = cycleEdges(cycle.Cycle)
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, strings.Join(order, " "))
+	case "dijkstra", "bellman-ford":
+		var p *graph.Paths[string]
+		if *algo == "dijkstra" {
+			p, err = g.Dijkstra(*from)
+		} else {
+			p, err = g.BellmanFord(*from)
+		}
+		var cycle *graph.NegativeCycleError[string]
+		if errors.As(err, &cycle) && *dot {
+			opts.Highlight = cycleEdges(cycle.Cycle)
+			break
+		}
+		if err != nil {
+			return err
+		}
+		opts.Highlight = p.Tree()
+		if *to != "" {
+			opts.Highlight = p.To(*to)
+		}
+		for _, n := range g.Nodes() {
+			if *to != "" && n != *to {
+				continue
+			}
+			fmt.Fprintf(out, "%-6g %-4s %s\n", p.Dist(n), n, strings.Join(graph.Nodes(p.To(n)), " "))
+		}
+	case "kruskal", "prim":
+		mst := g.Kruskal
+		if *algo == "prim" {
+			mst = g.Prim
+		}
+		var total float64
+		opts.Highlight, total, err = mst()
+		if err != nil {
+			return err
+		}
+		for _, e := range opts.Highlight {
+			fmt.Fprintf(out, "%s %s %g\n", e.From, e.To, e.Weight)
+		}
+		fmt.Fprintf(out, "total %g\n", total)
+	case "scc":
+		opts.Clusters = g.StronglyConnected()
+		for _, c := range opts.Clusters {
+			fmt.Fprintln(out, strings.Join(c, " "))
+		}
+	case "maxflow":
+		if *to == "" {
+			*to = g.Nodes()[g.Order()-1]
+		}
+		f, err := g.MaxFlow(*from, *to)
+		if err != nil {
+			return err
+		}
+		opts.Highlight = f.Edges
+		opts.Clusters = [][]string{f.Cut}
+		for _, e := range f.Edges {
+			fmt.Fprintf(out, "%s %s %g\n", e.From, e.To, e.Weight)
+		}
+		fmt.Fprintf(out, "flow %g, cut %s\n", f.Value, strings.Join(f.Cut, " "))
+	default:
+		return fmt.Errorf("unknown algorithm %q", *algo)
+	}
+	if *dot {
+		return g.WriteDOT(os.Stdout, opts)
+	}
+	return nil
+}
+
+// cycleEdges turns a cycle [a b c a] into its edges, for highlighting.
+func cycleEdges(cycle []string) []graph.Edge[string] {
+	var edges []graph.Edge[string]
+	for i := 1; i < len(cycle); i++ {
+		edges = append(edges, graph.Edge[string]{From: cycle[i-1], To: cycle[i]})
+	}
+	return edges
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/amiiralihassanpour/golang_learning/jq"
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+func init() {
+	register(&command{
+		name:    "jq",
+		summary: "run a jq-style query over JSON or NDJSON input",
+		run:     runJQ,
+	})
+}
+
+func runJQ(args []string) error {
+	fs := flag.NewFlagSet("jq", flag.ContinueOnError)
+	compact := fs.Bool("c", false, "compact output, one value per line")
+	raw := fs.Bool("r", false, "print strings without quotes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic jq [-c] [-r] filter [file ...]")
+		fmt.Fprintln(fs.Output(), `example: basic jq '.students[] | select(.age > 25) | .name' data.json`)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing filter")
+	}
+
+	q, err := jq.Compile(fs.Arg(0))
+	if err != nil {
+		var se *jq.SyntaxError
+		if errors.As(err, &se) {
+			return fmt.Errorf("%v\n    %s", se, indentLines(se.Context(), "    "))
+		}
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	run := func(r io.Reader, name string) error {
+		// Values are decoded one at a time, so NDJSON files of any size are
+		// processed without loading them whole.
+		dec := jsonparse.NewDecoder(r)
+		for {
+			v, err := dec.Decode()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			results, err := q.Run(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			for _, res := range results {
+				switch {
+				case *raw && res.Kind == jsonparse.String:
+					out.WriteString(res.Str)
+				case *compact:
+					out.Write(jsonparse.Marshal(res))
+				default:
+					out.Write(jsonparse.MarshalIndent(res, "", "  "))
+				}
+				out.WriteByte('\n')
+			}
+		}
+	}
+
+	if fs.NArg() == 1 {
+		return run(os.Stdin, "<stdin>")
+	}
+	for _, name := range fs.Args()[1:] {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = run(f, name)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indentLines prefixes every line after the first with indent.
+func indentLines(s, indent string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		out = append(out, s[i])
+		if s[i] == '\n' {
+			out = append(out, indent...)
+		}
+	}
+	return string(out)
+}
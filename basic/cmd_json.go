@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+func init() {
+	register(&command{
+		name:    "json",
+		summary: "parse JSON with the hand-written jsonparse package",
+		run:     runJSON,
+	})
+}
+
+func runJSON(args []string) error {
+	fs := flag.NewFlagSet("json", flag.ContinueOnError)
+	events := fs.Bool("events", false, "print the SAX-style event stream instead of the parsed value")
+	indent := fs.String("indent", "  ", "indentation for pretty-printed output")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic json [-events] [-indent s] [file ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return jsonStream(os.Stdin, "<stdin>", *events, *indent)
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = jsonStream(f, name, *events, *indent)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonStream prints every top-level value (or its events) found in r.
+func jsonStream(r io.Reader, name string, events bool, indent string) error {
+	if events {
+		rd := jsonparse.NewReader(r)
+		for {
+			ev, err := rd.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			line := strings.Repeat("  ", ev.Depth) + ev.Kind.String()
+			switch ev.Kind {
+			case jsonparse.EventKey:
+				line += " " + string(jsonparse.AppendQuote(nil, ev.Key))
+			case jsonparse.EventValue:
+				line += " " + ev.Value.String()
+			}
+			fmt.Printf("%s:%s\t%s\n", name, ev.Pos, line)
+		}
+	}
+	dec := jsonparse.NewDecoder(r)
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Printf("%s\n", jsonparse.MarshalIndent(v, "", indent))
+	}
+}
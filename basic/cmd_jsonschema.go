@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+	"github.com/amiiralihassanpour/golang_learning/jsonschema"
+)
+
+func init() {
+	register(&command{
+		name:    "jsonschema",
+		summary: "validate JSON files against a JSON Schema",
+		run:     runJSONSchema,
+	})
+}
+
+func runJSONSchema(args []string) error {
+	fs := flag.NewFlagSet("jsonschema", flag.ContinueOnError)
+	schemaFile := fs.String("schema", "", "schema `file` (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic jsonschema -schema file [file ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *schemaFile == "" {
+		fs.Usage()
+		return errors.New("missing -schema")
+	}
+	data, err := os.ReadFile(*schemaFile)
+	if err != nil {
+		return err
+	}
+	schema, err := jsonschema.Compile(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *schemaFile, err)
+	}
+
+	invalid := 0
+	check := func(r io.Reader, name string) error {
+		// Each file may hold several values, e.g. NDJSON payload logs.
+		dec := jsonparse.NewDecoder(r)
+		for i := 0; ; i++ {
+			v, err := dec.Decode()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			var verrs jsonschema.ValidationErrors
+			if !errors.As(schema.Validate(v), &verrs) {
+				continue
+			}
+			invalid++
+			for _, e := range verrs {
+				fmt.Printf("%s[%d]: %v (schema %s)\n", name, i, e, e.KeywordLocation)
+			}
+		}
+	}
+
+	if fs.NArg() == 0 {
+		if err := check(os.Stdin, "<stdin>"); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = check(f, name)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid documents", invalid)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/kata"
+)
+
+func init() {
+	register(&command{
+		name:    "kata",
+		summary: "turn a solution file into an exercise with hidden tests",
+		run:     runKata,
+	})
+}
+
+func runKata(args []string) error {
+	if len(args) == 0 || args[0] != "gen" {
+		return errors.New("usage: basic kata gen [flags] file.go")
+	}
+	fs := flag.NewFlagSet("kata gen", flag.ContinueOnError)
+	funcs := fs.String("funcs", "", "comma-separated functions to stub besides those marked "+kata.Directive)
+	out := fs.String("o", "exercise", "output `dir`: the stub goes to dir/student, the tests to dir/hidden")
+	verify := fs.Bool("verify", true, "check that the tests pass on the solution and fail on the stub")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic kata gen [flags] file.go")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one file")
+	}
+	name := fs.Arg(0)
+	src, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var opts kata.Options
+	if *funcs != "" {
+		opts.Funcs = strings.Split(*funcs, ",")
+	}
+	k, err := kata.Generate(name, src, opts)
+	if err != nil {
+		return err
+	}
+	for _, fn := range k.Funcs {
+		if why, ok := k.Untested[fn]; ok {
+			fmt.Printf("stubbed %s (no test: %s)\n", fn, why)
+		} else {
+			fmt.Printf("stubbed %s\n", fn)
+		}
+	}
+	if *verify {
+		if err := kata.Verify(context.Background(), filepath.Dir(name), k); err != nil {
+			return err
+		}
+		fmt.Println("verified: the tests pass on the solution and fail on the stub")
+	}
+
+	type output struct {
+		path string
+		data []byte
+	}
+	files := []output{{filepath.Join(*out, "student", k.Filename), k.Stub}}
+	if k.Test != nil {
+		files = append(files, output{filepath.Join(*out, "hidden", k.TestFilename()), k.Test})
+	}
+	for _, f := range files {
+		path, data := f.path, f.data
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/amiiralihassanpour/golang_learning/layout"
+	"github.com/amiiralihassanpour/golang_learning/students"
+)
+
+func init() {
+	register(&command{
+		name:    "layout",
+		summary: "show how structs, slices, strings, maps and arrays sit in memory",
+		run:     runLayout,
+	})
+}
+
+// padded orders its fields badly on purpose: every small field is
+// followed by a bigger, more strictly aligned one.
+type padded struct {
+	Active  bool
+	ID      int64
+	Grade   byte
+	Score   float64
+	Passed  bool
+	Credits int32
+}
+
+func runLayout(args []string) error {
+	fs := flag.NewFlagSet("layout", flag.ContinueOnError)
+	word := fs.Int("word", int(unsafe.Sizeof(uintptr(0))), "bytes per row in the byte maps")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *word <= 0 {
+		return fmt.Errorf("-word must be positive, got %d", *word)
+	}
+	w := os.Stdout
+
+	for _, v := range []any{padded{}, students.Student{}} {
+		s, err := layout.OfValue(v)
+		if err != nil {
+			return err
+		}
+		s.WriteTable(w)
+		s.WriteBytes(w, uintptr(*word))
+		if r := s.Reordered(); r.Size < s.Size {
+			fmt.Fprintf(w, "\nreordered by alignment it takes %d bytes instead of %d:\n", r.Size, s.Size)
+			r.WriteTable(w)
+			r.WriteBytes(w, uintptr(*word))
+		}
+		fmt.Fprintln(w)
+	}
+	// The same numbers straight from unsafe, which needs the static type.
+	var p padded
+	fmt.Fprintf(w, "unsafe: Sizeof(padded{}) = %d, Alignof(p.ID) = %d, Offsetof(p.ID) = %d, Offsetof(p.Credits) = %d\n\n",
+		unsafe.Sizeof(p), unsafe.Alignof(p.ID), unsafe.Offsetof(p.ID), unsafe.Offsetof(p.Credits))
+
+	// The slice from main: make([]int, 3, 4) and three assignments.
+	s := make([]int, 3, 4)
+	s[0], s[1], s[2] = 10, 20, 30
+	layout.Slice(s).WriteDiagram(w, "s")
+	fmt.Fprintf(w, "  %%p of s prints %p, the data pointer; &s[0] is %p, the same address\n", s, &s[0])
+	s = append(s, 40)
+	s = append(s, 50)
+	fmt.Fprintln(w, "\nafter append(s, 40) and append(s, 50) the capacity ran out, so the data moved:")
+	layout.Slice(s).WriteDiagram(w, "s")
+	fmt.Fprintln(w)
+
+	name := "Alice"
+	layout.String(name).WriteDiagram(w, "name", name)
+	fmt.Fprintln(w)
+
+	students := map[string]int{"Alice": 30, "Bob": 25}
+	layout.WriteMap(w, "students", students)
+	fmt.Fprintln(w)
+
+	twoD := [2][3]int{{1, 2, 3}, {4, 5, 6}}
+	return layout.WriteArray(w, "TwoDArray", twoD)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/amiiralihassanpour/golang_learning/runner"
+)
+
+func init() {
+	register(&command{
+		name:    "lessons",
+		summary: "run the lessons and count their allocations and GC cycles",
+		run:     runLessons,
+	})
+}
+
+func runLessons(args []string) error {
+	fs := flag.NewFlagSet("lessons", flag.ContinueOnError)
+	runs := fs.Int("runs", 100, "repetitions for testing.AllocsPerRun (0 to skip)")
+	lines := fs.Bool("memprofile", false, "attribute allocations to source lines")
+	top := fs.Int("top", 10, "allocating lines to show per lesson (0 for all)")
+	quiet := fs.Bool("q", false, "discard the output of the lessons")
+	list := fs.Bool("list", false, "list the lessons and exit")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic lessons [flags] [lesson...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		for _, l := range lessons {
+			fmt.Println(l.Name)
+		}
+		return nil
+	}
+	selected := lessons
+	if fs.NArg() > 0 {
+		selected = nil
+		for _, name := range fs.Args() {
+			i := slices.IndexFunc(lessons, func(l runner.Lesson) bool { return l.Name == name })
+			if i < 0 {
+				return fmt.Errorf("no lesson %q; -list shows them", name)
+			}
+			selected = append(selected, lessons[i])
+		}
+	}
+
+	stdout := os.Stdout
+	if *quiet {
+		null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		defer null.Close()
+		os.Stdout = null
+	}
+	reports := runner.Run(selected, runner.Options{Runs: *runs, Lines: *lines})
+	os.Stdout = stdout
+
+	fmt.Println()
+	runner.WriteReport(os.Stdout, reports, *top)
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/amiiralihassanpour/golang_learning/life"
+)
+
+func init() {
+	register(&command{
+		name:    "life",
+		summary: "play Conway's Game of Life in the terminal, or record it as a GIF",
+		run:     runLife,
+	})
+}
+
+func runLife(args []string) error {
+	const usage = "usage: basic life run [flags] pattern\n       basic life gif [flags] pattern"
+	if len(args) == 0 || args[0] != "run" && args[0] != "gif" {
+		return errors.New(usage)
+	}
+	return lifeRun(args[0], args[1:])
+}
+
+// loadPattern reads a pattern file, or a built-in pattern by name.
+func loadPattern(name string) (*life.Pattern, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		p, err := life.Builtin(name)
+		if err != nil {
+			return nil, fmt.Errorf("no pattern file or built-in pattern %q (built-in: %s)", name, strings.Join(life.Builtins(), ", "))
+		}
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return life.Parse(bytes.NewReader(data))
+}
+
+func lifeRun(mode string, args []string) error {
+	fs := flag.NewFlagSet("life "+mode, flag.ContinueOnError)
+	w := fs.Int("w", 0, "grid width (default: room round the pattern)")
+	h := fs.Int("h", 0, "grid height (default: room round the pattern)")
+	torus := fs.Bool("torus", false, "wrap around the edges instead of treating them as dead")
+	gens := fs.Int("gens", 200, "generations to run")
+	workers := fs.Int("workers", runtime.GOMAXPROCS(0), "goroutines computing each generation")
+	rule := fs.String("rule", "", "override the pattern's rule, such as B36/S23")
+	delay := fs.Duration("delay", 50*time.Millisecond, "pause between generations (run)")
+	scale := fs.Int("scale", 4, "pixels per cell (gif)")
+	out := fs.String("o", "life.gif", "output file (gif)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: basic life %s [flags] pattern\n\nbuilt-in patterns: %s\n\n", mode, strings.Join(life.Builtins(), ", "))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("want one pattern")
+	}
+	p, err := loadPattern(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *w <= 0 {
+		*w = max(p.W+20, 60)
+	}
+	if *h <= 0 {
+		*h = max(p.H+20, 40)
+	}
+	topology := life.Bounded
+	if *torus {
+		topology = life.Torus
+	}
+	g := life.New(*w, *h, topology)
+	g.Rule = p.Rule
+	if *rule != "" {
+		if g.Rule, err = life.ParseRule(*rule); err != nil {
+			return err
+		}
+	}
+	g.Center(p)
+
+	if mode == "gif" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		err = life.WriteGIF(f, g, *gens, life.GIFOptions{Scale: *scale, Delay: int(*delay / (10 * time.Millisecond)), Workers: *workers})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			fmt.Printf("wrote %d generations of %s to %s\n", *gens, p.Name, *out)
+		}
+		return err
+	}
+
+	// On a terminal each generation is drawn over the last; anywhere else
+	// only the final one is printed.
+	fi, err := os.Stdout.Stat()
+	tty := err == nil && fi.Mode()&os.ModeCharDevice != 0
+	status := func() {
+		fmt.Printf("%s, %s: generation %d, population %d\n", p.Name, g.Rule, g.Generation, g.Population())
+	}
+	if tty {
+		fmt.Print("\x1b[2J\x1b[?25l")
+		defer fmt.Print("\x1b[?25h")
+	}
+	for i := 0; ; i++ {
+		if tty {
+			fmt.Print("\x1b[H")
+			status()
+			if err := g.Render(os.Stdout); err != nil {
+				return err
+			}
+		}
+		if i == *gens {
+			break
+		}
+		g.Step(*workers)
+		if tty {
+			time.Sleep(*delay)
+		}
+	}
+	if !tty {
+		status()
+		fmt.Print(g)
+	}
+	return nil
+}
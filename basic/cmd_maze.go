@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/amiiralihassanpour/golang_learning/maze"
+)
+
+func init() {
+	register(&command{
+		name:    "maze",
+		summary: "generate and solve mazes, drawn as text or PNG, and compare the algorithms",
+		run:     runMaze,
+	})
+}
+
+func runMaze(args []string) error {
+	const usage = "usage: basic maze gen [flags]\n       basic maze stats [-w width] [-h height] [-n mazes]"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "gen":
+		return mazeGen(args[1:])
+	case "stats":
+		return mazeStats(args[1:])
+	}
+	return errors.New(usage)
+}
+
+func generatorNames() string {
+	var names []string
+	for _, g := range maze.Generators {
+		names = append(names, g.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func solverNames() string {
+	var names []string
+	for _, s := range maze.Solvers {
+		names = append(names, s.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func mazeGen(args []string) error {
+	fs := flag.NewFlagSet("maze gen", flag.ContinueOnError)
+	w := fs.Int("w", 20, "width in cells")
+	h := fs.Int("h", 10, "height in cells")
+	algo := fs.String("algo", "backtracker", "generator: "+generatorNames())
+	seed := fs.Uint64("seed", 0, "random seed (default: random)")
+	solve := fs.String("solve", "", "solver for a path from the top left to the bottom right: "+solverNames())
+	format := fs.String("format", "unicode", "ascii, unicode or png")
+	cell := fs.Int("cell", 12, "pixels per cell (png)")
+	out := fs.String("o", "maze.png", "output file (png)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *w < 1 || *h < 1 {
+		return fmt.Errorf("a maze needs at least one cell, not %dx%d", *w, *h)
+	}
+	gen, ok := maze.Lookup(*algo)
+	if !ok {
+		return fmt.Errorf("unknown generator %q (want %s)", *algo, generatorNames())
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	m := maze.Generate(gen, *w, *h, *seed)
+
+	var sol maze.Solution
+	status := fmt.Sprintf("%s %dx%d, seed %d", gen.Name, *w, *h, *seed)
+	if *solve != "" {
+		s, ok := maze.LookupSolver(*solve)
+		if !ok {
+			return fmt.Errorf("unknown solver %q (want %s)", *solve, solverNames())
+		}
+		var err error
+		sol, err = s.Solve(m, maze.Point{}, maze.Point{X: *w - 1, Y: *h - 1})
+		if err != nil {
+			return err
+		}
+		status += fmt.Sprintf(": %s path of %d steps, %d cells explored", s.Name, len(sol.Path)-1, sol.Explored)
+	}
+
+	switch *format {
+	case "ascii", "unicode":
+		fmt.Println(status)
+		style := maze.ASCII
+		if *format == "unicode" {
+			style = maze.Unicode
+		}
+		return m.Render(os.Stdout, style, sol.Path)
+	case "png":
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		err = maze.WritePNG(f, m, sol.Path, maze.PNGOptions{Cell: *cell})
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			fmt.Printf("wrote %s to %s\n", status, *out)
+		}
+		return err
+	}
+	return fmt.Errorf("unknown format %q (want ascii, unicode or png)", *format)
+}
+
+// mazeStats compares the texture of the generators, and the work of the
+// solvers on their mazes, averaged over n mazes of each.
+func mazeStats(args []string) error {
+	fs := flag.NewFlagSet("maze stats", flag.ContinueOnError)
+	w := fs.Int("w", 40, "width in cells")
+	h := fs.Int("h", 25, "height in cells")
+	n := fs.Int("n", 50, "mazes per generator")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *w < 1 || *h < 1 || *n < 1 {
+		return errors.New("width, height and count must be positive")
+	}
+	from, to := maze.Point{}, maze.Point{X: *w - 1, Y: *h - 1}
+
+	fmt.Printf("averages over %d mazes of %dx%d, solved from corner to corner\n", *n, *w, *h)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := "generator\tgen time\tdead ends\tpath"
+	for _, s := range maze.Solvers {
+		header += "\t" + s.Name + " explored"
+	}
+	fmt.Fprintln(tw, header+"\t")
+	for _, gen := range maze.Generators {
+		var elapsed time.Duration
+		deadEnds, path := 0, 0
+		explored := make([]int, len(maze.Solvers))
+		for seed := range uint64(*n) {
+			start := time.Now()
+			m := maze.Generate(gen, *w, *h, seed+1)
+			elapsed += time.Since(start)
+			deadEnds += m.DeadEnds()
+			for i, s := range maze.Solvers {
+				sol, err := s.Solve(m, from, to)
+				if err != nil {
+					return fmt.Errorf("%s, seed %d: %v", gen.Name, seed+1, err)
+				}
+				explored[i] += sol.Explored
+				if i == 0 {
+					path += len(sol.Path) - 1
+				}
+			}
+		}
+		avg := func(total int) float64 { return float64(total) / float64(*n) }
+		fmt.Fprintf(tw, "%s\t%v\t%.0f%%\t%.0f", gen.Name, (elapsed / time.Duration(*n)).Round(time.Microsecond), 100*avg(deadEnds)/float64(*w**h), avg(path))
+		for _, e := range explored {
+			fmt.Fprintf(tw, "\t%.0f", avg(e))
+		}
+		fmt.Fprintln(tw, "\t")
+	}
+	return tw.Flush()
+}
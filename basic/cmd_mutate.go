@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/amiiralihassanpour/golang_learning/mutate"
+)
+
+func init() {
+	register(&command{
+		name:    "mutate",
+		summary: "check that the tests notice small changes to the code",
+		run:     runMutate,
+	})
+}
+
+func runMutate(args []string) error {
+	fs := flag.NewFlagSet("mutate", flag.ContinueOnError)
+	workers := fs.Int("j", runtime.GOMAXPROCS(0), "mutants tested in parallel")
+	timeout := fs.Duration("timeout", time.Minute, "time limit for the tests of one mutant")
+	verbose := fs.Bool("v", false, "print every mutant as it finishes")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic mutate [-j n] [-timeout d] [-v] [packages]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts := mutate.Options{Patterns: fs.Args(), Workers: *workers, Timeout: *timeout}
+	if *verbose {
+		opts.Progress = os.Stderr
+	}
+	report, err := mutate.Run(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range report.Results {
+		if res.Status != mutate.Survived {
+			continue
+		}
+		m := res.Mutant
+		fmt.Printf("survived #%d %s:%d:%d: %s\n%s\n", m.ID, m.File, m.Line, m.Col, m.Desc, res.Diff)
+	}
+	for _, pkg := range report.NoTests {
+		fmt.Printf("no tests: %s\n", pkg)
+	}
+	fmt.Printf("mutants: %d, killed: %d, timed out: %d, survived: %d, invalid: %d\n",
+		len(report.Results), report.Count(mutate.Killed), report.Count(mutate.TimedOut),
+		report.Count(mutate.Survived), report.Count(mutate.Invalid))
+	fmt.Printf("mutation score: %.1f%%\n", 100*report.Score())
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/amiiralihassanpour/golang_learning/serial"
+)
+
+func init() {
+	register(&command{
+		name:    "serial",
+		summary: "compare JSON, gob, encoding/binary and protobuf on the students data",
+		run:     runSerial,
+	})
+}
+
+func runSerial(args []string) error {
+	fs := flag.NewFlagSet("serial", flag.ContinueOnError)
+	n := fs.Int("n", 1000, "number of generated students added to the lesson's map")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data := map[string]int{"Alice": 30, "Bob": 25}
+	for i := 0; i < *n; i++ {
+		data[fmt.Sprintf("student%05d", i)] = 18 + i%60
+	}
+	people := serial.FromMap(data)
+	fmt.Printf("%d records\n\n", len(people))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "format\tbytes\tbytes/record")
+	for _, f := range serial.Formats {
+		size, err := serial.RoundTrip(f, people)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.1f\n", f.Name, size, float64(size)/float64(len(people)))
+	}
+	w.Flush()
+
+	fmt.Println("\nschema evolution (adding an Email field):")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "format\told reader, new data\tnew reader, old data")
+	for _, f := range serial.Formats {
+		ev := serial.CheckEvolution(f, people)
+		fmt.Fprintf(w, "%s\t%s\t%s\n", ev.Format, outcome(ev.OldReadsNew), outcome(ev.NewReadsOld))
+	}
+	return w.Flush()
+}
+
+func outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return "FAIL: " + err.Error()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amiiralihassanpour/golang_learning/sorting"
+)
+
+func init() {
+	register(&command{
+		name:    "sort",
+		summary: "animate sorting algorithms in the terminal",
+		run:     runSort,
+	})
+}
+
+func runSort(args []string) error {
+	const usage = "usage: basic sort show [-algo name] [-shape name] [-n size] [-delay d]"
+	if len(args) == 0 || args[0] != "show" {
+		return errors.New(usage)
+	}
+	return sortShow(args[1:])
+}
+
+func joinNames[T any](items []T, name func(T) string) string {
+	var s []string
+	for _, it := range items {
+		s = append(s, name(it))
+	}
+	return strings.Join(s, ", ")
+}
+
+// maxRows bounds -height, as maxColumns bounds -n: a chart any bigger
+// would not fit on a screen.
+const maxRows = 200
+
+func sortShow(args []string) error {
+	fs := flag.NewFlagSet("sort show", flag.ContinueOnError)
+	algo := fs.String("algo", "quick", "algorithm: "+joinNames(sorting.Algorithms, func(a sorting.Algorithm) string { return a.Name }))
+	shape := fs.String("shape", "random", "input: "+joinNames(sorting.Shapes, func(s sorting.Shape) string { return s.Name }))
+	n := fs.Int("n", 40, "number of elements")
+	height := fs.Int("height", 15, "rows in the chart")
+	delay := fs.Duration("delay", 30*time.Millisecond, "pause between frames on a terminal")
+	every := fs.Int("every", 0, "draw every nth step (default: about 300 frames in all)")
+	seed := fs.Uint64("seed", 1, "seed for the random input")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *n < 1 || *n > maxColumns {
+		return fmt.Errorf("-n must be between 1 and %d, got %d", maxColumns, *n)
+	}
+	if *height < 1 || *height > maxRows {
+		return fmt.Errorf("-height must be between 1 and %d, got %d", maxRows, *height)
+	}
+	alg, ok := sorting.Lookup(*algo)
+	if !ok {
+		return fmt.Errorf("no algorithm %q", *algo)
+	}
+	sh, ok := sorting.LookupShape(*shape)
+	if !ok {
+		return fmt.Errorf("no input shape %q", *shape)
+	}
+	data := sh.Make(*n, rand.New(rand.NewPCG(*seed, 0)))
+	steps := sorting.Record(alg.Sort, data)
+	if *every <= 0 {
+		*every = max(1, len(steps)/300)
+	}
+
+	// On a terminal each frame is drawn over the last; anywhere else the
+	// frames are printed one after another, without colour or pauses.
+	fi, err := os.Stdout.Stat()
+	tty := err == nil && fi.Mode()&os.ModeCharDevice != 0
+	clearLine := ""
+	if tty {
+		// Clear the screen and hide the cursor while animating.
+		fmt.Print("\x1b[2J\x1b[?25l")
+		defer fmt.Print("\x1b[?25h")
+		clearLine = "\x1b[K"
+	}
+	frame := func(f sorting.Frame, step *sorting.Step) error {
+		if tty {
+			fmt.Print("\x1b[H")
+		}
+		fmt.Printf("%s sort, %d %s elements: step %d of %d%s\n", alg.Name, *n, sh.Name, f.N, len(steps), clearLine)
+		if err := sorting.Render(os.Stdout, f.Data, step, *height, tty); err != nil {
+			return err
+		}
+		fmt.Printf("%s%s\n", f.Counts, clearLine)
+		if tty {
+			time.Sleep(*delay)
+		} else {
+			fmt.Println()
+		}
+		return nil
+	}
+	last := sorting.Frame{Data: data}
+	for f := range sorting.Replay(data, steps) {
+		last = f
+		if f.N%*every == 0 && f.N < len(steps) {
+			if err := frame(f, &f.Step); err != nil {
+				return err
+			}
+		}
+	}
+	return frame(last, nil)
+}
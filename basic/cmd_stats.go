@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/stats"
+)
+
+func init() {
+	register(&command{
+		name:    "stats",
+		summary: "describe a column of numbers with statistics, a histogram and a sparkline",
+		run:     runStats,
+	})
+}
+
+func runStats(args []string) error {
+	const usage = "usage: basic stats describe [-bins n] [-width n] [file]"
+	if len(args) == 0 || args[0] != "describe" {
+		return errors.New(usage)
+	}
+	return statsDescribe(args[1:])
+}
+
+// readNumbers reads numbers separated by spaces, commas or semicolons.
+func readNumbers(r io.Reader) ([]float64, error) {
+	var xs []float64
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.FieldsFunc(sc.Text(), func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t'
+		})
+		for _, f := range fields {
+			x, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %q is not a number", line, f)
+			}
+			xs = append(xs, x)
+		}
+	}
+	return xs, sc.Err()
+}
+
+// maxColumns bounds -bins and -width: a histogram taller or a bar wider
+// than this would not fit on any screen.
+const maxColumns = 1000
+
+func statsDescribe(args []string) error {
+	fs := flag.NewFlagSet("stats describe", flag.ContinueOnError)
+	bins := fs.Int("bins", 0, "histogram bins (default: Sturges' rule)")
+	width := fs.Int("width", 50, "width of the longest histogram bar and of the sparkline")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic stats describe [flags] [file]\n\nReads numbers separated by spaces, commas or semicolons from the file, or from standard input.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bins < 0 || *bins > maxColumns {
+		return fmt.Errorf("-bins must be between 0 and %d, got %d", maxColumns, *bins)
+	}
+	if *width < 1 || *width > maxColumns {
+		return fmt.Errorf("-width must be between 1 and %d, got %d", maxColumns, *width)
+	}
+	in, name := io.Reader(os.Stdin), "standard input"
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in, name = f, fs.Arg(0)
+	}
+	xs, err := readNumbers(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(xs) == 0 {
+		return fmt.Errorf("%s: no numbers", name)
+	}
+
+	s := stats.Describe(xs)
+	fmt.Printf("n %d, mean %.6g, standard deviation %.6g\n", s.N, s.Mean, s.StdDev)
+	fmt.Printf("min %.6g, quartiles %.6g %.6g %.6g, max %.6g\n", s.Min, s.Q1, s.Median, s.Q3, s.Max)
+	if modes, count := stats.Mode(xs); count > 1 && len(modes) <= 5 {
+		fmt.Printf("mode %v, %d times\n", modes, count)
+	}
+
+	// The t-digest's estimates next to the exact quantiles show how close
+	// a few dozen centroids come.
+	td := stats.NewTDigest(100)
+	for _, x := range xs {
+		td.Add(x)
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	fmt.Printf("\n%8s %12s %12s   (t-digest: %d centroids)\n", "quantile", "exact", "t-digest", td.Centroids())
+	for _, q := range []float64{0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99} {
+		fmt.Printf("%8v %12.6g %12.6g\n", q, stats.QuantileSorted(sorted, q), td.Quantile(q))
+	}
+
+	fmt.Println()
+	if err := stats.NewHistogram(xs, *bins).Write(os.Stdout, *width); err != nil {
+		return err
+	}
+
+	// Draw long inputs as the means of width equal runs of values.
+	line := xs
+	if len(xs) > *width {
+		line = make([]float64, *width)
+		for i := range line {
+			line[i] = stats.Mean(xs[i*len(xs) / *width : (i+1)*len(xs) / *width])
+		}
+	}
+	fmt.Printf("\nin order: %s\n", stats.Sparkline(line))
+	return nil
+}
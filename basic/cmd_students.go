@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amiiralihassanpour/golang_learning/students"
+)
+
+func init() {
+	register(&command{
+		name:    "students",
+		summary: "import the students map from CSV, or keep it in a record file between runs",
+		run:     runStudents,
+	})
+}
+
+func runStudents(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: basic students import [flags] file.csv | basic students save [-f file.db] name=age... | basic students dump [file.db]")
+	}
+	switch args[0] {
+	case "import":
+		return runStudentsImport(args[1:])
+	case "save":
+		return runStudentsSave(args[1:])
+	case "dump":
+		return runStudentsDump(args[1:])
+	}
+	return fmt.Errorf("unknown subcommand %q", args[0])
+}
+
+func runStudentsImport(args []string) error {
+	fs := flag.NewFlagSet("students import", flag.ContinueOnError)
+	dup := fs.String("dup", "overwrite", "policy for repeated names: overwrite, skip or fail")
+	comma := fs.String("comma", ",", "field separator")
+	out := fs.String("o", "", "also save the imported map to this record `file`")
+	opts := students.Options{Headers: map[string]string{}}
+	fs.Func("map", "map a header to a field, e.g. -map 'Student Name=name' (repeatable)", func(s string) error {
+		from, to, ok := strings.Cut(s, "=")
+		if !ok || (to != "name" && to != "age") {
+			return fmt.Errorf("want HEADER=name or HEADER=age, got %q", s)
+		}
+		opts.Headers[from] = to
+		return nil
+	})
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic students import [flags] file.csv")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one file")
+	}
+	var err error
+	if opts.Duplicates, err = students.ParseDuplicatePolicy(*dup); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(*comma) != 1 {
+		return fmt.Errorf("-comma must be a single character, got %q", *comma)
+	}
+	opts.Comma, _ = utf8.DecodeRuneInString(*comma)
+
+	name := fs.Arg(0)
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	res, err := students.Import(f, opts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, e := range res.Errors {
+		fmt.Fprintf(os.Stderr, "%s:%v\n", name, e)
+	}
+	fmt.Println("Map:", res.Students)
+	for _, student := range students.SortedNames(res.Students) {
+		fmt.Printf("Key: %s, Value: %d\n", student, res.Students[student])
+	}
+	s := res.Summary
+	fmt.Printf("\nrows: %d, imported: %d, overwritten: %d, skipped: %d, invalid: %d\n",
+		s.Rows, s.Imported, s.Overwritten, s.Skipped, s.Invalid)
+	if s.Imported > 0 {
+		fmt.Printf("age: min %d, max %d, mean %.1f\n", s.MinAge, s.MaxAge, s.MeanAge)
+	}
+	if *out != "" {
+		return students.SaveFile(*out, res.Students)
+	}
+	return nil
+}
+
+// runStudentsSave adds name=age pairs to the map saved by the previous
+// run, or deletes a name given as name=, and saves the result.
+func runStudentsSave(args []string) error {
+	fs := flag.NewFlagSet("students save", flag.ContinueOnError)
+	path := fs.String("f", students.DefaultPath(), "record `file` to update")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic students save [-f file.db] name=age... (name= deletes)")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	m, err := students.LoadFile(*path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		m = map[string]int{}
+	case err != nil:
+		return err
+	default:
+		fmt.Println("Map saved by the previous run:", m)
+	}
+	for _, arg := range fs.Args() {
+		name, age, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("want name=age, got %q", arg)
+		}
+		if age == "" {
+			delete(m, name)
+			continue
+		}
+		n, err := strconv.Atoi(age)
+		if err != nil {
+			return fmt.Errorf("%s: age %q is not a number", name, age)
+		}
+		m[name] = n
+	}
+	if err := students.SaveFile(*path, m); err != nil {
+		return err
+	}
+	fmt.Println("Map saved to", *path+":", m)
+	return nil
+}
+
+// runStudentsDump prints a record file written by import -o or by save
+// (default: save's file).
+func runStudentsDump(args []string) error {
+	path := students.DefaultPath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+	m, err := students.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	fmt.Println(path)
+	for _, name := range students.SortedNames(m) {
+		fmt.Printf("Key: %s, Value: %d\n", name, m[name])
+	}
+	return nil
+}
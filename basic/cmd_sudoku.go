@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/amiiralihassanpour/golang_learning/sudoku"
+)
+
+func init() {
+	register(&command{
+		name:    "sudoku",
+		summary: "solve, rate and generate Sudoku puzzles",
+		run:     runSudoku,
+	})
+}
+
+func runSudoku(args []string) error {
+	const usage = "usage: basic sudoku solve [-backtrack] [-j n] puzzle|file|-\n" +
+		"       basic sudoku gen [-n count] [-difficulty level] [-symmetric] [-seed n]"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch args[0] {
+	case "solve":
+		return sudokuSolve(args[1:])
+	case "gen":
+		return sudokuGen(args[1:])
+	}
+	return errors.New(usage)
+}
+
+// readSudokus reads puzzles from an argument: a puzzle itself, a file of
+// puzzles, or - for standard input.
+func readSudokus(arg string) ([]sudoku.Puzzle, error) {
+	if g, err := sudoku.Parse(arg); err == nil {
+		return []sudoku.Puzzle{{Name: "puzzle", Grid: g}}, nil
+	}
+	var r io.Reader = os.Stdin
+	if arg != "-" {
+		f, err := os.Open(arg)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return sudoku.ReadPuzzles(r)
+}
+
+func sudokuSolve(args []string) error {
+	fs := flag.NewFlagSet("sudoku solve", flag.ContinueOnError)
+	backtrack := fs.Bool("backtrack", false, "use plain backtracking instead of constraint propagation")
+	workers := fs.Int("j", runtime.GOMAXPROCS(0), "goroutines for a file of puzzles")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: basic sudoku solve [-backtrack] [-j n] puzzle|file|-")
+	}
+	puzzles, err := readSudokus(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if len(puzzles) == 1 {
+		p := puzzles[0].Grid
+		solve := sudoku.Solve
+		if *backtrack {
+			solve = sudoku.Backtrack
+		}
+		start := time.Now()
+		g, stats, err := solve(p)
+		elapsed := time.Since(start)
+		if err != nil {
+			return err
+		}
+		fmt.Print(g.Format())
+		fmt.Printf("%d clues, %s, %d guesses, %d dead ends, %v\n", p.Clues(), sudoku.Rate(p), stats.Guesses, stats.DeadEnds, elapsed.Round(time.Microsecond))
+		if n := sudoku.CountSolutions(p, 2); n > 1 {
+			fmt.Println("warning: the puzzle has more than one solution")
+		}
+		return nil
+	}
+
+	// A batch prints one line per puzzle, in order.
+	grids := make([]sudoku.Grid, len(puzzles))
+	for i, p := range puzzles {
+		grids[i] = p.Grid
+	}
+	start := time.Now()
+	results := sudoku.SolveAll(grids, *workers)
+	elapsed := time.Since(start)
+	failed := 0
+	for i, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Printf("%s  %s: %v\n", grids[i], puzzles[i].Name, r.Err)
+			continue
+		}
+		fmt.Printf("%s  %s\n", r.Solution, puzzles[i].Name)
+	}
+	fmt.Fprintf(os.Stderr, "solved %d of %d puzzles in %v with %d workers\n", len(results)-failed, len(results), elapsed.Round(time.Microsecond), *workers)
+	if failed > 0 {
+		return fmt.Errorf("%d puzzles have no solution", failed)
+	}
+	return nil
+}
+
+func sudokuGen(args []string) error {
+	fs := flag.NewFlagSet("sudoku gen", flag.ContinueOnError)
+	n := fs.Int("n", 1, "number of puzzles")
+	level := fs.String("difficulty", "", "easy, medium, hard or expert (default: any)")
+	symmetric := fs.Bool("symmetric", false, "remove clues in pairs symmetric about the centre")
+	seed := fs.Uint64("seed", 0, "random seed (default: random)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts := sudoku.GenOptions{Symmetric: *symmetric}
+	if *level != "" {
+		d, err := sudoku.ParseDifficulty(*level)
+		if err != nil {
+			return err
+		}
+		opts.Difficulty = d
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	r := rand.New(rand.NewPCG(*seed, 0))
+	for i := range *n {
+		g, err := sudoku.Generate(r, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s-%d\n", g, sudoku.Rate(g), i+1)
+	}
+	return nil
+}
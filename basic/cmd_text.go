@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/amiiralihassanpour/golang_learning/text"
+)
+
+func init() {
+	register(&command{
+		name:    "text",
+		summary: "show bytes, runes and characters of strings",
+		run:     runText,
+	})
+}
+
+func runText(args []string) error {
+	fs := flag.NewFlagSet("text", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic text string...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	for i, s := range fs.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		describeText(s)
+	}
+	return nil
+}
+
+// describeText prints s the three ways Go can see it: bytes, runes and
+// grapheme clusters.
+func describeText(s string) {
+	fmt.Printf("%q\n", s)
+	fmt.Printf("  %d bytes, %d runes, %d characters, %d columns, %s\n",
+		len(s), len([]rune(s)), text.GraphemeCount(s), text.Width(s), text.Dir(s))
+	fmt.Println("  runes:")
+	for _, r := range text.Runes(s) {
+		hex := make([]string, len(r.Bytes))
+		for i, b := range r.Bytes {
+			hex[i] = fmt.Sprintf("%02x", b)
+		}
+		fmt.Printf("    %3d  %-12s %U %s\n", r.Offset, strings.Join(hex, " "), r.Rune, runeName(r.Rune))
+	}
+	fmt.Println("  characters:")
+	for _, g := range text.Graphemes(s) {
+		fmt.Printf("    %q (%d runes, %d columns)\n", g, len([]rune(g)), text.GraphemeWidth(g))
+	}
+	fmt.Printf("  reversed:    %s\n", text.Reverse(s))
+	if nfc, nfd := text.NFC(s), text.NFD(s); nfc != s || nfd != s {
+		fmt.Printf("  NFC: %d bytes, NFD: %d bytes\n", len(nfc), len(nfd))
+	}
+	if p := text.NormalizePersian(s); p != text.NFC(s) && strings.ContainsFunc(s, func(r rune) bool { return unicode.Is(unicode.Arabic, r) }) {
+		fmt.Printf("  normalised Persian: %s\n", p)
+	}
+}
+
+// runeName describes the kind of rune by its Unicode category, since the
+// standard library has no table of names.
+func runeName(r rune) string {
+	switch {
+	case unicode.Is(unicode.Mn, r):
+		return "combining mark"
+	case r == 0x200C:
+		return "zero width non-joiner"
+	case r == 0x200D:
+		return "zero width joiner"
+	case unicode.IsLetter(r):
+		for _, script := range []string{"Latin", "Arabic", "Han", "Hangul", "Cyrillic", "Greek", "Hiragana", "Katakana"} {
+			if unicode.Is(unicode.Scripts[script], r) {
+				return script + " letter"
+			}
+		}
+		return "letter"
+	case unicode.IsDigit(r):
+		return "digit"
+	case unicode.IsSpace(r):
+		return "space"
+	case unicode.IsControl(r):
+		return "control"
+	case unicode.IsPunct(r):
+		return "punctuation"
+	case unicode.IsSymbol(r):
+		return "symbol"
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/amiiralihassanpour/golang_learning/wordfreq"
+)
+
+func init() {
+	register(&command{
+		name:    "wordfreq",
+		summary: "count word frequencies in files with sharded maps",
+		run:     runWordfreq,
+	})
+}
+
+func runWordfreq(args []string) error {
+	fs := flag.NewFlagSet("wordfreq", flag.ContinueOnError)
+	n := fs.Int("n", 20, "number of words to print (0 for all)")
+	workers := fs.Int("j", runtime.GOMAXPROCS(0), "counting goroutines (1 counts single-threaded)")
+	shards := fs.Int("shards", wordfreq.DefaultShards, "map shards per worker")
+	fold := fs.Bool("fold", true, "ignore case, with Unicode case folding")
+	stop := fs.String("stop", "", `stop words to skip: "en" for the built-in English list, or a file`)
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: basic wordfreq [flags] [file ...]")
+		fmt.Fprintln(fs.Output(), "With no files, or a file named -, standard input is read.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := wordfreq.Options{Workers: *workers, Shards: *shards, Fold: *fold}
+	switch *stop {
+	case "":
+	case "en":
+		opts.Stop = wordfreq.English()
+	default:
+		f, err := os.Open(*stop)
+		if err != nil {
+			return err
+		}
+		opts.Stop, err = wordfreq.ReadStopWords(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", *stop, err)
+		}
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	c := wordfreq.New(opts)
+	for _, name := range files {
+		if err := countFile(c, name); err != nil {
+			return err
+		}
+	}
+	top := c.Top(*n)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Files   []string         `json:"files"`
+			Words   int              `json:"words"`
+			Unique  int              `json:"unique"`
+			Stopped int              `json:"stopped"`
+			Top     []wordfreq.Entry `json:"top"`
+		}{files, c.Total(), c.Unique(), c.Stopped(), top})
+	}
+	fmt.Printf("%d words, %d different", c.Total(), c.Unique())
+	if c.Stopped() > 0 {
+		fmt.Printf(", %d stop words skipped", c.Stopped())
+	}
+	fmt.Println()
+	for i, e := range top {
+		fmt.Printf("%4d  %8d  %s\n", i+1, e.Count, e.Word)
+	}
+	return nil
+}
+
+func countFile(c *wordfreq.Counter, name string) error {
+	if name == "-" {
+		return c.Add(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := c.Add(f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
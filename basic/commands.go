@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of the basic binary, e.g. `basic json file.json`.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]*command{}
+
+// register adds a subcommand. Each command file calls it from its init().
+func register(c *command) {
+	commands[c.name] = c
+}
+
+// runCommand dispatches args[0] to the matching subcommand and returns the
+// process exit code.
+func runCommand(args []string) int {
+	c, ok := commands[args[0]]
+	if !ok {
+		if args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			fmt.Fprintf(os.Stderr, "basic: unknown command %q\n\n", args[0])
+		}
+		printUsage()
+		return 2
+	}
+	if err := c.run(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "basic %s: %v\n", c.name, err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: basic [command] [arguments]")
+	fmt.Fprintln(os.Stderr, "\nWithout a command the lesson demo runs. Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
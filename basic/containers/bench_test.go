@@ -0,0 +1,169 @@
+package containers
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Each benchmark times a container against the slice or map code a Go
+// programmer would write instead, on benchN elements.
+const benchN = 10000
+
+var benchKeys, benchWords = func() ([]int, []string) {
+	r := rand.New(rand.NewPCG(1, 2))
+	keys := make([]int, benchN)
+	words := make([]string, benchN)
+	for i := range keys {
+		keys[i] = r.IntN(benchN * 10)
+		words[i] = strconv.FormatInt(int64(r.IntN(benchN*10)), 36)
+	}
+	return keys, words
+}()
+
+// pair runs the two sides of a comparison as sub-benchmarks.
+func pair(b *testing.B, container, builtin func()) {
+	for _, bm := range []struct {
+		name string
+		fn   func()
+	}{{"containers", container}, {"builtin", builtin}} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				bm.fn()
+			}
+		})
+	}
+}
+
+// BenchmarkQueue pushes n and pops n, against q = q[1:] on a slice.
+func BenchmarkQueue(b *testing.B) {
+	pair(b, func() {
+		var q Queue[int]
+		for _, k := range benchKeys {
+			q.Push(k)
+		}
+		for q.Len() > 0 {
+			q.Pop()
+		}
+	}, func() {
+		var q []int
+		for _, k := range benchKeys {
+			q = append(q, k)
+		}
+		for len(q) > 0 {
+			q = q[1:]
+		}
+	})
+}
+
+// BenchmarkListPushFront inserts n at the front, against slices.Insert.
+func BenchmarkListPushFront(b *testing.B) {
+	pair(b, func() {
+		var l List[int]
+		for _, k := range benchKeys {
+			l.PushFront(k)
+		}
+	}, func() {
+		var s []int
+		for _, k := range benchKeys {
+			s = slices.Insert(s, 0, k)
+		}
+	})
+}
+
+// BenchmarkHeap pushes n and pops them in order, against sort.Ints.
+func BenchmarkHeap(b *testing.B) {
+	pair(b, func() {
+		h := NewMinHeap[int]()
+		for _, k := range benchKeys {
+			h.Push(k)
+		}
+		for range h.Drain() {
+		}
+	}, func() {
+		s := slices.Clone(benchKeys)
+		sort.Ints(s)
+	})
+}
+
+// BenchmarkTree puts n and walks them in order, against a map and its
+// sorted keys.
+func BenchmarkTree(b *testing.B) {
+	pair(b, func() {
+		t := NewOrderedTree[int, int]()
+		for i, k := range benchKeys {
+			t.Put(k, i)
+		}
+		for range t.All() {
+		}
+	}, func() {
+		m := map[int]int{}
+		for i, k := range benchKeys {
+			m[k] = i
+		}
+		for range slices.Sorted(maps.Keys(m)) {
+		}
+	})
+}
+
+// BenchmarkTreeRange counts the keys in 100 ranges of 1% of the key space
+// each, against scanning a map. Both are built beforehand.
+func BenchmarkTreeRange(b *testing.B) {
+	t := NewOrderedTree[int, int]()
+	m := map[int]int{}
+	for i, k := range benchKeys {
+		t.Put(k, i)
+		m[k] = i
+	}
+	const width = benchN / 10
+	pair(b, func() {
+		for q := range 100 {
+			lo := q * benchN / 10
+			for range t.Range(lo, lo+width) {
+			}
+		}
+	}, func() {
+		for q := range 100 {
+			lo := q * benchN / 10
+			count := 0
+			for k := range m {
+				if k >= lo && k < lo+width {
+					count++
+				}
+			}
+		}
+	})
+}
+
+// BenchmarkTriePrefix finds the words with each of 100 prefixes, against
+// binary search in a sorted slice.
+func BenchmarkTriePrefix(b *testing.B) {
+	var t Trie[int]
+	for i, w := range benchWords {
+		t.Put(w, i)
+	}
+	sorted := slices.Sorted(slices.Values(benchWords))
+	prefixes := make([]string, 100)
+	for i := range prefixes {
+		w := benchWords[i*len(benchWords)/100]
+		prefixes[i] = w[:min(len(w), 2)]
+	}
+	pair(b, func() {
+		for _, p := range prefixes {
+			for range t.WithPrefix(p) {
+			}
+		}
+	}, func() {
+		for _, p := range prefixes {
+			i := sort.SearchStrings(sorted, p)
+			for i < len(sorted) && strings.HasPrefix(sorted[i], p) {
+				i++
+			}
+		}
+	})
+}
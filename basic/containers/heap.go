@@ -0,0 +1,130 @@
+package containers
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Heap is a binary heap: a priority queue whose Pop always returns the
+// smallest element by less. It is the generic form of container/heap,
+// with the slice kept inside instead of behind an interface.
+type Heap[T any] struct {
+	less func(a, b T) bool
+	// data is a complete binary tree stored level by level: the children
+	// of i are 2i+1 and 2i+2.
+	data []T
+}
+
+// NewHeap returns a heap ordered by less holding items. Building it from
+// items at once takes linear time, against n log n for pushing them one
+// by one.
+func NewHeap[T any](less func(a, b T) bool, items ...T) *Heap[T] {
+	h := &Heap[T]{less: less, data: slices.Clone(items)}
+	for i := len(h.data)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// NewMinHeap returns a heap that pops the smallest element first.
+func NewMinHeap[T cmp.Ordered](items ...T) *Heap[T] {
+	return NewHeap(cmp.Less[T], items...)
+}
+
+// NewMaxHeap returns a heap that pops the largest element first.
+func NewMaxHeap[T cmp.Ordered](items ...T) *Heap[T] {
+	return NewHeap(func(a, b T) bool { return cmp.Less(b, a) }, items...)
+}
+
+// Len returns the number of elements.
+func (h *Heap[T]) Len() int { return len(h.data) }
+
+// Push adds v.
+func (h *Heap[T]) Push(v T) {
+	h.data = append(h.data, v)
+	h.up(len(h.data) - 1)
+}
+
+// Peek returns the first element without removing it.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.data) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.data[0], true
+}
+
+// Pop removes and returns the first element.
+func (h *Heap[T]) Pop() (T, bool) {
+	v, ok := h.Peek()
+	if !ok {
+		return v, false
+	}
+	last := len(h.data) - 1
+	h.data[0] = h.data[last]
+	var zero T
+	h.data[last] = zero
+	h.data = h.data[:last]
+	h.down(0)
+	return v, true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.data[i], h.data[parent]) {
+			return
+		}
+		h.data[i], h.data[parent] = h.data[parent], h.data[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	for {
+		smallest := i
+		if l := 2*i + 1; l < len(h.data) && h.less(h.data[l], h.data[smallest]) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h.data) && h.less(h.data[r], h.data[smallest]) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h.data[i], h.data[smallest] = h.data[smallest], h.data[i]
+		i = smallest
+	}
+}
+
+// All yields the elements in storage order, which is not sorted. Drain
+// yields them in order.
+func (h *Heap[T]) All() iter.Seq[T] {
+	return slices.Values(h.data)
+}
+
+// Drain pops and yields the elements in order until the heap is empty or
+// the loop stops.
+func (h *Heap[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for len(h.data) > 0 {
+			v, _ := h.Pop()
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// checkInvariants verifies the heap property: no element is less than its
+// parent.
+func (h *Heap[T]) checkInvariants() error {
+	for i := 1; i < len(h.data); i++ {
+		if parent := (i - 1) / 2; h.less(h.data[i], h.data[parent]) {
+			return fmt.Errorf("heap: element %d (%v) is less than its parent %d (%v)", i, h.data[i], parent, h.data[parent])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+// Package containers implements the classic data structures the language
+// does not build in, as generic types: singly and doubly linked lists, a
+// stack, a queue, a balanced search tree, a trie and a binary heap.
+//
+// Every container can be iterated with range through an iter.Seq or
+// iter.Seq2. The tests check the internal invariants, such as the links
+// of a list agreeing in both directions or the balance of the tree, after
+// every step of random operation sequences.
+//
+// Slices and maps remain the right default in Go. These types are for
+// what they cannot do cheaply: removing from the middle while iterating,
+// popping from the front, iterating keys in order, or finding all keys
+// with a prefix.
+package containers
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// SList is a singly linked list. Pushing and popping at the front and
+// pushing at the back take constant time. The zero value is an empty
+// list.
+type SList[T any] struct {
+	head, tail *snode[T]
+	n          int
+}
+
+type snode[T any] struct {
+	value T
+	next  *snode[T]
+}
+
+// Len returns the number of elements.
+func (l *SList[T]) Len() int { return l.n }
+
+// PushFront adds v at the front.
+func (l *SList[T]) PushFront(v T) {
+	l.head = &snode[T]{value: v, next: l.head}
+	if l.tail == nil {
+		l.tail = l.head
+	}
+	l.n++
+}
+
+// PushBack adds v at the back.
+func (l *SList[T]) PushBack(v T) {
+	n := &snode[T]{value: v}
+	if l.tail == nil {
+		l.head = n
+	} else {
+		l.tail.next = n
+	}
+	l.tail = n
+	l.n++
+}
+
+// Front returns the first element.
+func (l *SList[T]) Front() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+	return l.head.value, true
+}
+
+// PopFront removes and returns the first element.
+func (l *SList[T]) PopFront() (T, bool) {
+	if l.head == nil {
+		var zero T
+		return zero, false
+	}
+	n := l.head
+	l.head = n.next
+	if l.head == nil {
+		l.tail = nil
+	}
+	l.n--
+	return n.value, true
+}
+
+// Reverse reverses the list in place by turning every link around.
+func (l *SList[T]) Reverse() {
+	var prev *snode[T]
+	l.tail = l.head
+	for n := l.head; n != nil; {
+		next := n.next
+		n.next = prev
+		prev, n = n, next
+	}
+	l.head = prev
+}
+
+// All yields the elements from front to back.
+func (l *SList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := l.head; n != nil; n = n.next {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// checkInvariants verifies that the length matches the links and that tail
+// is the last node.
+func (l *SList[T]) checkInvariants() error {
+	count := 0
+	var last *snode[T]
+	for n := l.head; n != nil; n = n.next {
+		if count > l.n {
+			return fmt.Errorf("slist: more than %d nodes, or a cycle", l.n)
+		}
+		count++
+		last = n
+	}
+	if count != l.n {
+		return fmt.Errorf("slist: Len is %d but there are %d nodes", l.n, count)
+	}
+	if last != l.tail {
+		return errors.New("slist: tail is not the last node")
+	}
+	return nil
+}
+
+// List is a doubly linked list, like container/list but generic. Elements
+// can be inserted, removed and moved anywhere in constant time given an
+// *Element. The zero value is an empty list.
+type List[T any] struct {
+	// root is a sentinel: root.next is the front and root.prev the back,
+	// which makes the list circular and saves nil checks.
+	root Element[T]
+	n    int
+}
+
+// Element is an element of a List.
+type Element[T any] struct {
+	Value      T
+	next, prev *Element[T]
+	list       *List[T]
+}
+
+// Next returns the next element or nil.
+func (e *Element[T]) Next() *Element[T] {
+	if n := e.next; e.list != nil && n != &e.list.root {
+		return n
+	}
+	return nil
+}
+
+// Prev returns the previous element or nil.
+func (e *Element[T]) Prev() *Element[T] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+func (l *List[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+// Len returns the number of elements.
+func (l *List[T]) Len() int { return l.n }
+
+// Front returns the first element or nil.
+func (l *List[T]) Front() *Element[T] {
+	if l.n == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last element or nil.
+func (l *List[T]) Back() *Element[T] {
+	if l.n == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.n++
+	return e
+}
+
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.next, e.prev = nil, nil
+	l.n--
+}
+
+// PushFront inserts v at the front and returns its element.
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, &l.root)
+}
+
+// PushBack inserts v at the back and returns its element.
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, l.root.prev)
+}
+
+// InsertBefore inserts v before mark, which must be an element of l.
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark.prev)
+}
+
+// InsertAfter inserts v after mark, which must be an element of l.
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark)
+}
+
+// Remove removes e from l if it is an element of l, and returns its
+// value.
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.unlink(e)
+		e.list = nil
+	}
+	return e.Value
+}
+
+// MoveToFront moves e to the front of l.
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+	l.unlink(e)
+	l.insert(e, &l.root)
+}
+
+// MoveToBack moves e to the back of l.
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list != l || l.root.prev == e {
+		return
+	}
+	l.unlink(e)
+	l.insert(e, l.root.prev)
+}
+
+// All yields the elements from front to back. The current element may be
+// removed during the iteration.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Front(); e != nil; {
+			next := e.Next()
+			if !yield(e.Value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward yields the elements from back to front.
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.Back(); e != nil; {
+			prev := e.Prev()
+			if !yield(e.Value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// checkInvariants verifies that every next link has a matching prev link,
+// that every element belongs to l and that the length matches.
+func (l *List[T]) checkInvariants() error {
+	if l.root.next == nil {
+		if l.n != 0 {
+			return fmt.Errorf("list: uninitialised but Len is %d", l.n)
+		}
+		return nil
+	}
+	count := 0
+	for e := l.root.next; e != &l.root; e = e.next {
+		if count > l.n {
+			return fmt.Errorf("list: more than %d elements, or a cycle", l.n)
+		}
+		if e.next.prev != e {
+			return fmt.Errorf("list: element %d: next.prev does not point back", count)
+		}
+		if e.list != l {
+			return fmt.Errorf("list: element %d belongs to another list", count)
+		}
+		count++
+	}
+	if l.root.next.prev != &l.root {
+		return errors.New("list: front.prev is not the sentinel")
+	}
+	if count != l.n {
+		return fmt.Errorf("list: Len is %d but there are %d elements", l.n, count)
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package containers
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/amiiralihassanpour/golang_learning/quick"
+)
+
+// op is one step of a random operation sequence: A picks the operation
+// and B is its argument. Shrinking removes steps and lowers both numbers,
+// so a failure is reported as a short sequence.
+type op = quick.Pair[int, int]
+
+func ops(kinds int) quick.Gen[[]op] {
+	return quick.SliceOf(quick.Zip(quick.IntRange(0, kinds-1), quick.IntRange(0, 40)))
+}
+
+// model is a property over operation sequences that applies them to a
+// container and to a model built from slices or maps, and returns an
+// error when the two disagree or the container breaks an invariant.
+type model struct {
+	name  string
+	kinds int
+	run   func(steps []op) error
+}
+
+// compare reports the first difference between a container's contents
+// and the model's.
+func compare[T comparable](what string, got, want []T) error {
+	if !slices.Equal(got, want) {
+		return fmt.Errorf("%s: got %v, want %v", what, got, want)
+	}
+	return nil
+}
+
+var models = []model{
+	{"SList", 4, func(steps []op) error {
+		var l SList[int]
+		var model []int
+		for _, s := range steps {
+			switch s.A {
+			case 0:
+				l.PushFront(s.B)
+				model = slices.Insert(model, 0, s.B)
+			case 1:
+				l.PushBack(s.B)
+				model = append(model, s.B)
+			case 2:
+				v, ok := l.PopFront()
+				if ok != (len(model) > 0) || ok && v != model[0] {
+					return fmt.Errorf("PopFront = %d, %t; model %v", v, ok, model)
+				}
+				if ok {
+					model = model[1:]
+				}
+			case 3:
+				l.Reverse()
+				slices.Reverse(model)
+			}
+			if err := l.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		return compare("All", slices.Collect(l.All()), model)
+	}},
+	{"List", 6, func(steps []op) error {
+		var l List[int]
+		var model []int
+		// at returns the element at position i, walking from the front.
+		at := func(i int) *Element[int] {
+			e := l.Front()
+			for range i {
+				e = e.Next()
+			}
+			return e
+		}
+		for _, s := range steps {
+			switch {
+			case s.A == 0:
+				l.PushFront(s.B)
+				model = slices.Insert(model, 0, s.B)
+			case s.A == 1:
+				l.PushBack(s.B)
+				model = append(model, s.B)
+			case len(model) == 0:
+			case s.A == 2:
+				i := s.B % len(model)
+				if v := l.Remove(at(i)); v != model[i] {
+					return fmt.Errorf("Remove at %d = %d, want %d", i, v, model[i])
+				}
+				model = slices.Delete(model, i, i+1)
+			case s.A == 3:
+				i := s.B % len(model)
+				l.InsertAfter(-s.B, at(i))
+				model = slices.Insert(model, i+1, -s.B)
+			case s.A == 4:
+				i := s.B % len(model)
+				l.MoveToFront(at(i))
+				v := model[i]
+				model = slices.Insert(slices.Delete(model, i, i+1), 0, v)
+			case s.A == 5:
+				i := s.B % len(model)
+				l.MoveToBack(at(i))
+				v := model[i]
+				model = append(slices.Delete(model, i, i+1), v)
+			}
+			if err := l.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		backward := slices.Clone(model)
+		slices.Reverse(backward)
+		if err := compare("All", slices.Collect(l.All()), model); err != nil {
+			return err
+		}
+		return compare("Backward", slices.Collect(l.Backward()), backward)
+	}},
+	{"Stack", 2, func(steps []op) error {
+		var st Stack[int]
+		var model []int
+		for _, s := range steps {
+			if s.A == 0 {
+				st.Push(s.B)
+				model = append(model, s.B)
+				continue
+			}
+			v, ok := st.Pop()
+			if ok != (len(model) > 0) || ok && v != model[len(model)-1] {
+				return fmt.Errorf("Pop = %d, %t; model %v", v, ok, model)
+			}
+			if ok {
+				model = model[:len(model)-1]
+			}
+		}
+		slices.Reverse(model)
+		return compare("All", slices.Collect(st.All()), model)
+	}},
+	{"Queue", 2, func(steps []op) error {
+		var q Queue[int]
+		var model []int
+		for _, s := range steps {
+			if s.A == 0 {
+				q.Push(s.B)
+				model = append(model, s.B)
+			} else {
+				v, ok := q.Pop()
+				if ok != (len(model) > 0) || ok && v != model[0] {
+					return fmt.Errorf("Pop = %d, %t; model %v", v, ok, model)
+				}
+				if ok {
+					model = model[1:]
+				}
+			}
+			if err := q.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		return compare("All", slices.Collect(q.All()), model)
+	}},
+	{"Heap", 2, func(steps []op) error {
+		h := NewMinHeap[int]()
+		var model []int
+		for _, s := range steps {
+			if s.A == 0 {
+				h.Push(s.B)
+				model = append(model, s.B)
+			} else {
+				v, ok := h.Pop()
+				if ok != (len(model) > 0) || ok && v != slices.Min(model) {
+					return fmt.Errorf("Pop = %d, %t; model %v", v, ok, model)
+				}
+				if ok {
+					model = slices.Delete(model, slices.Index(model, v), slices.Index(model, v)+1)
+				}
+			}
+			if err := h.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		slices.Sort(model)
+		return compare("Drain", slices.Collect(h.Drain()), model)
+	}},
+	{"Tree", 3, func(steps []op) error {
+		t := NewOrderedTree[int, int]()
+		model := map[int]int{}
+		for i, s := range steps {
+			switch s.A {
+			case 0:
+				t.Put(s.B, i)
+				model[s.B] = i
+			case 1:
+				_, had := model[s.B]
+				if t.Delete(s.B) != had {
+					return fmt.Errorf("Delete(%d) = %t, want %t", s.B, !had, had)
+				}
+				delete(model, s.B)
+			case 2:
+				v, ok := t.Get(s.B)
+				if want, had := model[s.B]; ok != had || v != want {
+					return fmt.Errorf("Get(%d) = %d, %t, want %d, %t", s.B, v, ok, want, had)
+				}
+			}
+			if err := t.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		keys := slices.Sorted(maps.Keys(model))
+		if err := compare("All", slices.Collect(seqKeys(t.All())), keys); err != nil {
+			return err
+		}
+		for lo := -1; lo <= 41; lo += 7 {
+			hi := lo + 10
+			want := slices.DeleteFunc(slices.Clone(keys), func(k int) bool { return k < lo || k >= hi })
+			if err := compare(fmt.Sprintf("Range(%d, %d)", lo, hi), slices.Collect(seqKeys(t.Range(lo, hi))), want); err != nil {
+				return err
+			}
+			// The floor of lo is the last key <= lo.
+			i, found := slices.BinarySearch(keys, lo)
+			if found {
+				i++
+			}
+			k, _, ok := t.Floor(lo)
+			if ok != (i > 0) || ok && k != keys[i-1] {
+				return fmt.Errorf("Floor(%d) = %d, %t; keys %v", lo, k, ok, keys)
+			}
+		}
+		return nil
+	}},
+	{"Trie", 4, func(steps []op) error {
+		// Keys are numbers written in base 3, so many share prefixes.
+		key := func(n int) string { return strconv.FormatInt(int64(n), 3) }
+		var t Trie[int]
+		model := map[string]int{}
+		for i, s := range steps {
+			k := key(s.B)
+			switch s.A {
+			case 0, 1:
+				t.Put(k, i)
+				model[k] = i
+			case 2:
+				_, had := model[k]
+				if t.Delete(k) != had {
+					return fmt.Errorf("Delete(%q) = %t, want %t", k, !had, had)
+				}
+				delete(model, k)
+			case 3:
+				prefix := k[:len(k)/2+1]
+				var want []string
+				for _, mk := range slices.Sorted(maps.Keys(model)) {
+					if strings.HasPrefix(mk, prefix) {
+						want = append(want, mk)
+					}
+				}
+				if err := compare(fmt.Sprintf("WithPrefix(%q)", prefix), slices.Collect(seqKeys(t.WithPrefix(prefix))), want); err != nil {
+					return err
+				}
+				if t.HasPrefix(prefix) != (len(want) > 0) {
+					return fmt.Errorf("HasPrefix(%q) = %t", prefix, !(len(want) > 0))
+				}
+				longest := ""
+				for mk := range model {
+					if strings.HasPrefix(k+"0", mk) && len(mk) > len(longest) {
+						longest = mk
+					}
+				}
+				if got, _, _ := t.LongestPrefix(k + "0"); got != longest {
+					return fmt.Errorf("LongestPrefix(%q) = %q, want %q", k+"0", got, longest)
+				}
+			}
+			if err := t.checkInvariants(); err != nil {
+				return err
+			}
+		}
+		return compare("All", slices.Collect(seqKeys(t.All())), slices.Sorted(maps.Keys(model)))
+	}},
+}
+
+// seqKeys drops the values of an iter.Seq2.
+func seqKeys[K, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// TestModels runs random operation sequences on each container and on its
+// model. quick shrinks a failure to the shortest sequence that still
+// fails; QUICK_SEED replays it.
+func TestModels(t *testing.T) {
+	for _, m := range models {
+		t.Run(m.name, func(t *testing.T) {
+			f := quick.Check(ops(m.kinds), func(steps []op) bool {
+				if err := m.run(steps); err != nil {
+					panic(err)
+				}
+				return true
+			}, &quick.Config{Runs: 200})
+			if f != nil {
+				t.Fatal(f)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package containers
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Queue is a first-in, first-out queue in a ring buffer that doubles when
+// it is full. Unlike a slice used as a queue with q = q[1:], it reuses the
+// space of popped elements. The zero value is an empty queue.
+type Queue[T any] struct {
+	buf []T
+	// head is the index of the front element; the elements run from head
+	// for n slots, wrapping around the end of buf.
+	head, n int
+}
+
+// Len returns the number of elements.
+func (q *Queue[T]) Len() int { return q.n }
+
+// Push adds v at the back.
+func (q *Queue[T]) Push(v T) {
+	if q.n == len(q.buf) {
+		q.grow()
+	}
+	q.buf[(q.head+q.n)%len(q.buf)] = v
+	q.n++
+}
+
+func (q *Queue[T]) grow() {
+	buf := make([]T, max(2*len(q.buf), 8))
+	// Unwrap the elements to the start of the new buffer.
+	k := copy(buf, q.buf[q.head:])
+	copy(buf[k:], q.buf[:q.head])
+	q.buf, q.head = buf, 0
+}
+
+// Peek returns the front element.
+func (q *Queue[T]) Peek() (T, bool) {
+	if q.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.buf[q.head], true
+}
+
+// Pop removes and returns the front element.
+func (q *Queue[T]) Pop() (T, bool) {
+	v, ok := q.Peek()
+	if ok {
+		var zero T
+		q.buf[q.head] = zero
+		q.head = (q.head + 1) % len(q.buf)
+		q.n--
+	}
+	return v, ok
+}
+
+// All yields the elements from front to back.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range q.n {
+			if !yield(q.buf[(q.head+i)%len(q.buf)]) {
+				return
+			}
+		}
+	}
+}
+
+// checkInvariants verifies that the front and the length fit in the
+// buffer.
+func (q *Queue[T]) checkInvariants() error {
+	if q.n < 0 || q.n > len(q.buf) {
+		return fmt.Errorf("queue: %d elements in a buffer of %d", q.n, len(q.buf))
+	}
+	if len(q.buf) > 0 && (q.head < 0 || q.head >= len(q.buf)) {
+		return fmt.Errorf("queue: head %d outside a buffer of %d", q.head, len(q.buf))
+	}
+	return nil
+}
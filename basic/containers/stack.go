@@ -0,0 +1,47 @@
+package containers
+
+import "iter"
+
+// Stack is a last-in, first-out stack backed by a slice. The zero value
+// is an empty stack.
+type Stack[T any] struct {
+	items []T
+}
+
+// Len returns the number of elements.
+func (s *Stack[T]) Len() int { return len(s.items) }
+
+// Push adds v on top.
+func (s *Stack[T]) Push(v T) { s.items = append(s.items, v) }
+
+// Peek returns the top element.
+func (s *Stack[T]) Peek() (T, bool) {
+	if len(s.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Pop removes and returns the top element.
+func (s *Stack[T]) Pop() (T, bool) {
+	v, ok := s.Peek()
+	if ok {
+		var zero T
+		// Clear the slot so the popped value can be garbage collected.
+		s.items[len(s.items)-1] = zero
+		s.items = s.items[:len(s.items)-1]
+	}
+	return v, ok
+}
+
+// All yields the elements from the top down.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(s.items) - 1; i >= 0; i-- {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
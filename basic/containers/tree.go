@@ -0,0 +1,325 @@
+package containers
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// Tree is an ordered map kept in an AVL tree: a binary search tree in
+// which the heights of the two subtrees of any node differ by at most
+// one. That keeps the height below 1.45 log2(n), so Get, Put and Delete
+// take logarithmic time, and the keys can be walked in order, which a Go
+// map cannot do.
+type Tree[K, V any] struct {
+	root *node[K, V]
+	n    int
+	cmp  func(a, b K) int
+}
+
+type node[K, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+	// height is 1 for a leaf; an empty subtree has height 0.
+	height int
+}
+
+// NewTree returns an empty tree ordered by cmp, which returns a negative
+// number, zero or a positive number as a is less than, equal to or
+// greater than b.
+func NewTree[K, V any](cmp func(a, b K) int) *Tree[K, V] {
+	return &Tree[K, V]{cmp: cmp}
+}
+
+// NewOrderedTree returns an empty tree ordered by <.
+func NewOrderedTree[K cmp.Ordered, V any]() *Tree[K, V] {
+	return NewTree[K, V](cmp.Compare[K])
+}
+
+// Len returns the number of keys.
+func (t *Tree[K, V]) Len() int { return t.n }
+
+// Height returns the height of the tree: the number of nodes on the
+// longest path from the root.
+func (t *Tree[K, V]) Height() int { return t.root.h() }
+
+func (n *node[K, V]) h() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[K, V]) fix() {
+	n.height = 1 + max(n.left.h(), n.right.h())
+}
+
+func (n *node[K, V]) balance() int { return n.left.h() - n.right.h() }
+
+// rotateRight lifts the left child of n into n's place:
+//
+//	    n          l
+//	   / \        / \
+//	  l   c  =>  a   n
+//	 / \            / \
+//	a   b          b   c
+func (n *node[K, V]) rotateRight() *node[K, V] {
+	l := n.left
+	n.left, l.right = l.right, n
+	n.fix()
+	l.fix()
+	return l
+}
+
+// rotateLeft is the mirror image of rotateRight.
+func (n *node[K, V]) rotateLeft() *node[K, V] {
+	r := n.right
+	n.right, r.left = r.left, n
+	n.fix()
+	r.fix()
+	return r
+}
+
+// rebalance restores the AVL property at n after one of its subtrees
+// changed height by one, and returns the new root of the subtree.
+func (n *node[K, V]) rebalance() *node[K, V] {
+	n.fix()
+	switch b := n.balance(); {
+	case b > 1:
+		if n.left.balance() < 0 {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case b < -1:
+		if n.right.balance() > 0 {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// Get returns the value stored under key.
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		switch c := t.cmp(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores value under key, replacing any value already there.
+func (t *Tree[K, V]) Put(key K, value V) {
+	t.root = t.put(t.root, key, value)
+}
+
+func (t *Tree[K, V]) put(n *node[K, V], key K, value V) *node[K, V] {
+	if n == nil {
+		t.n++
+		return &node[K, V]{key: key, value: value, height: 1}
+	}
+	switch c := t.cmp(key, n.key); {
+	case c < 0:
+		n.left = t.put(n.left, key, value)
+	case c > 0:
+		n.right = t.put(n.right, key, value)
+	default:
+		n.value = value
+		return n
+	}
+	return n.rebalance()
+}
+
+// Delete removes key and reports whether it was there.
+func (t *Tree[K, V]) Delete(key K) bool {
+	before := t.n
+	t.root = t.delete(t.root, key)
+	return t.n < before
+}
+
+func (t *Tree[K, V]) delete(n *node[K, V], key K) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	switch c := t.cmp(key, n.key); {
+	case c < 0:
+		n.left = t.delete(n.left, key)
+	case c > 0:
+		n.right = t.delete(n.right, key)
+	default:
+		t.n--
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		// Replace n by its successor, the smallest key on the right.
+		var succ *node[K, V]
+		n.right, succ = deleteMin(n.right)
+		succ.left, succ.right = n.left, n.right
+		n = succ
+	}
+	return n.rebalance()
+}
+
+// deleteMin detaches the smallest node of the subtree n and returns the
+// rebalanced subtree and that node.
+func deleteMin[K, V any](n *node[K, V]) (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var min *node[K, V]
+	n.left, min = deleteMin(n.left)
+	return n.rebalance(), min
+}
+
+// Min returns the smallest key and its value.
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return entry(n)
+}
+
+// Max returns the largest key and its value.
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return entry(n)
+}
+
+// Floor returns the largest key less than or equal to key.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if c := t.cmp(key, n.key); c < 0 {
+			n = n.left
+		} else {
+			best, n = n, n.right
+		}
+	}
+	return entry(best)
+}
+
+// Ceiling returns the smallest key greater than or equal to key.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := t.root; n != nil; {
+		if c := t.cmp(key, n.key); c > 0 {
+			n = n.right
+		} else {
+			best, n = n, n.left
+		}
+	}
+	return entry(best)
+}
+
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var k K
+		var v V
+		return k, v, false
+	}
+	return n.key, n.value, true
+}
+
+// All yields the keys and values in increasing key order.
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.inorder(yield)
+	}
+}
+
+func (n *node[K, V]) inorder(yield func(K, V) bool) bool {
+	return n == nil || n.left.inorder(yield) && yield(n.key, n.value) && n.right.inorder(yield)
+}
+
+// Backward yields the keys and values in decreasing key order.
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.reverse(yield)
+	}
+}
+
+func (n *node[K, V]) reverse(yield func(K, V) bool) bool {
+	return n == nil || n.right.reverse(yield) && yield(n.key, n.value) && n.left.reverse(yield)
+}
+
+// Range yields the keys from lo up to but not including hi, in order. It
+// skips the subtrees outside the range, so it takes time proportional to
+// log n plus the number of keys yielded.
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.rangeOf(t.root, lo, hi, yield)
+	}
+}
+
+func (t *Tree[K, V]) rangeOf(n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo, belowHi := t.cmp(n.key, lo) >= 0, t.cmp(n.key, hi) < 0
+	if aboveLo && !t.rangeOf(n.left, lo, hi, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(n.key, n.value) {
+		return false
+	}
+	if belowHi {
+		return t.rangeOf(n.right, lo, hi, yield)
+	}
+	return true
+}
+
+// checkInvariants verifies that the keys are in search tree order, that
+// every stored height is right, that every node is balanced and that the
+// length matches.
+func (t *Tree[K, V]) checkInvariants() error {
+	count := 0
+	if _, err := t.check(t.root, nil, nil, &count); err != nil {
+		return err
+	}
+	if count != t.n {
+		return fmt.Errorf("tree: Len is %d but there are %d nodes", t.n, count)
+	}
+	return nil
+}
+
+// check verifies the subtree n, whose keys must lie strictly between lo
+// and hi where those are not nil, and returns its height.
+func (t *Tree[K, V]) check(n *node[K, V], lo, hi *K, count *int) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+	*count++
+	if lo != nil && t.cmp(n.key, *lo) <= 0 || hi != nil && t.cmp(n.key, *hi) >= 0 {
+		return 0, fmt.Errorf("tree: key %v is out of order", n.key)
+	}
+	lh, err := t.check(n.left, lo, &n.key, count)
+	if err != nil {
+		return 0, err
+	}
+	rh, err := t.check(n.right, &n.key, hi, count)
+	if err != nil {
+		return 0, err
+	}
+	if h := 1 + max(lh, rh); n.height != h {
+		return 0, fmt.Errorf("tree: node %v has height %d, want %d", n.key, n.height, h)
+	}
+	if b := lh - rh; b < -1 || b > 1 {
+		return 0, fmt.Errorf("tree: node %v is out of balance by %d", n.key, b)
+	}
+	return n.height, nil
+}
@@ -0,0 +1,199 @@
+package containers
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Trie maps strings to values in a prefix tree: each node stands for a
+// prefix, and each edge adds one byte. All keys with a given prefix sit
+// under one node, so finding them does not look at any other key. Keys
+// come out in byte order, which for UTF-8 is Unicode code point order.
+// The zero value is an empty trie.
+type Trie[V any] struct {
+	root trieNode[V]
+	n    int
+}
+
+type trieNode[V any] struct {
+	// edges are sorted by byte. A slice is smaller than a map for the
+	// handful of children most nodes have, and keeps them in order.
+	edges []trieEdge[V]
+	value V
+	// ok is set when a key ends at this node.
+	ok bool
+}
+
+type trieEdge[V any] struct {
+	b    byte
+	node *trieNode[V]
+}
+
+func (n *trieNode[V]) child(b byte) (int, *trieNode[V]) {
+	i, found := slices.BinarySearchFunc(n.edges, b, func(e trieEdge[V], b byte) int { return int(e.b) - int(b) })
+	if found {
+		return i, n.edges[i].node
+	}
+	return i, nil
+}
+
+// find returns the node for prefix, or nil.
+func (t *Trie[V]) find(prefix string) *trieNode[V] {
+	n := &t.root
+	for i := 0; i < len(prefix) && n != nil; i++ {
+		_, n = n.child(prefix[i])
+	}
+	return n
+}
+
+// Len returns the number of keys.
+func (t *Trie[V]) Len() int { return t.n }
+
+// Put stores value under key.
+func (t *Trie[V]) Put(key string, value V) {
+	n := &t.root
+	for i := 0; i < len(key); i++ {
+		j, next := n.child(key[i])
+		if next == nil {
+			next = &trieNode[V]{}
+			n.edges = slices.Insert(n.edges, j, trieEdge[V]{key[i], next})
+		}
+		n = next
+	}
+	if !n.ok {
+		t.n++
+	}
+	n.value, n.ok = value, true
+}
+
+// Get returns the value stored under key.
+func (t *Trie[V]) Get(key string) (V, bool) {
+	if n := t.find(key); n != nil && n.ok {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes key and reports whether it was there. Nodes left with
+// no key and no children are removed too.
+func (t *Trie[V]) Delete(key string) bool {
+	deleted := t.root.delete(key)
+	if deleted {
+		t.n--
+	}
+	return deleted
+}
+
+func (n *trieNode[V]) delete(key string) bool {
+	if key == "" {
+		if !n.ok {
+			return false
+		}
+		var zero V
+		n.value, n.ok = zero, false
+		return true
+	}
+	i, next := n.child(key[0])
+	if next == nil || !next.delete(key[1:]) {
+		return false
+	}
+	if !next.ok && len(next.edges) == 0 {
+		n.edges = slices.Delete(n.edges, i, i+1)
+	}
+	return true
+}
+
+// HasPrefix reports whether any key starts with prefix.
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	n := t.find(prefix)
+	return n != nil && (n.ok || len(n.edges) > 0)
+}
+
+// All yields every key and value in key order.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return t.WithPrefix("")
+}
+
+// WithPrefix yields the keys starting with prefix and their values, in
+// key order.
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		if n := t.find(prefix); n != nil {
+			buf := []byte(prefix)
+			n.walk(&buf, yield)
+		}
+	}
+}
+
+// walk yields the keys under n; buf holds the prefix n stands for.
+func (n *trieNode[V]) walk(buf *[]byte, yield func(string, V) bool) bool {
+	if n.ok && !yield(string(*buf), n.value) {
+		return false
+	}
+	for _, e := range n.edges {
+		*buf = append(*buf, e.b)
+		if !e.node.walk(buf, yield) {
+			return false
+		}
+		*buf = (*buf)[:len(*buf)-1]
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key that is a prefix of s, as a
+// router matches paths or a tokenizer matches known words.
+func (t *Trie[V]) LongestPrefix(s string) (string, V, bool) {
+	var (
+		best  V
+		end   = -1
+		n     = &t.root
+		found bool
+	)
+	for i := 0; n != nil; i++ {
+		if n.ok {
+			best, end, found = n.value, i, true
+		}
+		if i == len(s) {
+			break
+		}
+		_, n = n.child(s[i])
+	}
+	if !found {
+		return "", best, false
+	}
+	return s[:end], best, true
+}
+
+// checkInvariants verifies that the edges of every node are sorted without
+// duplicates, that no node other than the root is a dead end holding no
+// key, and that the length matches.
+func (t *Trie[V]) checkInvariants() error {
+	count := 0
+	if err := t.root.check("", &count); err != nil {
+		return err
+	}
+	if count != t.n {
+		return fmt.Errorf("trie: Len is %d but there are %d keys", t.n, count)
+	}
+	return nil
+}
+
+func (n *trieNode[V]) check(path string, count *int) error {
+	if n.ok {
+		*count++
+	}
+	for i, e := range n.edges {
+		if i > 0 && n.edges[i-1].b >= e.b {
+			return fmt.Errorf("trie: edges under %q are not sorted", path)
+		}
+		if !e.node.ok && len(e.node.edges) == 0 {
+			return fmt.Errorf("trie: node %q holds no key and has no children", path+string([]byte{e.b}))
+		}
+		if err := e.node.check(path+string([]byte{e.b}), count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,557 @@
+// Package explain annotates Go source for beginners. It walks the syntax
+// tree of a file, type-checked with go/types, and attaches a short
+// explanation to each construct worth knowing about: declarations,
+// assignments, loops, switches, builtins such as make and delete,
+// pointers and so on. Each note names the README section that covers the
+// construct.
+package explain
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Note explains one construct.
+type Note struct {
+	Line, Col int
+	// Construct names the construct, e.g. "short variable declaration".
+	Construct string
+	// Text explains it in a sentence or two.
+	Text string
+	// Section is the title of the README section that covers it, or "".
+	Section string
+}
+
+// README section titles the notes refer to.
+const (
+	secImports   = "Importing packages"
+	secMain      = "`main` package vs libraries"
+	secFmt       = "The `fmt` package"
+	secVariables = "Variables"
+	secFor       = "For loops"
+	secIf        = "If / Else statements"
+	secSwitch    = "Switch / Case"
+	secArrays    = "Arrays and slices"
+	secSlices    = "Slice details"
+	secMaps      = "Maps"
+	secFunctions = "Functions"
+	secValidate  = "Validating structs with tags"
+)
+
+// File explains the Go file at path. The other files of its package are
+// type-checked with it, so identifiers defined elsewhere resolve; type
+// errors are tolerated and only make some notes less specific.
+func File(path string) ([]Note, error) {
+	fset := token.NewFileSet()
+	target, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	files := []*ast.File{target}
+	siblings, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.go"))
+	for _, name := range siblings {
+		if same, _ := sameFile(name, path); same || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if f, err := parser.ParseFile(fset, name, nil, parser.PackageClauseOnly); err != nil || f.Name.Name != target.Name.Name {
+			continue
+		}
+		if f, err := parser.ParseFile(fset, name, nil, 0); err == nil {
+			files = append(files, f)
+		}
+	}
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Defs:  map[*ast.Ident]types.Object{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{
+		Importer: exportImporter(fset, filepath.Dir(path)),
+		Error:    func(error) {},
+	}
+	pkg, _ := conf.Check(target.Name.Name, fset, files, info)
+
+	e := &explainer{fset: fset, info: info, pkg: pkg}
+	e.file(target)
+	slices.SortStableFunc(e.notes, func(a, b Note) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Col - b.Col
+	})
+	return dedupe(e.notes), nil
+}
+
+// dedupe drops notes repeated on the same line and shortens the ones
+// repeated later in the file to a pointer back to the first.
+func dedupe(notes []Note) []Note {
+	type key struct{ construct, text string }
+	first := map[key]int{}
+	out := notes[:0]
+	for _, n := range notes {
+		k := key{n.Construct, n.Text}
+		line, seen := first[k]
+		switch {
+		case !seen:
+			first[k] = n.Line
+		case line == n.Line:
+			continue
+		default:
+			n.Text = fmt.Sprintf("as on line %d", line)
+		}
+		out = append(out, n)
+	}
+	return out
+}
+
+// exportImporter imports packages from the export data the go command
+// writes for the dependencies of the package in dir. Unlike reading the
+// imports from source, this resolves module packages wherever the
+// process runs. When go list fails, imports fail too and the notes just
+// get less specific.
+func exportImporter(fset *token.FileSet, dir string) types.Importer {
+	exports := map[string]string{}
+	cmd := exec.Command("go", "list", "-e", "-export", "-deps", "-f", "{{.ImportPath}}\t{{.Export}}", ".")
+	cmd.Dir = dir
+	if out, err := cmd.Output(); err == nil {
+		for _, line := range strings.Split(string(out), "\n") {
+			if path, file, ok := strings.Cut(line, "\t"); ok && file != "" {
+				exports[path] = file
+			}
+		}
+	}
+	return importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		file, ok := exports[path]
+		if !ok {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+		return os.Open(file)
+	})
+}
+
+func sameFile(a, b string) (bool, error) {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(fa, fb), nil
+}
+
+type explainer struct {
+	fset  *token.FileSet
+	info  *types.Info
+	pkg   *types.Package
+	notes []Note
+}
+
+func (e *explainer) add(n ast.Node, section, construct, format string, args ...any) {
+	pos := e.fset.Position(n.Pos())
+	e.notes = append(e.notes, Note{
+		Line:      pos.Line,
+		Col:       pos.Column,
+		Construct: construct,
+		Text:      fmt.Sprintf(format, args...),
+		Section:   section,
+	})
+}
+
+// typeOf returns the type of x as written in the file, or "" when the
+// type checker could not work it out.
+func (e *explainer) typeOf(x ast.Expr) string {
+	tv, ok := e.info.Types[x]
+	if !ok || tv.Type == nil {
+		if id, ok := x.(*ast.Ident); ok {
+			if obj := e.info.ObjectOf(id); obj != nil {
+				return e.typeString(obj.Type())
+			}
+		}
+		return ""
+	}
+	return e.typeString(tv.Type)
+}
+
+func (e *explainer) typeString(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == e.pkg {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// builtin returns the name of the builtin function call calls, or "".
+func (e *explainer) builtin(call *ast.CallExpr) string {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	if b, ok := e.info.Uses[id].(*types.Builtin); ok {
+		return b.Name()
+	}
+	return ""
+}
+
+func (e *explainer) file(f *ast.File) {
+	for _, spec := range f.Imports {
+		path := strings.Trim(spec.Path.Value, `"`)
+		if spec.Name != nil {
+			e.add(spec, secImports, "renamed import",
+				"imports %s under the name %s, e.g. because another identifier already uses the package's own name", path, spec.Name.Name)
+		} else {
+			e.add(spec, secImports, "import", "makes the exported names of %s available as %s.Name", path, path[strings.LastIndex(path, "/")+1:])
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			e.funcDecl(n)
+		case *ast.GenDecl:
+			e.genDecl(n)
+		case *ast.AssignStmt:
+			e.assign(n)
+		case *ast.IncDecStmt:
+			e.add(n, secFor, "increment statement",
+				"%s changes the variable in place; in Go it is a statement, not an expression, so it cannot be used inside another expression", n.Tok)
+		case *ast.ForStmt:
+			e.forStmt(n)
+		case *ast.RangeStmt:
+			e.rangeStmt(n)
+		case *ast.IfStmt:
+			e.ifStmt(n)
+		case *ast.SwitchStmt:
+			e.switchStmt(n)
+		case *ast.TypeSwitchStmt:
+			e.add(n, secSwitch, "type switch", "chooses a case by the dynamic type of an interface value")
+		case *ast.CallExpr:
+			e.call(n)
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				e.add(n, secFunctions, "address-of", "&%s is a pointer (%s) to the variable %s, so whoever receives it can change that variable",
+					types.ExprString(n.X), e.typeOf(n), types.ExprString(n.X))
+			}
+		case *ast.StarExpr:
+			if tv, ok := e.info.Types[n]; ok && tv.IsValue() {
+				e.add(n, secFunctions, "dereference", "*%s is the %s that the pointer %s points to", types.ExprString(n.X), e.typeOf(n), types.ExprString(n.X))
+			}
+		case *ast.CompositeLit:
+			e.compositeLit(n)
+		case *ast.BinaryExpr:
+			if n.Op == token.REM {
+				e.add(n, secIf, "remainder", "%s is the remainder of dividing %s by %s; x%%2 == 0 is the usual test for even numbers",
+					types.ExprString(n), types.ExprString(n.X), types.ExprString(n.Y))
+			}
+		}
+		return true
+	})
+}
+
+func (e *explainer) funcDecl(fn *ast.FuncDecl) {
+	name := fn.Name.Name
+	if name == "main" && fn.Recv == nil {
+		e.add(fn, secMain, "main function", "main takes no arguments and returns nothing; the program starts here and exits when it returns")
+		return
+	}
+	if name == "init" && fn.Recv == nil {
+		e.add(fn, "The `init()` function", "init function", "init runs automatically before main; a file may have several")
+		return
+	}
+	results := 0
+	if fn.Type.Results != nil {
+		results = fn.Type.Results.NumFields()
+	}
+	switch {
+	case fn.Recv != nil:
+		e.add(fn, secFunctions, "method", "%s is a method: the receiver before the name is passed like an extra first parameter", name)
+	case results > 1:
+		e.add(fn, secFunctions, "multiple return values", "%s returns %d values; callers receive them with a, b := %s(...)", name, results, name)
+	default:
+		e.add(fn, secFunctions, "function declaration", "%s: parameters are copies of the arguments the caller passes", name)
+	}
+	for _, field := range fn.Type.Params.List {
+		if _, ok := field.Type.(*ast.StarExpr); ok {
+			for _, id := range field.Names {
+				e.add(id, secFunctions, "pointer parameter",
+					"%s is a pointer (%s): the function can change the caller's variable through it", id.Name, types.ExprString(field.Type))
+			}
+		}
+	}
+}
+
+func (e *explainer) genDecl(d *ast.GenDecl) {
+	for _, spec := range d.Specs {
+		switch spec := spec.(type) {
+		case *ast.ValueSpec:
+			names := identList(spec.Names)
+			switch {
+			case d.Tok == token.CONST:
+				e.add(spec, secVariables, "constant", "%s is fixed at compile time and cannot be assigned to", names)
+			case spec.Type != nil && len(spec.Values) == 0:
+				e.add(spec, secVariables, "var declaration", "declares %s of type %s, set to its zero value", names, types.ExprString(spec.Type))
+			case spec.Type != nil:
+				e.add(spec, secVariables, "var declaration", "declares %s with the explicit type %s", names, types.ExprString(spec.Type))
+			default:
+				e.add(spec, secVariables, "var declaration", "declares %s; %s inferred from the values", names, e.inferred(spec.Names))
+			}
+		case *ast.TypeSpec:
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			tagged := false
+			for _, f := range st.Fields.List {
+				tagged = tagged || f.Tag != nil
+			}
+			if tagged {
+				e.add(spec, secValidate, "struct with tags", "%s is a struct type; the strings after the field types are tags, read at run time through reflection", spec.Name.Name)
+			} else {
+				e.add(spec, secFunctions, "struct type", "%s groups named fields into one value", spec.Name.Name)
+			}
+		}
+	}
+}
+
+// inferred describes the types of the declared identifiers, e.g.
+// "x is float64 and y is string".
+func (e *explainer) inferred(ids []*ast.Ident) string {
+	var parts []string
+	for _, id := range ids {
+		if id.Name == "_" {
+			continue
+		}
+		if t := e.typeOf(id); t != "" {
+			parts = append(parts, id.Name+" is "+t)
+		}
+	}
+	if len(parts) == 0 {
+		return "the types are"
+	}
+	return strings.Join(parts, " and ")
+}
+
+func identList(ids []*ast.Ident) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (e *explainer) assign(a *ast.AssignStmt) {
+	if a.Tok == token.DEFINE {
+		var ids, fresh []*ast.Ident
+		for _, x := range a.Lhs {
+			if id, ok := x.(*ast.Ident); ok {
+				ids = append(ids, id)
+				if e.info.Defs[id] != nil {
+					fresh = append(fresh, id)
+				}
+			}
+		}
+		if len(a.Lhs) == 2 && len(a.Rhs) == 1 {
+			if ix, ok := a.Rhs[0].(*ast.IndexExpr); ok && e.isMap(ix.X) {
+				e.add(a, secMaps, "comma-ok lookup", "%s is true only if the key is in the map; %s gets the zero value otherwise",
+					types.ExprString(a.Lhs[1]), types.ExprString(a.Lhs[0]))
+				return
+			}
+		}
+		text := fmt.Sprintf("declares and assigns in one step (inside functions only); %s", e.inferred(ids))
+		if len(fresh) < len(ids) {
+			text += "; at least one variable on the left must be new, the others are just assigned"
+		}
+		e.add(a, secVariables, "short variable declaration", "%s", text)
+		return
+	}
+	if a.Tok != token.ASSIGN {
+		e.add(a, secVariables, "compound assignment", "x %s y is short for x = x %s y", a.Tok, strings.TrimSuffix(a.Tok.String(), "="))
+		return
+	}
+	if len(a.Lhs) > 1 {
+		e.add(a, secVariables, "multiple assignment", "all the right-hand sides are evaluated before any variable is assigned, so a, b = b, a swaps")
+		return
+	}
+	switch lhs := a.Lhs[0].(type) {
+	case *ast.StarExpr:
+		e.add(a, secFunctions, "assignment through a pointer", "changes the variable %s points to, not the pointer itself", types.ExprString(lhs.X))
+	case *ast.IndexExpr:
+		if e.isMap(lhs.X) {
+			e.add(a, secMaps, "map assignment", "adds the key or replaces its value; writing to a nil map panics, so it must come from make or a literal")
+		} else {
+			e.add(a, secArrays, "element assignment", "sets one element; an index outside 0..len-1 panics at run time")
+		}
+	case *ast.Ident:
+		if call, ok := a.Rhs[0].(*ast.CallExpr); ok && e.builtin(call) == "append" {
+			return // explained by the append call
+		}
+		if lhs.Name == "_" {
+			e.add(a, secVariables, "blank assignment", "the blank identifier _ discards the value")
+		}
+	}
+}
+
+func (e *explainer) isMap(x ast.Expr) bool {
+	tv, ok := e.info.Types[x]
+	if !ok || tv.Type == nil {
+		return false
+	}
+	_, ok = tv.Type.Underlying().(*types.Map)
+	return ok
+}
+
+func (e *explainer) forStmt(f *ast.ForStmt) {
+	switch {
+	case f.Init != nil || f.Post != nil:
+		e.add(f, secFor, "three-part for loop", "init runs once, the condition is checked before each iteration and the post statement after it; variables declared in init belong to the loop")
+	case f.Cond != nil:
+		e.add(f, secFor, "condition-only for loop", "runs while the condition holds, like while in other languages")
+	default:
+		e.add(f, secFor, "infinite loop", "runs until break, return or a panic")
+	}
+}
+
+func (e *explainer) rangeStmt(r *ast.RangeStmt) {
+	tv := e.info.Types[r.X]
+	if tv.Type == nil {
+		e.add(r, secFor, "range loop", "iterates over %s", types.ExprString(r.X))
+		return
+	}
+	switch tv.Type.Underlying().(type) {
+	case *types.Map:
+		e.add(r, secMaps, "range over a map", "visits every key once in an unspecified order that changes from run to run")
+	case *types.Slice, *types.Array:
+		e.add(r, secFor, "range over a slice", "gives the index and a copy of each element; changing the copy does not change the slice")
+	case *types.Basic:
+		if tv.Type.Underlying().(*types.Basic).Info()&types.IsString != 0 {
+			e.add(r, secFor, "range over a string", "gives the byte offset and the rune (not the byte) starting there")
+		} else {
+			e.add(r, secFor, "range over an integer", "counts from 0 up to but not including %s", types.ExprString(r.X))
+		}
+	default:
+		e.add(r, secFor, "range loop", "iterates over %s", types.ExprString(r.X))
+	}
+}
+
+func (e *explainer) ifStmt(s *ast.IfStmt) {
+	if s.Init != nil {
+		e.add(s, secIf, "if with an init statement", "the statement before ; runs first; what it declares is visible only in this if and its else branches")
+	} else {
+		e.add(s, secIf, "if statement", "no parentheses around the condition, but the braces are required")
+	}
+	if s.Else != nil {
+		if _, chained := s.Else.(*ast.IfStmt); chained {
+			return
+		}
+		e.add(s.Else, secIf, "else branch", "runs when the condition is false")
+	}
+}
+
+func (e *explainer) switchStmt(s *ast.SwitchStmt) {
+	fallsThrough := false
+	ast.Inspect(s.Body, func(n ast.Node) bool {
+		if b, ok := n.(*ast.BranchStmt); ok && b.Tok == token.FALLTHROUGH {
+			fallsThrough = true
+		}
+		return !fallsThrough
+	})
+	what := "the first case whose value equals " + types.ExprString(s.Tag)
+	if s.Tag == nil {
+		what = "the first case whose condition is true"
+	}
+	if fallsThrough {
+		e.add(s, secSwitch, "switch with fallthrough", "runs %s; fallthrough continues into the next case", what)
+	} else {
+		e.add(s, secSwitch, "switch", "runs %s and stops: cases do not fall through, so no break is needed", what)
+	}
+	for _, stmt := range s.Body.List {
+		if cc := stmt.(*ast.CaseClause); cc.List == nil {
+			e.add(cc, secSwitch, "default case", "runs when no other case matches, wherever it is written")
+		}
+	}
+}
+
+func (e *explainer) call(c *ast.CallExpr) {
+	switch e.builtin(c) {
+	case "make":
+		switch t := e.info.Types[c]; {
+		case t.Type == nil:
+		case len(c.Args) == 3:
+			e.add(c, secSlices, "make with capacity", "creates a %s of length %s with room for %s elements before append has to reallocate",
+				e.typeString(t.Type), types.ExprString(c.Args[1]), types.ExprString(c.Args[2]))
+		case isMapType(t.Type):
+			e.add(c, secMaps, "make a map", "creates an empty map that is ready for writes")
+		default:
+			e.add(c, secArrays, "make", "creates a %s with every element set to its zero value", e.typeString(t.Type))
+		}
+	case "append":
+		e.add(c, secSlices, "append", "returns the slice with the new elements; when the capacity runs out it copies everything to a bigger array, so always use the result")
+	case "delete":
+		e.add(c, secMaps, "delete", "removes the key from the map; deleting a missing key does nothing")
+	case "len":
+		e.add(c, secSlices, "len", "the number of elements (bytes for a string)")
+	case "cap":
+		e.add(c, secSlices, "cap", "how many elements fit before append has to allocate a new array")
+	case "":
+		sel, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return
+		}
+		if name, ok := e.info.Uses[pkg].(*types.PkgName); ok && name.Imported().Path() == "fmt" {
+			switch fn := sel.Sel.Name; {
+			case strings.HasSuffix(fn, "f"):
+				e.add(c, secFmt, "fmt."+fn, "formats with verbs such as %%d, %%s and %%v; every verb needs a matching argument")
+			case strings.HasSuffix(fn, "ln"):
+				e.add(c, secFmt, "fmt."+fn, "prints the arguments separated by spaces, followed by a newline")
+			}
+		}
+	}
+}
+
+func isMapType(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Map)
+	return ok
+}
+
+func (e *explainer) compositeLit(c *ast.CompositeLit) {
+	tv, ok := e.info.Types[c]
+	if !ok || tv.Type == nil || c.Type == nil {
+		// Elided types, like the rows of [2][3]int{{1, 2, 3}, ...}, are
+		// covered by the outer literal.
+		return
+	}
+	typ := e.typeString(tv.Type)
+	switch t := tv.Type.Underlying().(type) {
+	case *types.Array:
+		if _, nested := t.Elem().Underlying().(*types.Array); nested {
+			e.add(c, secArrays, "array of arrays", "%s is an array of %d arrays; the inner braces are the rows", typ, t.Len())
+		} else {
+			e.add(c, secArrays, "array literal", "%s has a fixed length that is part of its type", typ)
+		}
+	case *types.Slice:
+		e.add(c, secArrays, "slice literal", "creates a %s backed by a new array, with len and cap both %d", typ, len(c.Elts))
+	case *types.Map:
+		e.add(c, secMaps, "map literal", "creates a %s with these entries", typ)
+	case *types.Struct:
+		e.add(c, secFunctions, "struct literal", "creates a %s; fields not named get their zero value", typ)
+	}
+}
@@ -0,0 +1,85 @@
+package explain
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with the file testdata/name, or rewrites the file
+// when the tests run with -update.
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (rerun with -update to accept it):\n%s", path, got)
+	}
+}
+
+func lesson(t *testing.T) ([]byte, []Note) {
+	t.Helper()
+	path := filepath.Join("testdata", "lesson", "lesson.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src, notes
+}
+
+func TestWriteText(t *testing.T) {
+	src, notes := lesson(t)
+	var buf bytes.Buffer
+	if err := WriteText(&buf, src, notes, 40); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "lesson.txt", buf.Bytes())
+}
+
+func TestWriteHTML(t *testing.T) {
+	src, notes := lesson(t)
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, "lesson.go", src, notes, "README.md"); err != nil {
+		t.Fatal(err)
+	}
+	golden(t, "lesson.html", buf.Bytes())
+}
+
+func TestAnchor(t *testing.T) {
+	tests := map[string]string{
+		"If / Else statements":         "if--else-statements",
+		"`main` package vs libraries":  "main-package-vs-libraries",
+		"Validating structs with tags": "validating-structs-with-tags",
+		"`basic kata gen` — exercises": "basic-kata-gen--exercises",
+	}
+	for heading, want := range tests {
+		if got := Anchor(heading); got != want {
+			t.Errorf("Anchor(%q) = %q, want %q", heading, got, want)
+		}
+	}
+}
+
+func TestHeadings(t *testing.T) {
+	readme := []byte("# Title\n\n## For loops\n\n```sh\n# not a heading\n```\n### Maps \n")
+	got := Headings(readme)
+	if len(got) != 3 || !got["Title"] || !got["For loops"] || !got["Maps"] {
+		t.Errorf("got %v", got)
+	}
+}
@@ -0,0 +1,138 @@
+package explain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Anchor returns the fragment GitHub gives a Markdown heading, e.g.
+// "if--else-statements" for "If / Else statements".
+func Anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteByte('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Headings returns the titles of the Markdown headings in readme, skipping
+// fenced code blocks.
+func Headings(readme []byte) map[string]bool {
+	titles := map[string]bool{}
+	inCode := false
+	sc := bufio.NewScanner(bytes.NewReader(readme))
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "#") {
+			titles[strings.TrimSpace(strings.TrimLeft(line, "#"))] = true
+		}
+	}
+	return titles
+}
+
+// WriteText writes src with the notes beside it: the source on the left,
+// cut or padded to width columns, and one note per row on the right. Lines
+// with several notes continue on extra rows with an empty left side.
+func WriteText(w io.Writer, src []byte, notes []Note, width int) error {
+	bw := bufio.NewWriter(w)
+	byLine := groupByLine(notes)
+	for i, line := range strings.Split(strings.TrimRight(string(src), "\n"), "\n") {
+		left := fitWidth(strings.ReplaceAll(line, "\t", "    "), width)
+		lineNotes := byLine[i+1]
+		if len(lineNotes) == 0 {
+			fmt.Fprintf(bw, "%4d  %s\n", i+1, strings.TrimRight(left, " "))
+			continue
+		}
+		for j, n := range lineNotes {
+			if j > 0 {
+				left = strings.Repeat(" ", width)
+			}
+			ref := ""
+			if n.Section != "" {
+				ref = " [" + n.Section + "]"
+			}
+			num := fmt.Sprintf("%4d", i+1)
+			if j > 0 {
+				num = "    "
+			}
+			fmt.Fprintf(bw, "%s  %s │ %s: %s%s\n", num, left, n.Construct, n.Text, ref)
+		}
+	}
+	return bw.Flush()
+}
+
+func groupByLine(notes []Note) map[int][]Note {
+	m := map[int][]Note{}
+	for _, n := range notes {
+		m[n.Line] = append(m[n.Line], n)
+	}
+	return m
+}
+
+// fitWidth pads or cuts s to exactly width runes.
+func fitWidth(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
+
+// WriteHTML writes a page with src and the notes side by side. Notes link
+// to readmeURL plus the anchor of their section.
+func WriteHTML(w io.Writer, title string, src []byte, notes []Note, readmeURL string) error {
+	type row struct {
+		Num   int
+		Code  string
+		Notes []Note
+	}
+	byLine := groupByLine(notes)
+	var rows []row
+	for i, line := range strings.Split(strings.TrimRight(string(src), "\n"), "\n") {
+		rows = append(rows, row{Num: i + 1, Code: line, Notes: byLine[i+1]})
+	}
+	return pageTemplate.Execute(w, map[string]any{"Title": title, "Rows": rows, "README": readmeURL})
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{"anchor": Anchor}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} explained</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; }
+td { vertical-align: top; padding: 0 .5em; }
+td.num { color: #999; text-align: right; }
+td.code { font-family: monospace; white-space: pre; tab-size: 4; border-right: 1px solid #ccc; }
+td.notes { font-size: 90%; }
+td.notes p { margin: 0 0 .3em; }
+tr:hover { background: #f4f4f4; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+{{- range .Rows}}
+<tr><td class="num">{{.Num}}</td><td class="code">{{.Code}}</td><td class="notes">
+{{- range .Notes}}<p><b>{{.Construct}}</b>: {{.Text}}{{if .Section}} <a href="{{$.README}}#{{anchor .Section}}">{{.Section}}</a>{{end}}</p>{{end -}}
+</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
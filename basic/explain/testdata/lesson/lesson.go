@@ -0,0 +1,32 @@
+package main
+
+import "fmt"
+
+func sum(a int, b int) int {
+	return a + b
+}
+
+func main() {
+	var name string = "Alice"
+	ages := make(map[string]int)
+	ages[name] = 30
+	delete(ages, name)
+
+	nums := []int{1, 2, 3}
+	nums = append(nums, 4)
+	for i, n := range nums {
+		if n%2 == 0 {
+			fmt.Println(i, "is even")
+		}
+	}
+
+	x := 1
+	p := &x
+	*p = sum(*p, 10)
+	switch x {
+	case 11:
+		fmt.Println("eleven")
+	default:
+		fmt.Println("other")
+	}
+}
@@ -1,3 +1,13 @@
 module github.com/amiiralihassanpour/golang_learning
 
 go 1.25.0
+
+require google.golang.org/protobuf v1.36.12
+
+require golang.org/x/text v0.41.0
+
+require (
+	golang.org/x/mod v0.39.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
+	golang.org/x/tools v0.49.0
+)
@@ -0,0 +1,254 @@
+// Package gradebook keeps the marks of a class and turns them into grades.
+//
+// Where the maps lesson stores one int per student, a Book maps each name
+// to a slice of scores, one per assessment. Each assessment has a weight
+// and a maximum. From these the package computes each student's weighted
+// percentage, describes the class with summary statistics, assigns letter
+// grades on one of several curves and renders report cards with
+// text/template. Books are read from and written to CSV in the layout a
+// spreadsheet would use.
+package gradebook
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Assessment is a piece of graded work.
+type Assessment struct {
+	Name string
+	// Weight is the assessment's share of the final grade, relative to
+	// the other assessments' weights.
+	Weight float64
+	// Max is the number of points available. A score may exceed it, for
+	// extra credit.
+	Max float64
+}
+
+// Status says how a score counts.
+type Status uint8
+
+const (
+	// Graded work counts with its points.
+	Graded Status = iota
+	// Missing work was not handed in and counts as zero.
+	Missing
+	// Excused work is left out of the grade, and the weights of the other
+	// assessments are scaled up to make up for it.
+	Excused
+)
+
+// Score is a student's result on one assessment.
+type Score struct {
+	Points float64
+	Status Status
+}
+
+// Book is the marks of a class.
+type Book struct {
+	Assessments []Assessment
+	// Scores maps each student's name to their scores, in the order of
+	// Assessments.
+	Scores map[string][]Score
+}
+
+// Names returns the students in alphabetical order.
+func (b *Book) Names() []string {
+	names := make([]string, 0, len(b.Scores))
+	for name := range b.Scores {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Percent returns the student's weighted percentage: each assessment's
+// points as a percentage of its maximum, averaged with the assessments'
+// weights. Excused assessments are left out. If every assessment is
+// excused, there is nothing to grade and Percent returns NaN; so it does
+// for a name not in the book.
+func (b *Book) Percent(name string) float64 {
+	scores, ok := b.Scores[name]
+	if !ok {
+		return math.NaN()
+	}
+	var sum, weights float64
+	for i, a := range b.Assessments {
+		s := scores[i]
+		if s.Status == Excused || a.Weight == 0 {
+			continue
+		}
+		if s.Status == Graded {
+			sum += a.Weight * s.Points / a.Max
+		}
+		weights += a.Weight
+	}
+	if weights == 0 {
+		return math.NaN()
+	}
+	return 100 * sum / weights
+}
+
+// Read reads a book from CSV. The header row names the assessments after
+// a first column headed "name". Rows whose first cell is "weight" or
+// "max" may follow it to give the weights, which default to 1, and the
+// maximums, which default to 100. Every other row is a student: a name,
+// then a score per assessment, which is a number, empty for missing work
+// or "ex" for excused work.
+func Read(r io.Reader) (*Book, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("gradebook: empty file")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gradebook: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "name") {
+		return nil, errors.New(`gradebook: the header must be "name" followed by the assessments`)
+	}
+
+	b := &Book{Scores: map[string][]Score{}}
+	for i, h := range header[1:] {
+		name := strings.TrimSpace(h)
+		if name == "" {
+			return nil, fmt.Errorf("gradebook: line 1: assessment %d has no name", i+1)
+		}
+		if slices.ContainsFunc(b.Assessments, func(a Assessment) bool { return a.Name == name }) {
+			return nil, fmt.Errorf("gradebook: line 1: assessment %q appears twice", name)
+		}
+		b.Assessments = append(b.Assessments, Assessment{Name: name, Weight: 1, Max: 100})
+	}
+
+	students := false
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gradebook: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		fail := func(col int, format string, args ...any) error {
+			_, column := cr.FieldPos(col)
+			return fmt.Errorf("gradebook: line %d, column %d: %s", line, column, fmt.Sprintf(format, args...))
+		}
+		name := strings.TrimSpace(record[0])
+
+		if kind := strings.ToLower(name); !students && (kind == "weight" || kind == "max") {
+			for i, cell := range record[1:] {
+				v, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+				switch {
+				case err != nil || math.IsNaN(v) || math.IsInf(v, 0):
+					return nil, fail(i+1, "%s %q is not a number", kind, cell)
+				case kind == "weight" && v < 0:
+					return nil, fail(i+1, "weight %v is negative", v)
+				case kind == "max" && v <= 0:
+					return nil, fail(i+1, "max %v is not positive", v)
+				case kind == "weight":
+					b.Assessments[i].Weight = v
+				default:
+					b.Assessments[i].Max = v
+				}
+			}
+			continue
+		}
+
+		students = true
+		if name == "" {
+			return nil, fail(0, "missing name")
+		}
+		if _, dup := b.Scores[name]; dup {
+			return nil, fail(0, "%q appears twice", name)
+		}
+		scores := make([]Score, len(b.Assessments))
+		for i, cell := range record[1:] {
+			cell = strings.TrimSpace(cell)
+			switch {
+			case cell == "":
+				scores[i].Status = Missing
+			case strings.EqualFold(cell, "ex"):
+				scores[i].Status = Excused
+			default:
+				v, err := strconv.ParseFloat(cell, 64)
+				if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+					return nil, fail(i+1, "%s: score %q is not a number of points", b.Assessments[i].Name, cell)
+				}
+				scores[i].Points = v
+			}
+		}
+		b.Scores[name] = scores
+	}
+
+	if !slices.ContainsFunc(b.Assessments, func(a Assessment) bool { return a.Weight > 0 }) {
+		return nil, errors.New("gradebook: every assessment has weight 0")
+	}
+	return b, nil
+}
+
+// Write writes the book as CSV in the layout Read reads, with the
+// students in alphabetical order.
+func (b *Book) Write(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := []string{"name"}
+	for _, a := range b.Assessments {
+		row = append(row, a.Name)
+	}
+	cw.Write(row)
+	for _, kind := range []string{"weight", "max"} {
+		row = append(row[:0], kind)
+		for _, a := range b.Assessments {
+			v := a.Weight
+			if kind == "max" {
+				v = a.Max
+			}
+			row = append(row, formatNumber(v))
+		}
+		cw.Write(row)
+	}
+	for _, name := range b.Names() {
+		row = append(row[:0], name)
+		for _, s := range b.Scores[name] {
+			row = append(row, s.String())
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// String returns the score as Read reads it.
+func (s Score) String() string {
+	switch s.Status {
+	case Missing:
+		return ""
+	case Excused:
+		return "ex"
+	}
+	return formatNumber(s.Points)
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+//go:embed sample.csv
+var sample string
+
+// Sample returns a small class to try the package on.
+func Sample() *Book {
+	b, err := Read(strings.NewReader(sample))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,72 @@
+package gradebook
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPercent checks the weighted percentages of the sample class against
+// ones worked out by hand.
+func TestPercent(t *testing.T) {
+	sample := Sample()
+	for name, want := range map[string]float64{
+		// Homework 2 is missing and counts as zero.
+		"Bob": (10*75 + 10*0 + 10*70 + 30*72 + 40*64) / 100.0,
+		// The quiz is excused, so the other weights add up to 90.
+		"Farid": (10*90 + 10*95 + 30*77 + 40*83) / 90.0,
+		"Cara":  (10*100 + 10*100 + 10*100 + 30*95 + 40*97) / 100.0,
+	} {
+		if got := sample.Percent(name); !near(got, want) {
+			t.Errorf("%s: %v%%, want %v%%", name, got, want)
+		}
+	}
+	if got := sample.Percent("Nobody"); !math.IsNaN(got) {
+		t.Errorf("a student not in the book has %v%%", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	sample := Sample()
+	var first, second bytes.Buffer
+	if err := sample.Write(&first); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(b, sample) {
+		t.Fatalf("read back differently:\n%s", first.String())
+	}
+	if err := b.Write(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("written once as\n%s\nand then as\n%s", first.String(), second.String())
+	}
+}
+
+// TestReadErrors checks that bad files are rejected with their position.
+func TestReadErrors(t *testing.T) {
+	tests := []struct{ csv, want string }{
+		{"", "empty file"},
+		{"student,Quiz\nAl,3\n", "header"},
+		{"name,Quiz,Quiz\n", `"Quiz" appears twice`},
+		{"name,Quiz\nweight,-1\n", "line 2, column 8: weight -1 is negative"},
+		{"name,Quiz\nmax,0\n", "line 2, column 5: max 0 is not positive"},
+		{"name,Quiz\nweight,0\nAl,3\n", "weight 0"},
+		{"name,Quiz,Test\nAl,3,x\n", `line 2, column 6: Test: score "x"`},
+		{"name,Quiz\nAl,-3\n", `line 2, column 4: Quiz: score "-3"`},
+		{"name,Quiz\nAl,3\nAl,4\n", `line 3, column 1: "Al" appears twice`},
+		{"name,Quiz\nAl,3,4\n", "wrong number of fields"},
+	}
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.csv))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: got %v, want an error containing %q", tt.csv, err, tt.want)
+		}
+	}
+}
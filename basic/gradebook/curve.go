@@ -0,0 +1,114 @@
+package gradebook
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Letters are the letter grades, best first. A student with nothing to
+// grade gets Incomplete instead.
+var Letters = []string{"A", "B", "C", "D", "F"}
+
+// Incomplete is the grade of a student whose work was all excused.
+const Incomplete = "I"
+
+// Curve is a way of turning percentages into letter grades.
+type Curve struct {
+	Name    string
+	Summary string
+	// Letters returns the letter for each of percents, a grade for the
+	// whole class at once, since most curves depend on how everyone did.
+	// NaN percentages get Incomplete and do not affect the others.
+	Letters func(percents []float64) []string
+}
+
+// Curves lists the curves.
+var Curves = []Curve{
+	{"straight", "A from 90%, B from 80%, C from 70%, D from 60%", Straight},
+	{"top", "add the points that bring the best student to 100%, then grade straight", Top},
+	{"bell", "by standard deviations from the mean: A above +1.5, B above +0.5, C above -0.5, D above -1.5", Bell},
+	{"quota", "by rank: the top 10% get A, the next 20% B, 40% C, 20% D and the last 10% F", Quota},
+}
+
+// LookupCurve returns the curve called name.
+func LookupCurve(name string) (Curve, error) {
+	for _, c := range Curves {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Curve{}, fmt.Errorf("gradebook: unknown curve %q", name)
+}
+
+// byCutoffs returns the first letter whose cutoff x reaches, or F.
+func byCutoffs(x float64, cutoffs [4]float64) string {
+	for i, c := range cutoffs {
+		if x >= c {
+			return Letters[i]
+		}
+	}
+	return Letters[4]
+}
+
+// grade applies letter to every percentage that is not NaN.
+func grade(percents []float64, letter func(x float64) string) []string {
+	out := make([]string, len(percents))
+	for i, x := range percents {
+		if math.IsNaN(x) {
+			out[i] = Incomplete
+		} else {
+			out[i] = letter(x)
+		}
+	}
+	return out
+}
+
+var straightCutoffs = [4]float64{90, 80, 70, 60}
+
+// Straight grades on a fixed scale, whatever the rest of the class did.
+func Straight(percents []float64) []string {
+	return grade(percents, func(x float64) string { return byCutoffs(x, straightCutoffs) })
+}
+
+// Top raises everyone's percentage by the same amount, so that the best
+// student has 100%, and then grades straight. It helps when an exam
+// turned out harder than intended, and leaves a class whose best student
+// already has 100% alone.
+func Top(percents []float64) []string {
+	best := Describe(percents).Max
+	shift := max(100-best, 0)
+	return grade(percents, func(x float64) string { return byCutoffs(x+shift, straightCutoffs) })
+}
+
+// Bell grades on the normal curve: by how many standard deviations each
+// percentage lies from the class mean. A class where everyone has the same
+// percentage all gets C.
+func Bell(percents []float64) []string {
+	s := Describe(percents)
+	return grade(percents, func(x float64) string {
+		if s.StdDev == 0 {
+			return "C"
+		}
+		return byCutoffs((x-s.Mean)/s.StdDev, [4]float64{1.5, 0.5, -0.5, -1.5})
+	})
+}
+
+// Quota grades by rank, giving each letter to a fixed share of the class.
+// Students with equal percentages get the same letter: the one a student
+// ranked just after everyone better would get.
+func Quota(percents []float64) []string {
+	sorted := slices.DeleteFunc(slices.Clone(percents), math.IsNaN)
+	slices.Sort(sorted)
+	return grade(percents, func(x float64) string {
+		above := sort.Search(len(sorted), func(i int) bool { return sorted[i] > x })
+		better := float64(len(sorted)-above) / float64(len(sorted))
+		for i, share := range [4]float64{0.1, 0.3, 0.7, 0.9} {
+			if better < share {
+				return Letters[i]
+			}
+		}
+		return Letters[4]
+	})
+}
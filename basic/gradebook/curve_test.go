@@ -0,0 +1,40 @@
+package gradebook
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCurves(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		curve    string
+		percents []float64
+		want     string
+	}{
+		{"straight", []float64{100, 90, 89.99, 80, 70, 60, 59.9, nan}, "AABBCDFI"},
+		// The best has 80%, so everyone gains 20 points.
+		{"top", []float64{80, 70, 50, 39.9}, "AACF"},
+		{"top", []float64{100, 75}, "AC"},
+		// Mean 50 and standard deviation 11.8, so 42 and 58 are about 0.7
+		// below and above, and 30 and 70 about 1.7.
+		{"bell", []float64{30, 45, 50, 55, 70, 58, 42}, "FCCCABD"},
+		{"bell", []float64{70, 70, 70}, "CCC"},
+		{"quota", []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, "ABBCCCCDDF"},
+		// Ties share the better letter.
+		{"quota", []float64{90, 80, 80, 80, 50, nan}, "ABBBDI"},
+	}
+	for _, tt := range tests {
+		c, err := LookupCurve(tt.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(c.Letters(tt.percents), ""); got != tt.want {
+			t.Errorf("%s on %v: got %s, want %s", tt.curve, tt.percents, got, tt.want)
+		}
+	}
+	if _, err := LookupCurve("none"); err == nil {
+		t.Error("LookupCurve accepted an unknown curve")
+	}
+}
@@ -0,0 +1,184 @@
+package gradebook
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Report is the graded class: statistics for each assessment and for the
+// final percentages, and a report card per student.
+type Report struct {
+	Curve       Curve
+	Assessments []AssessmentStats
+	Final       Stats
+	// Distribution counts the students with each letter, in the order of
+	// Letters, followed by Incomplete if anyone has it.
+	Distribution []LetterCount
+	// Cards are in alphabetical order.
+	Cards []*Card
+}
+
+// AssessmentStats describes the class's percentages on one assessment.
+// Missing work counts as 0% and excused work is left out, as in the
+// final grade.
+type AssessmentStats struct {
+	Assessment
+	Stats Stats
+}
+
+// LetterCount is the number of students with a letter grade.
+type LetterCount struct {
+	Letter string
+	Count  int
+}
+
+// Card is a student's report card.
+type Card struct {
+	Name    string
+	Lines   []Line
+	Percent float64
+	Letter  string
+	// Rank is the student's place in the class, 1 for the best. Students
+	// with equal percentages share a rank. Of is the number of students
+	// ranked, which leaves out any with an Incomplete.
+	Rank, Of int
+}
+
+// Line is one assessment on a report card.
+type Line struct {
+	Assessment
+	Score
+	// Percent is the score as a percentage of the maximum, NaN if it was
+	// excused.
+	Percent float64
+	// ClassMean is the class's mean percentage on the assessment.
+	ClassMean float64
+}
+
+// percent returns a score as a percentage of the assessment's maximum,
+// NaN if it was excused.
+func (a Assessment) percent(s Score) float64 {
+	switch s.Status {
+	case Excused:
+		return math.NaN()
+	case Missing:
+		return 0
+	}
+	return 100 * s.Points / a.Max
+}
+
+// Report grades the class on curve c.
+func (b *Book) Report(c Curve) *Report {
+	names := b.Names()
+	r := &Report{Curve: c}
+	for i, a := range b.Assessments {
+		percents := make([]float64, len(names))
+		for j, name := range names {
+			percents[j] = a.percent(b.Scores[name][i])
+		}
+		r.Assessments = append(r.Assessments, AssessmentStats{a, Describe(percents)})
+	}
+
+	finals := make([]float64, len(names))
+	for i, name := range names {
+		finals[i] = b.Percent(name)
+	}
+	r.Final = Describe(finals)
+	letters := c.Letters(finals)
+
+	counts := map[string]int{}
+	for i, name := range names {
+		card := &Card{Name: name, Percent: finals[i], Letter: letters[i]}
+		counts[card.Letter]++
+		for j, a := range b.Assessments {
+			s := b.Scores[name][j]
+			card.Lines = append(card.Lines, Line{a, s, a.percent(s), r.Assessments[j].Stats.Mean})
+		}
+		if !math.IsNaN(card.Percent) {
+			card.Rank, card.Of = 1, r.Final.N
+			for _, other := range finals {
+				if other > card.Percent {
+					card.Rank++
+				}
+			}
+		}
+		r.Cards = append(r.Cards, card)
+	}
+	for _, l := range Letters {
+		r.Distribution = append(r.Distribution, LetterCount{l, counts[l]})
+	}
+	if n := counts[Incomplete]; n > 0 {
+		r.Distribution = append(r.Distribution, LetterCount{Incomplete, n})
+	}
+	return r
+}
+
+// Card returns the report card of the student called name.
+func (r *Report) Card(name string) (*Card, bool) {
+	for _, c := range r.Cards {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+//go:embed report.tmpl
+var reportText string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	// pct formats a percentage, or a dash for NaN.
+	"pct": func(x float64) string {
+		if math.IsNaN(x) {
+			return "-"
+		}
+		return strconv.FormatFloat(x, 'f', 1, 64)
+	},
+	"num":  formatNumber,
+	"bar":  func(n int) string { return strings.Repeat("#", n) },
+	"rule": func(s string) string { return strings.Repeat("=", utf8.RuneCountInString(s)) },
+	// score shows a line's points out of the maximum, or why there are
+	// none.
+	"score": func(l Line) string {
+		switch l.Status {
+		case Missing:
+			return "missing"
+		case Excused:
+			return "excused"
+		}
+		return formatNumber(l.Points) + "/" + formatNumber(l.Max)
+	},
+}).Parse(reportText))
+
+// WriteCSV writes each student's final percentage, rounded to two
+// places, letter and rank as CSV, for a spreadsheet or a school system.
+// Incomplete students have no percentage or rank.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"name", "percent", "letter", "rank"})
+	for _, c := range r.Cards {
+		percent, rank := "", ""
+		if c.Rank > 0 {
+			percent, rank = strconv.FormatFloat(c.Percent, 'f', 2, 64), strconv.Itoa(c.Rank)
+		}
+		cw.Write([]string{c.Name, percent, c.Letter, rank})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Write writes the class summary followed by every report card.
+func (r *Report) Write(w io.Writer) error {
+	return reportTemplate.ExecuteTemplate(w, "report", r)
+}
+
+// Write writes the report card.
+func (c *Card) Write(w io.Writer) error {
+	return reportTemplate.ExecuteTemplate(w, "card", c)
+}
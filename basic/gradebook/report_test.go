@@ -0,0 +1,63 @@
+package gradebook
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCard(t *testing.T) {
+	r := Sample().Report(Curves[0])
+	card, ok := r.Card("Bob")
+	if !ok {
+		t.Fatal("no card for Bob")
+	}
+	var buf bytes.Buffer
+	if err := card.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `Report card: Bob
+================
+assessment     weight     score       %  class mean
+Homework 1         10     15/20    75.0        80.7
+Homework 2         10   missing     0.0        72.1
+Quiz               10      7/10    70.0        75.4
+Midterm            30    72/100    72.0        74.2
+Final exam         40    64/100    64.0        75.1
+Final: 61.7%, grade D, rank 11 of 14
+`
+	if buf.String() != want {
+		t.Errorf("got\n%swant\n%s", buf.String(), want)
+	}
+	total := 0
+	for _, lc := range r.Distribution {
+		total += lc.Count
+	}
+	if total != len(r.Cards) {
+		t.Errorf("the letter counts add up to %d for %d students", total, len(r.Cards))
+	}
+}
+
+// TestAllExcused checks that a student excused from everything is
+// incomplete and not ranked.
+func TestAllExcused(t *testing.T) {
+	b, err := Read(strings.NewReader("name,Quiz,Test\nAl,ex,EX\nBea,5,60\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := b.Report(Curves[0])
+	al, _ := r.Card("Al")
+	if al.Letter != Incomplete || al.Rank != 0 || r.Final.N != 1 {
+		t.Errorf("Al has %s, rank %d; %d students ranked", al.Letter, al.Rank, r.Final.N)
+	}
+	if last := r.Distribution[len(r.Distribution)-1]; last != (LetterCount{Letter: Incomplete, Count: 1}) {
+		t.Errorf("distribution ends %v", last)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "name,percent,letter,rank\nAl,,I,\nBea,32.50,F,1\n"; buf.String() != want {
+		t.Errorf("exported\n%swant\n%s", buf.String(), want)
+	}
+}
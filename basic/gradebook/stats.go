@@ -0,0 +1,34 @@
+package gradebook
+
+import (
+	"math"
+	"slices"
+
+	"github.com/amiiralihassanpour/golang_learning/stats"
+)
+
+// Stats describes a set of percentages.
+type Stats struct {
+	N                int
+	Mean, StdDev     float64
+	Min, Max         float64
+	P25, Median, P75 float64
+}
+
+// Describe returns the statistics of xs, ignoring NaNs. StdDev is the
+// population standard deviation: the class is the whole population, not
+// a sample of a larger one.
+func Describe(xs []float64) Stats {
+	sorted := slices.DeleteFunc(slices.Clone(xs), math.IsNaN)
+	slices.Sort(sorted)
+	return Stats{
+		N:      len(sorted),
+		Mean:   stats.Mean(sorted),
+		StdDev: stats.PopStdDev(sorted),
+		Min:    stats.QuantileSorted(sorted, 0),
+		P25:    stats.QuantileSorted(sorted, 0.25),
+		Median: stats.QuantileSorted(sorted, 0.5),
+		P75:    stats.QuantileSorted(sorted, 0.75),
+		Max:    stats.QuantileSorted(sorted, 1),
+	}
+}
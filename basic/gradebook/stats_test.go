@@ -0,0 +1,28 @@
+package gradebook
+
+import (
+	"math"
+	"testing"
+)
+
+// near reports whether two numbers agree to within a relative error of
+// 1e-12, which allows for rounding in the last few bits.
+func near(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-12*math.Max(1, math.Abs(want))
+}
+
+func TestDescribe(t *testing.T) {
+	// The textbook example whose population standard deviation is exactly
+	// 2, with a NaN to be ignored.
+	s := Describe([]float64{2, 4, 4, 4, 5, 5, 7, 9, math.NaN()})
+	want := Stats{N: 8, Mean: 5, StdDev: 2, Min: 2, Max: 9, P25: 4, Median: 4.5, P75: 5.5}
+	if s != want {
+		t.Errorf("got %+v\nwant %+v", s, want)
+	}
+	// A large mean with a small spread: subtracting the squared mean
+	// would lose every digit of the variance.
+	s = Describe([]float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16})
+	if !near(s.StdDev, math.Sqrt(22.5)) {
+		t.Errorf("standard deviation %v, want %v", s.StdDev, math.Sqrt(22.5))
+	}
+}
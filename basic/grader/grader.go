@@ -0,0 +1,232 @@
+// Package grader builds submitted Go programs and runs them against test
+// cases in a sandbox.
+//
+// Each submission is copied into a fresh temporary module with no
+// dependencies and built with the module proxy turned off. Every test case
+// then runs the binary as a subprocess with:
+//
+//   - a CPU-time limit and a memory limit, enforced with rlimits on Linux
+//   - a wall-clock timeout
+//   - no network: a separate network namespace where the kernel allows it,
+//     otherwise proxy variables pointing nowhere (an env guard)
+//   - caps on the size of stdout and stderr
+//
+// The output is compared with the expected output after normalisation,
+// and the results are plain structs ready to be written as JSON.
+package grader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits bounds the resources of one test case run. A field left at zero
+// sets no limit on its resource.
+type Limits struct {
+	// CPU is the CPU time the program may use. The kernel counts whole
+	// seconds, so on Linux the hard limit is rounded up.
+	CPU time.Duration
+	// Wall is the real time after which the program is killed.
+	Wall time.Duration
+	// Memory caps the writable memory of the program, in bytes. It is
+	// enforced as RLIMIT_DATA rather than RLIMIT_AS: the Go runtime
+	// reserves far more address space than it ever uses and would not even
+	// start under a tight address-space limit.
+	Memory int64
+	// Output caps stdout and stderr, in bytes each.
+	Output int64
+}
+
+// DefaultLimits fit the exercises of this module with room to spare.
+var DefaultLimits = Limits{
+	CPU:    2 * time.Second,
+	Wall:   5 * time.Second,
+	Memory: 512 << 20,
+	Output: 64 << 10,
+}
+
+// Case is one test case: a run of the program with its expected output.
+type Case struct {
+	Name  string   `json:"name"`
+	Args  []string `json:"args,omitempty"`
+	Stdin string   `json:"stdin,omitempty"`
+	Want  string   `json:"want"`
+}
+
+// Config is an assignment: its test cases and how outputs are compared.
+type Config struct {
+	Cases     []Case    `json:"cases"`
+	Normalize Normalize `json:"normalize"`
+	// Limits apply to every case. The zero Limits, with no field set,
+	// stands for DefaultLimits rather than for no limits at all.
+	Limits Limits `json:"-"`
+}
+
+// Status is the outcome of one test case.
+type Status string
+
+const (
+	Passed       Status = "passed"
+	WrongAnswer  Status = "wrong-answer"
+	RuntimeError Status = "runtime-error"
+	Timeout      Status = "timeout"
+	CPULimit     Status = "cpu-limit"
+	MemoryLimit  Status = "memory-limit"
+	OutputLimit  Status = "output-limit"
+	NotRun       Status = "not-run"
+)
+
+// CaseResult is the outcome of one test case.
+type CaseResult struct {
+	Name     string `json:"name"`
+	Status   Status `json:"status"`
+	ExitCode int    `json:"exit_code"`
+	CPUMs    int64  `json:"cpu_ms"`
+	WallMs   int64  `json:"wall_ms"`
+	// Diff points at the first difference for wrong answers.
+	Diff string `json:"diff,omitempty"`
+	// Stdout and Stderr are kept for failed cases only.
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+}
+
+// Report is the outcome for one submission.
+type Report struct {
+	Submission string `json:"submission"`
+	Built      bool   `json:"built"`
+	// BuildOutput holds the compiler errors when the build failed.
+	BuildOutput string `json:"build_output,omitempty"`
+	// Network says how the network was cut off: "namespace" or "env".
+	Network string `json:"network,omitempty"`
+	// Rlimits reports whether the CPU and memory limits were enforced by
+	// the kernel; elsewhere CPU time is only checked afterwards.
+	Rlimits bool         `json:"rlimits"`
+	Cases   []CaseResult `json:"cases"`
+	Passed  int          `json:"passed"`
+	Total   int          `json:"total"`
+}
+
+// Grade builds the submission in dir and runs every case of cfg.
+func Grade(ctx context.Context, dir string, cfg Config) *Report {
+	if cfg.Limits == (Limits{}) {
+		cfg.Limits = DefaultLimits
+	}
+	r := &Report{Submission: dir, Total: len(cfg.Cases), Rlimits: enforcesLimits}
+	fail := func(format string, args ...any) *Report {
+		r.BuildOutput = fmt.Sprintf(format, args...)
+		for _, c := range cfg.Cases {
+			r.Cases = append(r.Cases, CaseResult{Name: c.Name, Status: NotRun})
+		}
+		return r
+	}
+
+	tmp, err := os.MkdirTemp("", "grader-*")
+	if err != nil {
+		return fail("%v", err)
+	}
+	defer os.RemoveAll(tmp)
+	bin, out, err := build(ctx, dir, tmp)
+	if err != nil {
+		return fail("%s", out)
+	}
+	r.Built = true
+
+	for i, c := range cfg.Cases {
+		work := filepath.Join(tmp, fmt.Sprintf("case%d", i))
+		if err := os.Mkdir(work, 0o755); err != nil {
+			return fail("%v", err)
+		}
+		res, network := runCase(ctx, bin, work, c, cfg)
+		r.Network = network
+		if res.Status == Passed {
+			r.Passed++
+		}
+		r.Cases = append(r.Cases, res)
+	}
+	return r
+}
+
+// GradeAll grades the submissions with up to workers at a time. The
+// reports are in the order of dirs.
+func GradeAll(ctx context.Context, dirs []string, cfg Config, workers int) []*Report {
+	reports := make([]*Report, len(dirs))
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, dir := range dirs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			reports[i] = Grade(ctx, dir, cfg)
+		}()
+	}
+	wg.Wait()
+	return reports
+}
+
+// Normalize lists the differences between the output and the expected
+// output that do not count.
+type Normalize struct {
+	// LineEndings treats "\r\n" as "\n".
+	LineEndings bool `json:"line_endings"`
+	// TrailingSpace ignores spaces and tabs at the end of each line.
+	TrailingSpace bool `json:"trailing_space"`
+	// TrailingNewlines ignores blank lines at the end.
+	TrailingNewlines bool `json:"trailing_newlines"`
+	// CollapseSpace treats every run of spaces and tabs as one space.
+	CollapseSpace bool `json:"collapse_space"`
+	// IgnoreCase compares letters without regard to case.
+	IgnoreCase bool `json:"ignore_case"`
+}
+
+// DefaultNormalize ignores differences nobody can see in a terminal.
+var DefaultNormalize = Normalize{LineEndings: true, TrailingSpace: true, TrailingNewlines: true}
+
+// Apply returns s normalised.
+func (n Normalize) Apply(s string) string {
+	if n.LineEndings {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+	}
+	if n.IgnoreCase {
+		s = strings.ToLower(s)
+	}
+	if n.TrailingSpace || n.CollapseSpace {
+		lines := strings.Split(s, "\n")
+		for i, line := range lines {
+			if n.CollapseSpace {
+				line = strings.Join(strings.FieldsFunc(line, func(r rune) bool { return r == ' ' || r == '\t' }), " ")
+			}
+			if n.TrailingSpace {
+				line = strings.TrimRight(line, " \t")
+			}
+			lines[i] = line
+		}
+		s = strings.Join(lines, "\n")
+	}
+	if n.TrailingNewlines {
+		s = strings.TrimRight(s, "\n")
+	}
+	return s
+}
+
+// diff describes the first line where got and want differ.
+func diff(got, want string) string {
+	g, w := strings.Split(got, "\n"), strings.Split(want, "\n")
+	for i := range max(len(g), len(w)) {
+		switch {
+		case i >= len(g):
+			return fmt.Sprintf("line %d: output ends, want %q", i+1, w[i])
+		case i >= len(w):
+			return fmt.Sprintf("line %d: got %q, want end of output", i+1, g[i])
+		case g[i] != w[i]:
+			return fmt.Sprintf("line %d: got %q, want %q", i+1, g[i], w[i])
+		}
+	}
+	return ""
+}
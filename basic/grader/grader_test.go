@@ -0,0 +1,160 @@
+package grader
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMain lets the test binary stand in for the grader executable, which
+// runs every submission through itself.
+func TestMain(m *testing.M) {
+	MaybeEnterSandbox()
+	os.Exit(m.Run())
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		n       Normalize
+		in, out string
+	}{
+		{DefaultNormalize, "15 \r\n\r\n\n", "15"},
+		{DefaultNormalize, "a  b\t\nc", "a  b\nc"},
+		{Normalize{CollapseSpace: true}, " a \t b ", "a b"},
+		{Normalize{IgnoreCase: true}, "Hello", "hello"},
+		{Normalize{}, "x \r\n", "x \r\n"},
+	}
+	for _, tt := range tests {
+		if got := tt.n.Apply(tt.in); got != tt.out {
+			t.Errorf("%+v.Apply(%q) = %q, want %q", tt.n, tt.in, got, tt.out)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		got, want, diff string
+	}{
+		{"1\n2", "1\n2", ""},
+		{"1\n3", "1\n2", `line 2: got "3", want "2"`},
+		{"1", "1\n2", `line 2: output ends, want "2"`},
+		{"1\n2", "1", `line 2: got "2", want end of output`},
+	}
+	for _, tt := range tests {
+		if d := diff(tt.got, tt.want); d != tt.diff {
+			t.Errorf("diff(%q, %q) = %q, want %q", tt.got, tt.want, d, tt.diff)
+		}
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	calls := 0
+	b := &cappedBuffer{limit: 5, onFull: func() { calls++ }}
+	for _, s := range []string{"abc", "def", "ghi"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if b.String() != "abcde" || !b.full || calls != 1 {
+		t.Errorf("got %q, full %v, onFull called %d times", b.String(), b.full, calls)
+	}
+
+	unlimited := &cappedBuffer{onFull: func() { t.Error("a buffer without a limit filled up") }}
+	unlimited.WriteString("abc")
+	if unlimited.String() != "abc" || unlimited.full {
+		t.Errorf("without a limit: got %q, full %v", unlimited.String(), unlimited.full)
+	}
+}
+
+// TestGrade grades the sample submissions, which between them cover every
+// status.
+func TestGrade(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs every sample submission")
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "cases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Limits = DefaultLimits
+	cfg.Limits.CPU, cfg.Limits.Wall = time.Second, 3*time.Second
+
+	// Without rlimits, a busy loop runs into the wall-clock timeout and
+	// a memory hog into whatever the machine has.
+	loop, hog := CPULimit, MemoryLimit
+	if !enforcesLimits {
+		loop, hog = Timeout, ""
+	}
+	want := map[string]Status{
+		"good":    Passed,
+		"wrong":   WrongAnswer,
+		"broken":  NotRun,
+		"loop":    loop,
+		"hog":     hog,
+		"spam":    OutputLimit,
+		"offline": Passed,
+	}
+	var dirs []string
+	for name := range want {
+		dirs = append(dirs, filepath.Join("testdata", "submissions", name))
+	}
+	for _, r := range GradeAll(context.Background(), dirs, cfg, 4) {
+		name := filepath.Base(r.Submission)
+		if r.Total != len(cfg.Cases) || len(r.Cases) != r.Total {
+			t.Errorf("%s: %d results for %d cases", name, len(r.Cases), r.Total)
+			continue
+		}
+		if r.Built != (name != "broken") {
+			t.Errorf("%s: built = %v\n%s", name, r.Built, r.BuildOutput)
+		}
+		if name == "offline" && r.Network == "env" {
+			// The env guard stops proxy-aware clients, not a plain dial.
+			t.Log("offline: no network namespace; skipped")
+			continue
+		}
+		if want[name] == "" {
+			continue
+		}
+		for _, c := range r.Cases {
+			if c.Status != want[name] {
+				t.Errorf("%s/%s: %s, want %s (exit %d)", name, c.Name, c.Status, want[name], c.ExitCode)
+			}
+		}
+		if want[name] == Passed && r.Passed != r.Total {
+			t.Errorf("%s: %d of %d passed", name, r.Passed, r.Total)
+		}
+	}
+}
+
+// TestZeroLimits checks that a limit left at 0 is no limit: without a
+// wall-clock timeout or an output cap a correct submission still passes.
+func TestZeroLimits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs a sample submission")
+	}
+	data, err := os.ReadFile(filepath.Join("testdata", "cases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Limits = Limits{CPU: DefaultLimits.CPU}
+	r := Grade(context.Background(), filepath.Join("testdata", "submissions", "good"), cfg)
+	for _, c := range r.Cases {
+		if c.Status != Passed {
+			t.Errorf("%s: %s (exit %d)\n%s", c.Name, c.Status, c.ExitCode, c.Stderr)
+		}
+	}
+	if r.Passed != r.Total || r.Total == 0 {
+		t.Errorf("%d of %d passed", r.Passed, r.Total)
+	}
+}
@@ -0,0 +1,195 @@
+package grader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// build copies the non-test Go files of dir into a module of their own
+// under tmp and builds them. Submissions may only use the standard
+// library: the module proxy is off.
+func build(ctx context.Context, dir, tmp string) (bin, out string, err error) {
+	src := filepath.Join(tmp, "src")
+	if err := os.Mkdir(src, 0o755); err != nil {
+		return "", err.Error(), err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err.Error(), err
+	}
+	n := 0
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "", err.Error(), err
+		}
+		if err := os.WriteFile(filepath.Join(src, name), data, 0o644); err != nil {
+			return "", err.Error(), err
+		}
+		n++
+	}
+	if n == 0 {
+		err := errors.New("no Go files in submission")
+		return "", err.Error(), err
+	}
+	if err := os.WriteFile(filepath.Join(src, "go.mod"), []byte("module submission\n\ngo 1.25\n"), 0o644); err != nil {
+		return "", err.Error(), err
+	}
+
+	bin = filepath.Join(tmp, "prog")
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", bin, ".")
+	cmd.Dir = src
+	cmd.Env = append(os.Environ(), "GOPROXY=off", "GOWORK=off", "GOFLAGS=-mod=mod", "CGO_ENABLED=0")
+	output, err := cmd.CombinedOutput()
+	return bin, string(output), err
+}
+
+// noNamespace is set once creating a network namespace has failed, so
+// later runs go straight to the env guard.
+var noNamespace atomic.Bool
+
+// envGuard points every proxy variable at a closed port, which stops
+// HTTP clients that honour them.
+var envGuard = []string{
+	"HTTP_PROXY=http://127.0.0.1:9", "HTTPS_PROXY=http://127.0.0.1:9", "ALL_PROXY=http://127.0.0.1:9",
+	"http_proxy=http://127.0.0.1:9", "https_proxy=http://127.0.0.1:9", "all_proxy=http://127.0.0.1:9",
+	"NO_PROXY=", "no_proxy=", "GRADER_NO_NETWORK=1",
+}
+
+// runCase runs one test case and returns its result and how the network
+// was cut off.
+func runCase(ctx context.Context, bin, work string, c Case, cfg Config) (CaseResult, string) {
+	res := CaseResult{Name: c.Name}
+	l := cfg.Limits
+	var cancel context.CancelFunc
+	if l.Wall > 0 {
+		ctx, cancel = context.WithTimeout(ctx, l.Wall)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: l.Output, onFull: cancel}
+	stderr := &cappedBuffer{limit: l.Output, onFull: cancel}
+	env := []string{"PATH=" + os.Getenv("PATH"), "HOME=" + work, "TMPDIR=" + work}
+	newCmd := func(isolate bool) *exec.Cmd {
+		cmd := sandboxCommand(ctx, bin, c.Args, l)
+		cmd.Dir = work
+		cmd.Env = append(cmd.Env, env...)
+		cmd.Stdin = strings.NewReader(c.Stdin)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		cmd.WaitDelay = time.Second
+		if isolate {
+			isolateNetwork(cmd)
+		} else {
+			cmd.Env = append(cmd.Env, envGuard...)
+		}
+		return cmd
+	}
+
+	network := "namespace"
+	cmd := newCmd(!noNamespace.Load())
+	start := time.Now()
+	err := cmd.Start()
+	if err != nil && cmd.SysProcAttr != nil {
+		noNamespace.Store(true)
+		cmd = newCmd(false)
+		start = time.Now()
+		err = cmd.Start()
+	}
+	if cmd.SysProcAttr == nil {
+		network = "env"
+	}
+	if err == nil {
+		err = cmd.Wait()
+	}
+	res.WallMs = time.Since(start).Milliseconds()
+	var cpu time.Duration
+	if cmd.ProcessState != nil {
+		cpu = cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	res.CPUMs = cpu.Milliseconds()
+
+	got := cfg.Normalize.Apply(stdout.String())
+	switch {
+	case stdout.full || stderr.full:
+		res.Status = OutputLimit
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		res.Status = Timeout
+	case l.CPU > 0 && cpu >= l.CPU:
+		res.Status = CPULimit
+	case err != nil && isOutOfMemory(stderr.String()):
+		res.Status = MemoryLimit
+	case err != nil:
+		res.Status = RuntimeError
+		if cmd.ProcessState == nil {
+			stderr.WriteString(fmt.Sprintf("grader: %v\n", err))
+		}
+	case got == cfg.Normalize.Apply(c.Want):
+		res.Status = Passed
+	default:
+		res.Status = WrongAnswer
+		res.Diff = diff(got, cfg.Normalize.Apply(c.Want))
+	}
+	if res.Status != Passed {
+		res.Stdout = stdout.String()
+		res.Stderr = stderr.String()
+	}
+	return res, network
+}
+
+func isOutOfMemory(stderr string) bool {
+	return strings.Contains(stderr, "out of memory") || strings.Contains(stderr, "cannot allocate memory")
+}
+
+// cappedBuffer keeps up to limit bytes, or everything if limit is 0, and
+// calls onFull once when more arrive. It keeps accepting writes so the program does not block on a
+// full pipe before it is killed.
+type cappedBuffer struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	limit  int64
+	full   bool
+	onFull func()
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - int64(b.buf.Len()); b.limit > 0 && int64(len(p)) > room {
+		b.buf.Write(p[:max(room, 0)])
+		if !b.full {
+			b.full = true
+			b.onFull()
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) WriteString(s string) {
+	b.Write([]byte(s))
+}
+
+func (b *cappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,109 @@
+//go:build linux
+
+package grader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// enforcesLimits reports whether CPU and memory limits are set by the
+// kernel on this platform.
+const enforcesLimits = true
+
+// sandboxEnv carries the limits to the re-executed grader binary. The
+// rlimits cannot be set on a child from the outside, so the grader starts
+// itself with this variable set; MaybeEnterSandbox applies the limits to
+// its own process and then replaces itself with the submission, which
+// inherits them.
+const sandboxEnv = "GRADER_SANDBOX_LIMITS"
+
+// MaybeEnterSandbox must be the first thing main does in a program that
+// calls Grade, because the grader runs submissions by starting its own
+// executable again. In such a process it applies the limits and replaces
+// the process with the submission, and never returns; otherwise it returns
+// at once.
+func MaybeEnterSandbox() {
+	if spec, ok := os.LookupEnv(sandboxEnv); ok {
+		err := enterSandbox(spec)
+		fmt.Fprintf(os.Stderr, "grader sandbox: %v\n", err)
+		os.Exit(126)
+	}
+}
+
+// enterSandbox applies the limits in spec ("cpu=2,data=536870912,fsize=65536")
+// and execs os.Args[1]. It only returns on failure.
+func enterSandbox(spec string) error {
+	resources := map[string]int{"cpu": syscall.RLIMIT_CPU, "data": syscall.RLIMIT_DATA, "fsize": syscall.RLIMIT_FSIZE}
+	for _, kv := range strings.Split(spec, ",") {
+		key, val, _ := strings.Cut(kv, "=")
+		resource, ok := resources[key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad limit %q", kv)
+		}
+		lim := syscall.Rlimit{Cur: n, Max: n}
+		if resource == syscall.RLIMIT_CPU {
+			// SIGXCPU at the soft limit, SIGKILL a second later for
+			// programs that ignore it, as Go programs do.
+			lim.Max = n + 1
+		}
+		if err := syscall.Setrlimit(resource, &lim); err != nil {
+			return fmt.Errorf("setrlimit %s: %w", key, err)
+		}
+	}
+	if len(os.Args) < 2 {
+		return fmt.Errorf("no program to run")
+	}
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, sandboxEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+	return syscall.Exec(os.Args[1], os.Args[1:], env)
+}
+
+// sandboxCommand returns the command that runs bin under the limits.
+func sandboxCommand(ctx context.Context, bin string, args []string, l Limits) *exec.Cmd {
+	self, err := os.Executable()
+	if err != nil {
+		// Without a way back into the grader, run without rlimits.
+		return exec.CommandContext(ctx, bin, args...)
+	}
+	var spec []string
+	if l.CPU > 0 {
+		spec = append(spec, fmt.Sprintf("cpu=%d", (l.CPU+time.Second-1)/time.Second))
+	}
+	if l.Memory > 0 {
+		spec = append(spec, fmt.Sprintf("data=%d", l.Memory))
+	}
+	if l.Output > 0 {
+		spec = append(spec, fmt.Sprintf("fsize=%d", l.Output))
+	}
+	cmd := exec.CommandContext(ctx, self, append([]string{bin}, args...)...)
+	cmd.Env = []string{sandboxEnv + "=" + strings.Join(spec, ",")}
+	return cmd
+}
+
+// isolateNetwork starts the command in a new network namespace, which has
+// only a loopback interface that is down. Unprivileged users need a user
+// namespace for that, mapping their own IDs.
+func isolateNetwork(cmd *exec.Cmd) {
+	attr := &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWNET}
+	if uid, gid := os.Getuid(), os.Getgid(); uid != 0 {
+		attr.Cloneflags |= syscall.CLONE_NEWUSER
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+	}
+	cmd.SysProcAttr = attr
+}
@@ -0,0 +1,12 @@
+// Does not compile: sum returns nothing.
+package main
+
+import "fmt"
+
+func sum(a int, b int) {
+	return a + b
+}
+
+func main() {
+	fmt.Println(sum(5, 10))
+}
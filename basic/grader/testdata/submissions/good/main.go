@@ -0,0 +1,23 @@
+// Prints the sum of two integers, read from the arguments or from stdin.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func sum(a int, b int) int {
+	return a + b
+}
+
+func main() {
+	var a, b int
+	if len(os.Args) == 3 {
+		a, _ = strconv.Atoi(os.Args[1])
+		b, _ = strconv.Atoi(os.Args[2])
+	} else {
+		fmt.Scan(&a, &b)
+	}
+	fmt.Println(sum(a, b))
+}
@@ -0,0 +1,15 @@
+// Keeps every slice it appends to, until memory runs out.
+package main
+
+import "fmt"
+
+func main() {
+	var keep [][]byte
+	for {
+		keep = append(keep, make([]byte, 64<<20))
+		keep[len(keep)-1][0] = 1
+		if len(keep) > 1<<20 {
+			fmt.Println(len(keep))
+		}
+	}
+}
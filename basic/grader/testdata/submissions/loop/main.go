@@ -0,0 +1,12 @@
+// Never finishes: i<=5 with i-- counts down forever.
+package main
+
+import "fmt"
+
+func main() {
+	n := 0
+	for i := 1; i <= 5; i-- {
+		n += i
+	}
+	fmt.Println(n)
+}
@@ -0,0 +1,26 @@
+// Tries to look the answer up online before adding.
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+func main() {
+	conn, err := net.DialTimeout("tcp", "proxy.golang.org:443", 2*time.Second)
+	if err == nil {
+		conn.Close()
+		fmt.Fprintln(os.Stderr, "network is reachable")
+		os.Exit(1)
+	}
+	var a, b int
+	if len(os.Args) == 3 {
+		fmt.Sscan(os.Args[1], &a)
+		fmt.Sscan(os.Args[2], &b)
+	} else {
+		fmt.Scan(&a, &b)
+	}
+	fmt.Println(a + b)
+}
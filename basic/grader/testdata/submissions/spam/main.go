@@ -0,0 +1,10 @@
+// Prints the answer over and over.
+package main
+
+import "fmt"
+
+func main() {
+	for {
+		fmt.Println(15)
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DOTOptions controls how WriteDOT draws a graph.
+type DOTOptions[N comparable] struct {
+	// Name is the graph's name in the output; it defaults to "G".
+	Name string
+	// Label returns the text shown for a node; it defaults to fmt.Sprint.
+	Label func(N) string
+	// Highlight lists edges to draw in bold red, such as a shortest path
+	// or a spanning tree. An edge matches if its ends match, either way
+	// round in an undirected graph.
+	Highlight []Edge[N]
+	// Clusters lists groups of nodes to draw in boxes, such as strongly
+	// connected components.
+	Clusters [][]N
+}
+
+// WriteDOT writes g in the Graphviz DOT language, for example to render
+// with:
+//
+//	dot -Tsvg graph.dot > graph.svg
+//
+// Edges are labelled with their weights unless every weight is 1.
+func (g *Graph[N]) WriteDOT(w io.Writer, opts DOTOptions[N]) error {
+	name := opts.Name
+	if name == "" {
+		name = "G"
+	}
+	label := opts.Label
+	if label == nil {
+		label = func(n N) string { return fmt.Sprint(n) }
+	}
+	id := func(n N) string { return strconv.Quote(label(n)) }
+	type pair struct{ from, to N }
+	hi := map[pair]bool{}
+	for _, e := range opts.Highlight {
+		hi[pair{e.From, e.To}] = true
+		if !g.directed {
+			hi[pair{e.To, e.From}] = true
+		}
+	}
+	edges := g.Edges()
+	weighted := false
+	for _, e := range edges {
+		weighted = weighted || e.Weight != 1
+	}
+	kind, arrow := "digraph", "->"
+	if !g.directed {
+		kind, arrow = "graph", "--"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s {\n", kind, strconv.Quote(name))
+	fmt.Fprintf(bw, "\tnode [shape=circle];\n")
+	clustered := map[N]bool{}
+	for k, c := range opts.Clusters {
+		fmt.Fprintf(bw, "\tsubgraph cluster_%d {\n", k)
+		for _, n := range c {
+			fmt.Fprintf(bw, "\t\t%s;\n", id(n))
+			clustered[n] = true
+		}
+		fmt.Fprintf(bw, "\t}\n")
+	}
+	for _, n := range g.nodes {
+		if !clustered[n] {
+			fmt.Fprintf(bw, "\t%s;\n", id(n))
+		}
+	}
+	for _, e := range edges {
+		var attrs []string
+		if weighted {
+			attrs = append(attrs, "label="+strconv.Quote(strconv.FormatFloat(e.Weight, 'g', -1, 64)))
+		}
+		if hi[pair{e.From, e.To}] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		fmt.Fprintf(bw, "\t%s %s %s", id(e.From), arrow, id(e.To))
+		if len(attrs) > 0 {
+			fmt.Fprint(bw, " [")
+			for i, a := range attrs {
+				if i > 0 {
+					fmt.Fprint(bw, ", ")
+				}
+				fmt.Fprint(bw, a)
+			}
+			fmt.Fprint(bw, "]")
+		}
+		fmt.Fprint(bw, ";\n")
+	}
+	fmt.Fprint(bw, "}\n")
+	return bw.Flush()
+}
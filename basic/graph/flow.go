@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+)
+
+// Flow is a maximum flow from a source to a sink, with edge weights read
+// as capacities.
+type Flow[N comparable] struct {
+	// Value is the total flow out of the source, which by the max-flow
+	// min-cut theorem equals the capacity of Cut.
+	Value float64
+	// Edges lists the edges that carry flow, with Weight set to the flow
+	// on them.
+	Edges []Edge[N]
+	// Cut lists the nodes still reachable from the source once the flow
+	// is saturated: the source side of a minimum cut.
+	Cut []N
+}
+
+// arc is an edge of the residual network. Each arc has a partner going
+// the other way, at adj[to][rev], so pushing flow along one frees the
+// same amount of capacity on the other.
+type arc struct {
+	to, rev   int
+	cap, flow float64
+	// edge is the index of the original edge in the list from Edges, or -1
+	// for the reverse arc of a directed edge.
+	edge int
+}
+
+// MaxFlow computes a maximum flow from source to sink with the
+// Edmonds-Karp algorithm: while the residual network has a path from
+// source to sink, it pushes as much flow as that path allows. Taking the
+// shortest such path each time, found with breadth-first search, bounds
+// the number of rounds by nodes times edges whatever the capacities are.
+// An undirected edge can carry flow either way.
+func (g *Graph[N]) MaxFlow(source, sink N) (*Flow[N], error) {
+	s, ok1 := g.index[source]
+	t, ok2 := g.index[sink]
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("graph: no node %v or %v", source, sink)
+	}
+	if s == t {
+		return nil, fmt.Errorf("graph: source and sink are both %v", source)
+	}
+	edges := g.Edges()
+	res := make([][]arc, len(g.nodes))
+	for k, e := range edges {
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("graph: edge %v -> %v has negative capacity %g", e.From, e.To, e.Weight)
+		}
+		i, j := g.index[e.From], g.index[e.To]
+		back := 0.0
+		backEdge := -1
+		if !g.directed {
+			back, backEdge = e.Weight, k
+		}
+		res[i] = append(res[i], arc{to: j, rev: len(res[j]), cap: e.Weight, edge: k})
+		res[j] = append(res[j], arc{to: i, rev: len(res[i]) - 1, cap: back, edge: backEdge})
+	}
+
+	f := &Flow[N]{}
+	// prev[j] is the arc by which the search reached j, as a node and an
+	// index into its arcs.
+	type step struct{ node, arc int }
+	prev := make([]step, len(g.nodes))
+	for {
+		for i := range prev {
+			prev[i] = step{-1, -1}
+		}
+		prev[s] = step{s, -1}
+		queue := []int{s}
+		for len(queue) > 0 && prev[t].node < 0 {
+			i := queue[0]
+			queue = queue[1:]
+			for k, a := range res[i] {
+				if prev[a.to].node < 0 && a.cap-a.flow > 0 {
+					prev[a.to] = step{i, k}
+					queue = append(queue, a.to)
+				}
+			}
+		}
+		if prev[t].node < 0 {
+			break
+		}
+		push := math.Inf(1)
+		for j := t; j != s; j = prev[j].node {
+			a := res[prev[j].node][prev[j].arc]
+			push = min(push, a.cap-a.flow)
+		}
+		for j := t; j != s; j = prev[j].node {
+			a := &res[prev[j].node][prev[j].arc]
+			a.flow += push
+			res[a.to][a.rev].flow -= push
+		}
+		f.Value += push
+	}
+
+	// The last search stopped at the cut: the nodes it reached.
+	for i, p := range prev {
+		if p.node >= 0 {
+			f.Cut = append(f.Cut, g.nodes[i])
+		}
+	}
+	for i := range res {
+		for _, a := range res[i] {
+			if a.edge >= 0 && a.flow > 0 {
+				f.Edges = append(f.Edges, Edge[N]{g.nodes[i], g.nodes[a.to], a.flow})
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,179 @@
+// Package graph implements weighted directed and undirected graphs as
+// adjacency lists, and the classic algorithms on them: breadth- and
+// depth-first search, topological sorting, shortest paths (Dijkstra, A*
+// and Bellman-Ford), minimum spanning trees (Kruskal and Prim), strongly
+// connected components (Tarjan) and maximum flow (Edmonds-Karp).
+//
+// Nodes can be any comparable type. They are kept in the order they were
+// added, and so are the edges of each node, which makes every algorithm's
+// output deterministic. WriteDOT draws a graph with Graphviz, optionally
+// highlighting the edges of a result such as a path or a spanning tree.
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Edge is a weighted edge. In an undirected graph it can be followed
+// both ways.
+type Edge[N comparable] struct {
+	From, To N
+	Weight   float64
+}
+
+// Graph is a weighted graph stored as adjacency lists.
+type Graph[N comparable] struct {
+	directed bool
+	nodes    []N
+	index    map[N]int
+	// adj[i] lists the edges leaving nodes[i]. An undirected edge is
+	// stored once in each direction.
+	adj [][]Edge[N]
+	m   int
+}
+
+// NewDirected returns an empty directed graph.
+func NewDirected[N comparable]() *Graph[N] {
+	return &Graph[N]{directed: true, index: map[N]int{}}
+}
+
+// NewUndirected returns an empty undirected graph.
+func NewUndirected[N comparable]() *Graph[N] {
+	return &Graph[N]{index: map[N]int{}}
+}
+
+// Directed reports whether edges have a direction.
+func (g *Graph[N]) Directed() bool { return g.directed }
+
+// AddNode adds n if it is not in the graph yet, and returns its index.
+func (g *Graph[N]) AddNode(n N) int {
+	if i, ok := g.index[n]; ok {
+		return i
+	}
+	g.index[n] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	g.adj = append(g.adj, nil)
+	return len(g.nodes) - 1
+}
+
+// AddEdge adds an edge, and its nodes if they are new. Parallel edges are
+// allowed.
+func (g *Graph[N]) AddEdge(from, to N, weight float64) {
+	i, j := g.AddNode(from), g.AddNode(to)
+	g.adj[i] = append(g.adj[i], Edge[N]{from, to, weight})
+	if !g.directed && i != j {
+		g.adj[j] = append(g.adj[j], Edge[N]{to, from, weight})
+	}
+	g.m++
+}
+
+// Has reports whether n is a node of the graph.
+func (g *Graph[N]) Has(n N) bool {
+	_, ok := g.index[n]
+	return ok
+}
+
+// Nodes returns the nodes in the order they were added.
+func (g *Graph[N]) Nodes() []N { return g.nodes }
+
+// Order returns the number of nodes.
+func (g *Graph[N]) Order() int { return len(g.nodes) }
+
+// Size returns the number of edges, counting an undirected edge once.
+func (g *Graph[N]) Size() int { return g.m }
+
+// Out returns the edges leaving n. For an undirected graph these are all
+// the edges at n, each with From set to n.
+func (g *Graph[N]) Out(n N) []Edge[N] {
+	i, ok := g.index[n]
+	if !ok {
+		return nil
+	}
+	return g.adj[i]
+}
+
+// Edges returns every edge once, in the order of their From nodes.
+func (g *Graph[N]) Edges() []Edge[N] {
+	var out []Edge[N]
+	for i, edges := range g.adj {
+		for _, e := range edges {
+			// An undirected edge is kept at both ends; report it from the
+			// end that was added first.
+			if g.directed || i <= g.index[e.To] {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// Reverse returns the graph with every edge turned around. An undirected
+// graph is returned as it is.
+func (g *Graph[N]) Reverse() *Graph[N] {
+	if !g.directed {
+		return g
+	}
+	r := NewDirected[N]()
+	for _, n := range g.nodes {
+		r.AddNode(n)
+	}
+	for _, e := range g.Edges() {
+		r.AddEdge(e.To, e.From, e.Weight)
+	}
+	return r
+}
+
+// Read parses a graph with string nodes from a simple text format:
+//
+//	# comments and blank lines are ignored
+//	directed          # or undirected; must come first
+//	a b 4             # an edge from a to b with weight 4
+//	b c               # the weight defaults to 1
+//	d                 # a node with no edges
+func Read(r io.Reader) (*Graph[string], error) {
+	var g *Graph[string]
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if g == nil {
+			switch fields[0] {
+			case "directed":
+				g = NewDirected[string]()
+			case "undirected":
+				g = NewUndirected[string]()
+			default:
+				return nil, fmt.Errorf("graph: line %d: want directed or undirected, found %q", line, fields[0])
+			}
+			continue
+		}
+		switch len(fields) {
+		case 1:
+			g.AddNode(fields[0])
+		case 2:
+			g.AddEdge(fields[0], fields[1], 1)
+		case 3:
+			w, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("graph: line %d: bad weight %q", line, fields[2])
+			}
+			g.AddEdge(fields[0], fields[1], w)
+		default:
+			return nil, fmt.Errorf("graph: line %d: want from, to and weight, found %d fields", line, len(fields))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph: empty input")
+	}
+	return g, nil
+}
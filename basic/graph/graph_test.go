@@ -0,0 +1,295 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// The fixtures in testdata are textbook graphs whose answers are
+// published, mostly figures from CLRS.
+func fixture(t *testing.T, name string) *Graph[string] {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name+".txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := Read(f)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return g
+}
+
+func TestFixtures(t *testing.T) {
+	for _, tt := range fixtureTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.check(fixture(t, tt.fixture)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// fixtureTests run an algorithm on a fixture in testdata and compare the
+// result with the published answer.
+var fixtureTests = []struct {
+	name    string
+	fixture string
+	check   func(g *Graph[string]) error
+}{
+	{"bfs visits by distance", "tree", func(g *Graph[string]) error {
+		var got []string
+		for n, d := range g.BFS("a") {
+			got = append(got, fmt.Sprintf("%s%d", n, d))
+		}
+		return wantNodes("order", got, []string{"a0", "b1", "c1", "d2", "e2", "f2", "g3"})
+	}},
+	{"dfs follows each branch to its end", "tree", func(g *Graph[string]) error {
+		return wantNodes("order", slices.Collect(g.DFS("a")), []string{"a", "b", "d", "e", "g", "c", "f"})
+	}},
+	{"dfs stops early", "tree", func(g *Graph[string]) error {
+		var got []string
+		for n := range g.DFS("a") {
+			if got = append(got, n); n == "e" {
+				break
+			}
+		}
+		return wantNodes("order", got, []string{"a", "b", "d", "e"})
+	}},
+	{"topological sort puts every edge forward", "clothing", func(g *Graph[string]) error {
+		order, err := g.TopoSort()
+		if err != nil {
+			return err
+		}
+		if len(order) != g.Order() {
+			return fmt.Errorf("got %d nodes, want %d", len(order), g.Order())
+		}
+		for _, e := range g.Edges() {
+			if slices.Index(order, e.From) > slices.Index(order, e.To) {
+				return fmt.Errorf("%s comes after %s in %v", e.From, e.To, order)
+			}
+		}
+		return nil
+	}},
+	{"topological sort reports a cycle", "cycle", func(g *Graph[string]) error {
+		_, err := g.TopoSort()
+		var cycle *CycleError[string]
+		if !errors.As(err, &cycle) {
+			return fmt.Errorf("got error %v, want a cycle", err)
+		}
+		return wantNodes("cycle", cycle.Cycle, []string{"a", "b", "c", "a"})
+	}},
+	{"dijkstra", "dijkstra", func(g *Graph[string]) error {
+		p, err := g.Dijkstra("s")
+		if err != nil {
+			return err
+		}
+		if err := wantDists(p, "s 0 t 8 x 9 y 5 z 7"); err != nil {
+			return err
+		}
+		return wantNodes("path to x", Nodes(p.To("x")), []string{"s", "y", "t", "x"})
+	}},
+	{"dijkstra on an undirected graph", "mst", func(g *Graph[string]) error {
+		p, err := g.Dijkstra("a")
+		if err != nil {
+			return err
+		}
+		return wantDists(p, "a 0 b 4 c 12 d 19 e 21 f 11 g 9 h 8 i 14")
+	}},
+	{"dijkstra refuses negative weights", "bellman-ford", func(g *Graph[string]) error {
+		if _, err := g.Dijkstra("s"); err == nil {
+			return errors.New("got no error")
+		}
+		return nil
+	}},
+	{"bellman-ford with negative weights", "bellman-ford", func(g *Graph[string]) error {
+		p, err := g.BellmanFord("s")
+		if err != nil {
+			return err
+		}
+		if err := wantDists(p, "s 0 t 2 x 4 y 7 z -2"); err != nil {
+			return err
+		}
+		return wantNodes("path to z", Nodes(p.To("z")), []string{"s", "y", "x", "t", "z"})
+	}},
+	{"bellman-ford agrees with dijkstra", "dijkstra", func(g *Graph[string]) error {
+		p, err := g.BellmanFord("s")
+		if err != nil {
+			return err
+		}
+		return wantDists(p, "s 0 t 8 x 9 y 5 z 7")
+	}},
+	{"bellman-ford reports a negative cycle", "negative-cycle", func(g *Graph[string]) error {
+		_, err := g.BellmanFord("s")
+		var cycle *NegativeCycleError[string]
+		if !errors.As(err, &cycle) {
+			return fmt.Errorf("got error %v, want a negative cycle", err)
+		}
+		c := cycle.Cycle
+		if len(c) != 4 || c[0] != c[3] {
+			return fmt.Errorf("cycle %v is not a triangle", c)
+		}
+		return wantNodes("cycle nodes", slices.Sorted(slices.Values(c[:3])), []string{"a", "b", "c"})
+	}},
+	{"a* matches dijkstra with no estimate", "dijkstra", func(g *Graph[string]) error {
+		path, length, ok := g.AStar("s", "x", func(string) float64 { return 0 })
+		if !ok || length != 9 {
+			return fmt.Errorf("got length %g, %v, want 9", length, ok)
+		}
+		return wantNodes("path", Nodes(path), []string{"s", "y", "t", "x"})
+	}},
+	{"kruskal", "mst", func(g *Graph[string]) error { return wantMST(g, g.Kruskal) }},
+	{"prim", "mst", func(g *Graph[string]) error { return wantMST(g, g.Prim) }},
+	{"strongly connected components", "scc", func(g *Graph[string]) error {
+		var got []string
+		for _, c := range g.StronglyConnected() {
+			got = append(got, strings.Join(slices.Sorted(slices.Values(c)), ""))
+		}
+		// Tarjan's algorithm finds them in reverse topological order.
+		return wantNodes("components", got, []string{"h", "fg", "cd", "abe"})
+	}},
+	{"max flow", "flow", func(g *Graph[string]) error {
+		f, err := g.MaxFlow("s", "t")
+		if err != nil {
+			return err
+		}
+		if f.Value != 23 {
+			return fmt.Errorf("got flow %g, want 23", f.Value)
+		}
+		// Flow is conserved at every node but the source and sink, and
+		// the cut's capacity equals the flow.
+		net := map[string]float64{}
+		for _, e := range f.Edges {
+			net[e.From] -= e.Weight
+			net[e.To] += e.Weight
+		}
+		for n, v := range net {
+			if n != "s" && n != "t" && v != 0 {
+				return fmt.Errorf("%g more flow goes into %s than out", v, n)
+			}
+		}
+		capacity := 0.0
+		for _, e := range g.Edges() {
+			if slices.Contains(f.Cut, e.From) && !slices.Contains(f.Cut, e.To) {
+				capacity += e.Weight
+			}
+		}
+		if capacity != 23 {
+			return fmt.Errorf("cut %v has capacity %g, want 23", f.Cut, capacity)
+		}
+		return nil
+	}},
+}
+
+// astarGrid is a grid for A*: S is the start, G the goal and # a wall. The
+// bottom row is a dead end, so the shortest route climbs back to the top
+// row and comes down the right-hand side: 15 steps.
+const astarGrid = `
+S..#....
+.#.#.##.
+.#...#..
+.####.#.
+......#G`
+
+func TestAStarGrid(t *testing.T) {
+	type cell = [2]int
+	rows := strings.Split(strings.TrimSpace(astarGrid), "\n")
+	g := NewUndirected[cell]()
+	var start, goal cell
+	for r, row := range rows {
+		for c, ch := range row {
+			if ch == '#' {
+				continue
+			}
+			g.AddNode(cell{r, c})
+			switch ch {
+			case 'S':
+				start = cell{r, c}
+			case 'G':
+				goal = cell{r, c}
+			}
+			if r > 0 && rows[r-1][c] != '#' {
+				g.AddEdge(cell{r - 1, c}, cell{r, c}, 1)
+			}
+			if c > 0 && row[c-1] != '#' {
+				g.AddEdge(cell{r, c - 1}, cell{r, c}, 1)
+			}
+		}
+	}
+	manhattan := func(n cell) float64 {
+		return math.Abs(float64(n[0]-goal[0])) + math.Abs(float64(n[1]-goal[1]))
+	}
+	path, length, ok := g.AStar(start, goal, manhattan)
+	if !ok || length != 15 || len(path) != 15 {
+		t.Errorf("got length %g with %d steps, %v, want 15", length, len(path), ok)
+	}
+	for n, d := range g.BFS(start) {
+		if n == goal && d != 15 {
+			t.Errorf("breadth-first search says %d steps", d)
+		}
+	}
+}
+
+func wantNodes(what string, got, want []string) error {
+	if !slices.Equal(got, want) {
+		return fmt.Errorf("%s: got %v, want %v", what, got, want)
+	}
+	return nil
+}
+
+// wantDists compares distances against "node dist node dist ...".
+func wantDists(p *Paths[string], spec string) error {
+	f := strings.Fields(spec)
+	for i := 0; i < len(f); i += 2 {
+		var d float64
+		fmt.Sscan(f[i+1], &d)
+		if got := p.Dist(f[i]); got != d {
+			return fmt.Errorf("distance to %s: got %g, want %g", f[i], got, d)
+		}
+	}
+	return nil
+}
+
+func wantMST(g *Graph[string], mst func() ([]Edge[string], float64, error)) error {
+	tree, total, err := mst()
+	if err != nil {
+		return err
+	}
+	if total != 37 || len(tree) != g.Order()-1 {
+		return fmt.Errorf("got %d edges weighing %g, want %d weighing 37", len(tree), total, g.Order()-1)
+	}
+	// n-1 edges that connect every node form a tree.
+	t := NewUndirected[string]()
+	for _, e := range tree {
+		t.AddEdge(e.From, e.To, e.Weight)
+	}
+	if n := len(slices.Collect(t.DFS("a"))); n != g.Order() {
+		return fmt.Errorf("the tree reaches %d of %d nodes", n, g.Order())
+	}
+	return nil
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "graph: empty input"},
+		{"# only a comment\n", "graph: empty input"},
+		{"sideways\na b\n", "graph: line 1: want directed or undirected"},
+		{"directed\na b heavy\n", "graph: line 2: bad weight"},
+		{"directed\na b 1 2\n", "graph: line 2: want from, to and weight"},
+	}
+	for _, tt := range tests {
+		_, err := Read(strings.NewReader(tt.in))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("Read(%q): got %v, want %s", tt.in, err, tt.want)
+		}
+	}
+}
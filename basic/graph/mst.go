@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/amiiralihassanpour/golang_learning/containers"
+)
+
+// Kruskal returns a minimum spanning tree of an undirected graph: the
+// edges of least total weight that connect every node. It takes the edges
+// from lightest to heaviest and keeps each one that joins two trees not
+// yet connected, which a union-find structure answers in nearly constant
+// time. If the graph is not connected, the result is a spanning forest
+// with one tree per component.
+func (g *Graph[N]) Kruskal() ([]Edge[N], float64, error) {
+	if g.directed {
+		return nil, 0, fmt.Errorf("graph: spanning trees need an undirected graph")
+	}
+	edges := g.Edges()
+	slices.SortStableFunc(edges, func(a, b Edge[N]) int { return cmp.Compare(a.Weight, b.Weight) })
+	uf := newUnionFind(len(g.nodes))
+	var tree []Edge[N]
+	total := 0.0
+	for _, e := range edges {
+		if uf.union(g.index[e.From], g.index[e.To]) {
+			tree = append(tree, e)
+			total += e.Weight
+		}
+	}
+	return tree, total, nil
+}
+
+// unionFind tracks a partition of 0..n-1 into disjoint sets.
+type unionFind struct {
+	parent, rank []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n), rank: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+// find returns the representative of x's set, pointing every node on the
+// way straight at it so later finds are shorter.
+func (uf *unionFind) find(x int) int {
+	for uf.parent[x] != x {
+		uf.parent[x] = uf.parent[uf.parent[x]]
+		x = uf.parent[x]
+	}
+	return x
+}
+
+// union merges the sets of x and y and reports whether they were apart.
+func (uf *unionFind) union(x, y int) bool {
+	x, y = uf.find(x), uf.find(y)
+	if x == y {
+		return false
+	}
+	// Hang the shallower tree under the deeper one.
+	if uf.rank[x] < uf.rank[y] {
+		x, y = y, x
+	}
+	uf.parent[y] = x
+	if uf.rank[x] == uf.rank[y] {
+		uf.rank[x]++
+	}
+	return true
+}
+
+// Prim returns a minimum spanning tree like Kruskal, but grows it from one
+// node at a time: it repeatedly adds the lightest edge leaving the tree,
+// kept in a heap. It suits dense graphs, where sorting every edge is the
+// expensive part of Kruskal. A disconnected graph gets a spanning forest.
+func (g *Graph[N]) Prim() ([]Edge[N], float64, error) {
+	if g.directed {
+		return nil, 0, fmt.Errorf("graph: spanning trees need an undirected graph")
+	}
+	in := make([]bool, len(g.nodes))
+	h := containers.NewHeap(func(a, b Edge[N]) bool { return a.Weight < b.Weight })
+	var tree []Edge[N]
+	total := 0.0
+	for root := range g.nodes {
+		if in[root] {
+			continue
+		}
+		in[root] = true
+		for _, e := range g.adj[root] {
+			h.Push(e)
+		}
+		for h.Len() > 0 {
+			e, _ := h.Pop()
+			j := g.index[e.To]
+			if in[j] {
+				continue
+			}
+			in[j] = true
+			tree = append(tree, e)
+			total += e.Weight
+			for _, next := range g.adj[j] {
+				if !in[g.index[next.To]] {
+					h.Push(next)
+				}
+			}
+		}
+	}
+	return tree, total, nil
+}
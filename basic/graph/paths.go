@@ -0,0 +1,232 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/containers"
+)
+
+// Paths holds the shortest paths from one source to every node.
+type Paths[N comparable] struct {
+	g      *Graph[N]
+	source int
+	dist   []float64
+	// via[i] is the last edge of the shortest path to node i.
+	via []Edge[N]
+}
+
+func newPaths[N comparable](g *Graph[N], source int) *Paths[N] {
+	p := &Paths[N]{
+		g:      g,
+		source: source,
+		dist:   make([]float64, len(g.nodes)),
+		via:    make([]Edge[N], len(g.nodes)),
+	}
+	for i := range p.dist {
+		p.dist[i] = math.Inf(1)
+	}
+	p.dist[source] = 0
+	return p
+}
+
+// Dist returns the length of the shortest path to n, or +Inf if n cannot
+// be reached.
+func (p *Paths[N]) Dist(n N) float64 {
+	i, ok := p.g.index[n]
+	if !ok {
+		return math.Inf(1)
+	}
+	return p.dist[i]
+}
+
+// To returns the edges of the shortest path to n, or nil if n cannot be
+// reached or is the source.
+func (p *Paths[N]) To(n N) []Edge[N] {
+	i, ok := p.g.index[n]
+	if !ok || math.IsInf(p.dist[i], 1) {
+		return nil
+	}
+	var path []Edge[N]
+	for i != p.source {
+		e := p.via[i]
+		path = append(path, e)
+		i = p.g.index[e.From]
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Tree returns the last edge of the shortest path to every reachable
+// node: together they form the shortest path tree rooted at the source.
+func (p *Paths[N]) Tree() []Edge[N] {
+	var tree []Edge[N]
+	for i, d := range p.dist {
+		if i != p.source && !math.IsInf(d, 1) {
+			tree = append(tree, p.via[i])
+		}
+	}
+	return tree
+}
+
+// Nodes returns the nodes along a path of edges, from the first edge's
+// start to the last edge's end.
+func Nodes[N comparable](path []Edge[N]) []N {
+	if len(path) == 0 {
+		return nil
+	}
+	out := []N{path[0].From}
+	for _, e := range path {
+		out = append(out, e.To)
+	}
+	return out
+}
+
+type queued struct {
+	node int
+	dist float64
+}
+
+func byDist(a, b queued) bool { return a.dist < b.dist }
+
+// Dijkstra finds the shortest paths from source in a graph without
+// negative weights. It settles nodes in order of distance, taking the
+// closest unsettled one from a heap each time; once settled, a node's
+// distance is final. That is only true when no edge is negative, so
+// Dijkstra refuses graphs that have one; use BellmanFord for those.
+func (g *Graph[N]) Dijkstra(source N) (*Paths[N], error) {
+	s, ok := g.index[source]
+	if !ok {
+		return nil, fmt.Errorf("graph: no node %v", source)
+	}
+	for _, e := range g.Edges() {
+		if e.Weight < 0 {
+			return nil, fmt.Errorf("graph: Dijkstra needs non-negative weights, edge %v -> %v has %g", e.From, e.To, e.Weight)
+		}
+	}
+	p := newPaths(g, s)
+	settled := make([]bool, len(g.nodes))
+	// The heap may hold stale entries for a node whose distance improved
+	// after it was pushed; they are skipped when popped. That is simpler
+	// than a decrease-key operation and costs at most one entry per edge.
+	h := containers.NewHeap(byDist, queued{s, 0})
+	for h.Len() > 0 {
+		q, _ := h.Pop()
+		if settled[q.node] {
+			continue
+		}
+		settled[q.node] = true
+		for _, e := range g.adj[q.node] {
+			j := g.index[e.To]
+			if d := q.dist + e.Weight; d < p.dist[j] {
+				p.dist[j], p.via[j] = d, e
+				h.Push(queued{j, d})
+			}
+		}
+	}
+	return p, nil
+}
+
+// AStar finds a shortest path from source to target, guided by an
+// estimate h of the remaining distance from each node to target. It is
+// Dijkstra with nodes ordered by distance so far plus estimate, so it
+// heads towards the target instead of spreading out in all directions.
+// The path is shortest as long as h never overestimates, as the straight
+// line or Manhattan distance on a map does. It returns the path and its
+// length, or ok false if target cannot be reached.
+func (g *Graph[N]) AStar(source, target N, h func(N) float64) (path []Edge[N], length float64, ok bool) {
+	s, ok1 := g.index[source]
+	t, ok2 := g.index[target]
+	if !ok1 || !ok2 {
+		return nil, 0, false
+	}
+	p := newPaths(g, s)
+	closed := make([]bool, len(g.nodes))
+	open := containers.NewHeap(byDist, queued{s, h(source)})
+	for open.Len() > 0 {
+		q, _ := open.Pop()
+		if q.node == t {
+			return p.To(target), p.dist[t], true
+		}
+		if closed[q.node] {
+			continue
+		}
+		closed[q.node] = true
+		for _, e := range g.adj[q.node] {
+			j := g.index[e.To]
+			if d := p.dist[q.node] + e.Weight; d < p.dist[j] {
+				p.dist[j], p.via[j] = d, e
+				open.Push(queued{j, d + h(e.To)})
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// NegativeCycleError is returned by BellmanFord when a cycle of negative
+// total weight can be reached from the source: going round it again
+// always makes a path shorter, so there is no shortest path.
+type NegativeCycleError[N comparable] struct {
+	Cycle []N
+}
+
+func (e *NegativeCycleError[N]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		parts[i] = fmt.Sprint(n)
+	}
+	return "graph: negative cycle " + strings.Join(parts, " -> ")
+}
+
+// BellmanFord finds the shortest paths from source, allowing negative
+// weights. It relaxes every edge n-1 times, since a shortest path has at
+// most n-1 edges; if a further round still improves a distance, there is
+// a negative cycle and the error is a *NegativeCycleError.
+func (g *Graph[N]) BellmanFord(source N) (*Paths[N], error) {
+	s, ok := g.index[source]
+	if !ok {
+		return nil, fmt.Errorf("graph: no node %v", source)
+	}
+	p := newPaths(g, s)
+	edges := g.Edges()
+	if !g.directed {
+		// Both directions of an undirected edge can be relaxed.
+		for _, e := range g.Edges() {
+			edges = append(edges, Edge[N]{e.To, e.From, e.Weight})
+		}
+	}
+	relax := func() int {
+		changed := -1
+		for _, e := range edges {
+			i, j := g.index[e.From], g.index[e.To]
+			if d := p.dist[i] + e.Weight; d < p.dist[j] {
+				p.dist[j], p.via[j] = d, e
+				changed = j
+			}
+		}
+		return changed
+	}
+	for range len(g.nodes) - 1 {
+		if relax() < 0 {
+			return p, nil
+		}
+	}
+	j := relax()
+	if j < 0 {
+		return p, nil
+	}
+	// j was improved in round n, so following the via edges back n times
+	// is sure to land on the cycle.
+	for range len(g.nodes) {
+		j = g.index[p.via[j].From]
+	}
+	cycle := []N{g.nodes[j]}
+	for k := g.index[p.via[j].From]; k != j; k = g.index[p.via[k].From] {
+		cycle = append(cycle, g.nodes[k])
+	}
+	cycle = append(cycle, g.nodes[j])
+	slices.Reverse(cycle)
+	return nil, &NegativeCycleError[N]{Cycle: cycle}
+}
@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// BFS yields the nodes reachable from start in breadth-first order, with
+// their distance from start in edges. Nodes closer to start always come
+// first, which makes BFS the shortest path algorithm for unweighted
+// graphs.
+func (g *Graph[N]) BFS(start N) iter.Seq2[N, int] {
+	return func(yield func(N, int) bool) {
+		s, ok := g.index[start]
+		if !ok {
+			return
+		}
+		depth := make([]int, len(g.nodes))
+		for i := range depth {
+			depth[i] = -1
+		}
+		depth[s] = 0
+		queue := []int{s}
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			if !yield(g.nodes[i], depth[i]) {
+				return
+			}
+			for _, e := range g.adj[i] {
+				if j := g.index[e.To]; depth[j] < 0 {
+					depth[j] = depth[i] + 1
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+}
+
+// DFS yields the nodes reachable from start in depth-first preorder: a
+// node comes before everything discovered through it, and each branch is
+// followed to its end before the next.
+func (g *Graph[N]) DFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		s, ok := g.index[start]
+		if !ok {
+			return
+		}
+		seen := make([]bool, len(g.nodes))
+		var visit func(i int) bool
+		visit = func(i int) bool {
+			seen[i] = true
+			if !yield(g.nodes[i]) {
+				return false
+			}
+			for _, e := range g.adj[i] {
+				if j := g.index[e.To]; !seen[j] && !visit(j) {
+					return false
+				}
+			}
+			return true
+		}
+		visit(s)
+	}
+}
+
+// CycleError is returned by TopoSort for a graph with a cycle.
+type CycleError[N comparable] struct {
+	// Cycle lists the nodes of one cycle, with the first repeated at the
+	// end: [a b c a].
+	Cycle []N
+}
+
+func (e *CycleError[N]) Error() string {
+	parts := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		parts[i] = fmt.Sprint(n)
+	}
+	return "graph: cycle " + strings.Join(parts, " -> ")
+}
+
+// TopoSort orders the nodes of a directed graph so that every edge points
+// forward, as a build tool orders tasks by their dependencies. It uses
+// depth-first search: a node is finished after everything it points to,
+// so the reversed finishing order is a topological order. If the graph
+// has a cycle there is no such order, and the error is a *CycleError.
+func (g *Graph[N]) TopoSort() ([]N, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("graph: topological sort needs a directed graph")
+	}
+	const (
+		unvisited = iota
+		active    // on the current DFS path
+		done
+	)
+	state := make([]int, len(g.nodes))
+	parent := make([]int, len(g.nodes))
+	order := make([]N, 0, len(g.nodes))
+	var cycle []N
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = active
+		for _, e := range g.adj[i] {
+			j := g.index[e.To]
+			switch state[j] {
+			case active:
+				// A back edge: walk the path back from i to j.
+				cycle = []N{g.nodes[j]}
+				for k := i; k != j; k = parent[k] {
+					cycle = append(cycle, g.nodes[k])
+				}
+				cycle = append(cycle, g.nodes[j])
+				slices.Reverse(cycle)
+				return false
+			case unvisited:
+				parent[j] = i
+				if !visit(j) {
+					return false
+				}
+			}
+		}
+		state[i] = done
+		order = append(order, g.nodes[i])
+		return true
+	}
+	for i := range g.nodes {
+		if state[i] == unvisited && !visit(i) {
+			return nil, &CycleError[N]{Cycle: cycle}
+		}
+	}
+	slices.Reverse(order)
+	return order, nil
+}
+
+// StronglyConnected returns the strongly connected components of a
+// directed graph: the largest sets of nodes in which every node can reach
+// every other. It uses Tarjan's algorithm, a single depth-first search
+// that tracks the lowest discovery index reachable from each node. The
+// components come out in reverse topological order; for an undirected
+// graph they are the connected components.
+func (g *Graph[N]) StronglyConnected() [][]N {
+	index := make([]int, len(g.nodes))
+	low := make([]int, len(g.nodes))
+	onStack := make([]bool, len(g.nodes))
+	for i := range index {
+		index[i] = -1
+	}
+	var (
+		stack []int
+		comps [][]N
+		next  int
+	)
+	var visit func(i int)
+	visit = func(i int) {
+		index[i], low[i] = next, next
+		next++
+		stack = append(stack, i)
+		onStack[i] = true
+		for _, e := range g.adj[i] {
+			j := g.index[e.To]
+			if index[j] < 0 {
+				visit(j)
+				low[i] = min(low[i], low[j])
+			} else if onStack[j] {
+				low[i] = min(low[i], index[j])
+			}
+		}
+		if low[i] != index[i] {
+			return
+		}
+		// i is the root of a component: everything above it on the stack.
+		var comp []N
+		for {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[j] = false
+			comp = append(comp, g.nodes[j])
+			if j == i {
+				break
+			}
+		}
+		slices.Reverse(comp)
+		comps = append(comps, comp)
+	}
+	for i := range g.nodes {
+		if index[i] < 0 {
+			visit(i)
+		}
+	}
+	return comps
+}
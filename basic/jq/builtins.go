@@ -0,0 +1,319 @@
+package jq
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// builtinFunc implements a builtin. Arguments are passed unevaluated,
+// because filters such as select and map run them once per element.
+type builtinFunc func(in jsonparse.Value, args []expr) ([]jsonparse.Value, error)
+
+type builtinKey struct {
+	name  string
+	arity int
+}
+
+var builtins = map[builtinKey]builtinFunc{
+	{"empty", 0}:          func(jsonparse.Value, []expr) ([]jsonparse.Value, error) { return nil, nil },
+	{"not", 0}:            one(func(v jsonparse.Value) (jsonparse.Value, error) { return jsonparse.BoolValue(!v.Truthy()), nil }),
+	{"length", 0}:         one(length),
+	{"keys", 0}:           one(keys),
+	{"values", 0}:         builtinValues,
+	{"type", 0}:           one(func(v jsonparse.Value) (jsonparse.Value, error) { return jsonparse.StringValue(v.Kind.String()), nil }),
+	{"add", 0}:            one(add),
+	{"reverse", 0}:        one(reverse),
+	{"sort", 0}:           one(func(v jsonparse.Value) (jsonparse.Value, error) { return sortBy(v, nil) }),
+	{"unique", 0}:         one(unique),
+	{"min", 0}:            one(func(v jsonparse.Value) (jsonparse.Value, error) { return extreme(v, -1) }),
+	{"max", 0}:            one(func(v jsonparse.Value) (jsonparse.Value, error) { return extreme(v, 1) }),
+	{"tostring", 0}:       one(tostring),
+	{"tonumber", 0}:       one(tonumber),
+	{"select", 1}:         builtinSelect,
+	{"map", 1}:            builtinMap,
+	{"sort_by", 1}:        builtinSortBy,
+	{"has", 1}:            builtinHas,
+	{"first", 0}:          nth(0),
+	{"last", 0}:           nth(-1),
+	{"ascii_downcase", 0}: one(asciiDowncase),
+}
+
+// call is a builtin invocation such as length or select(.age > 25).
+type call struct {
+	name string
+	fn   builtinFunc
+	args []expr
+}
+
+func (c call) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	return c.fn(in, c.args)
+}
+
+// one adapts a function of the input alone into a builtin.
+func one(fn func(jsonparse.Value) (jsonparse.Value, error)) builtinFunc {
+	return func(in jsonparse.Value, _ []expr) ([]jsonparse.Value, error) {
+		v, err := fn(in)
+		if err != nil {
+			return nil, err
+		}
+		return []jsonparse.Value{v}, nil
+	}
+}
+
+// nth returns the builtin for .[i], used by first and last.
+func nth(i int) builtinFunc {
+	return func(in jsonparse.Value, _ []expr) ([]jsonparse.Value, error) {
+		return index{identity{}, literal{jsonparse.NumberValue(float64(i))}}.eval(in)
+	}
+}
+
+func length(v jsonparse.Value) (jsonparse.Value, error) {
+	switch v.Kind {
+	case jsonparse.Null:
+		return jsonparse.NumberValue(0), nil
+	case jsonparse.Bool:
+		return jsonparse.Value{}, errorf("%s has no length", describe(v))
+	case jsonparse.Number:
+		if v.Num < 0 {
+			return jsonparse.NumberValue(-v.Num), nil
+		}
+		return v, nil
+	case jsonparse.String:
+		return jsonparse.NumberValue(float64(utf8.RuneCountInString(v.Str))), nil
+	}
+	return jsonparse.NumberValue(float64(v.Len())), nil
+}
+
+func keys(v jsonparse.Value) (jsonparse.Value, error) {
+	switch v.Kind {
+	case jsonparse.Object:
+		names := make([]string, 0, len(v.Members))
+		for _, m := range v.Members {
+			if !slices.Contains(names, m.Key) {
+				names = append(names, m.Key)
+			}
+		}
+		slices.Sort(names)
+		out := make([]jsonparse.Value, len(names))
+		for i, name := range names {
+			out[i] = jsonparse.StringValue(name)
+		}
+		return jsonparse.ArrayValue(out...), nil
+	case jsonparse.Array:
+		out := make([]jsonparse.Value, len(v.Items))
+		for i := range v.Items {
+			out[i] = jsonparse.NumberValue(float64(i))
+		}
+		return jsonparse.ArrayValue(out...), nil
+	}
+	return jsonparse.Value{}, errorf("%s has no keys", describe(v))
+}
+
+func asciiDowncase(v jsonparse.Value) (jsonparse.Value, error) {
+	if v.Kind != jsonparse.String {
+		return jsonparse.Value{}, errorf("%s cannot be lowercased", describe(v))
+	}
+	return jsonparse.StringValue(strings.ToLower(v.Str)), nil
+}
+
+func builtinValues(in jsonparse.Value, _ []expr) ([]jsonparse.Value, error) {
+	return iterate{identity{}}.eval(in)
+}
+
+func add(v jsonparse.Value) (jsonparse.Value, error) {
+	if v.Kind != jsonparse.Array {
+		return jsonparse.Value{}, errorf("cannot add up the items of %s", describe(v))
+	}
+	acc := jsonparse.NullValue()
+	for _, item := range v.Items {
+		var err error
+		if acc, err = arith("+", acc, item); err != nil {
+			return jsonparse.Value{}, err
+		}
+	}
+	return acc, nil
+}
+
+func reverse(v jsonparse.Value) (jsonparse.Value, error) {
+	switch v.Kind {
+	case jsonparse.Null:
+		return jsonparse.ArrayValue(), nil
+	case jsonparse.String:
+		r := []rune(v.Str)
+		slices.Reverse(r)
+		return jsonparse.StringValue(string(r)), nil
+	case jsonparse.Array:
+		items := slices.Clone(v.Items)
+		slices.Reverse(items)
+		return jsonparse.ArrayValue(items...), nil
+	}
+	return jsonparse.Value{}, errorf("cannot reverse %s", describe(v))
+}
+
+func unique(v jsonparse.Value) (jsonparse.Value, error) {
+	sorted, err := sortBy(v, nil)
+	if err != nil {
+		return jsonparse.Value{}, err
+	}
+	items := slices.CompactFunc(sorted.Items, func(a, b jsonparse.Value) bool { return Compare(a, b) == 0 })
+	return jsonparse.ArrayValue(items...), nil
+}
+
+func extreme(v jsonparse.Value, sign int) (jsonparse.Value, error) {
+	if v.Kind != jsonparse.Array {
+		return jsonparse.Value{}, errorf("cannot take the min or max of %s", describe(v))
+	}
+	if len(v.Items) == 0 {
+		return jsonparse.NullValue(), nil
+	}
+	best := v.Items[0]
+	for _, item := range v.Items[1:] {
+		if Compare(item, best)*sign > 0 {
+			best = item
+		}
+	}
+	return best, nil
+}
+
+func tostring(v jsonparse.Value) (jsonparse.Value, error) {
+	if v.Kind == jsonparse.String {
+		return v, nil
+	}
+	return jsonparse.StringValue(v.String()), nil
+}
+
+func tonumber(v jsonparse.Value) (jsonparse.Value, error) {
+	switch v.Kind {
+	case jsonparse.Number:
+		return v, nil
+	case jsonparse.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v.Str), 64)
+		if err != nil {
+			return jsonparse.Value{}, errorf("cannot parse %s as a number", describe(v))
+		}
+		return jsonparse.NumberValue(f), nil
+	}
+	return jsonparse.Value{}, errorf("%s cannot be parsed as a number", describe(v))
+}
+
+func builtinSelect(in jsonparse.Value, args []expr) ([]jsonparse.Value, error) {
+	conds, err := args[0].eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []jsonparse.Value
+	for _, c := range conds {
+		if c.Truthy() {
+			out = append(out, in)
+		}
+	}
+	return out, nil
+}
+
+func builtinMap(in jsonparse.Value, args []expr) ([]jsonparse.Value, error) {
+	return collect{pipe{iterate{identity{}}, args[0]}}.eval(in)
+}
+
+func builtinHas(in jsonparse.Value, args []expr) ([]jsonparse.Value, error) {
+	return cross(identity{}, args[0], in, func(t, k jsonparse.Value) (jsonparse.Value, error) {
+		switch {
+		case t.Kind == jsonparse.Object && k.Kind == jsonparse.String:
+			_, ok := t.Get(k.Str)
+			return jsonparse.BoolValue(ok), nil
+		case t.Kind == jsonparse.Array && k.Kind == jsonparse.Number:
+			return jsonparse.BoolValue(k.Num >= 0 && int(k.Num) < len(t.Items)), nil
+		}
+		return jsonparse.Value{}, errorf("cannot check whether %s has a key %s", describe(t), describe(k))
+	})
+}
+
+func builtinSortBy(in jsonparse.Value, args []expr) ([]jsonparse.Value, error) {
+	v, err := sortBy(in, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return []jsonparse.Value{v}, nil
+}
+
+// sortBy sorts an array stably, by the items themselves when key is nil and
+// by the array of outputs of key otherwise.
+func sortBy(v jsonparse.Value, key expr) (jsonparse.Value, error) {
+	if v.Kind != jsonparse.Array {
+		return jsonparse.Value{}, errorf("%s cannot be sorted, as it is not an array", describe(v))
+	}
+	type keyed struct {
+		key, item jsonparse.Value
+	}
+	items := make([]keyed, len(v.Items))
+	for i, item := range v.Items {
+		items[i] = keyed{item, item}
+		if key != nil {
+			ks, err := key.eval(item)
+			if err != nil {
+				return jsonparse.Value{}, err
+			}
+			items[i].key = jsonparse.ArrayValue(ks...)
+		}
+	}
+	slices.SortStableFunc(items, func(a, b keyed) int { return Compare(a.key, b.key) })
+	out := make([]jsonparse.Value, len(items))
+	for i, it := range items {
+		out[i] = it.item
+	}
+	return jsonparse.ArrayValue(out...), nil
+}
+
+// Compare orders two values the way jq does: null < false < true < numbers
+// < strings < arrays < objects. Objects compare their sorted key sets first
+// and then their values key by key.
+func Compare(a, b jsonparse.Value) int {
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return cmp.Compare(ra, rb)
+	}
+	switch a.Kind {
+	case jsonparse.Number:
+		return cmp.Compare(a.Num, b.Num)
+	case jsonparse.String:
+		return strings.Compare(a.Str, b.Str)
+	case jsonparse.Array:
+		return slices.CompareFunc(a.Items, b.Items, Compare)
+	case jsonparse.Object:
+		ka, _ := keys(a)
+		kb, _ := keys(b)
+		if c := Compare(ka, kb); c != 0 {
+			return c
+		}
+		for _, k := range ka.Items {
+			va, _ := a.Get(k.Str)
+			vb, _ := b.Get(k.Str)
+			if c := Compare(va, vb); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func rank(v jsonparse.Value) int {
+	switch v.Kind {
+	case jsonparse.Null:
+		return 0
+	case jsonparse.Bool:
+		if v.Bool {
+			return 2
+		}
+		return 1
+	case jsonparse.Number:
+		return 3
+	case jsonparse.String:
+		return 4
+	case jsonparse.Array:
+		return 5
+	}
+	return 6
+}
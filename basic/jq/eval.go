@@ -0,0 +1,518 @@
+package jq
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// expr is a node of the query AST. eval returns every output the filter
+// produces for one input.
+type expr interface {
+	eval(in jsonparse.Value) ([]jsonparse.Value, error)
+}
+
+// RuntimeError is raised while evaluating a query, e.g. when indexing a
+// number. The ? operator suppresses it.
+type RuntimeError struct {
+	Msg string
+}
+
+func (e *RuntimeError) Error() string { return e.Msg }
+
+func errorf(format string, args ...any) error {
+	return &RuntimeError{Msg: fmt.Sprintf(format, args...)}
+}
+
+type identity struct{}
+
+func (identity) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	return []jsonparse.Value{in}, nil
+}
+
+// recurse is .., every value in the input, depth first.
+type recurse struct{}
+
+func (recurse) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	var out []jsonparse.Value
+	var walk func(v jsonparse.Value)
+	walk = func(v jsonparse.Value) {
+		out = append(out, v)
+		for _, item := range v.Items {
+			walk(item)
+		}
+		for _, m := range v.Members {
+			walk(m.Value)
+		}
+	}
+	walk(in)
+	return out, nil
+}
+
+type literal struct {
+	v jsonparse.Value
+}
+
+func (l literal) eval(jsonparse.Value) ([]jsonparse.Value, error) {
+	return []jsonparse.Value{l.v}, nil
+}
+
+type pipe struct {
+	left, right expr
+}
+
+func (p pipe) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	mid, err := p.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []jsonparse.Value
+	for _, v := range mid {
+		res, err := p.right.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res...)
+	}
+	return out, nil
+}
+
+type comma struct {
+	left, right expr
+}
+
+func (c comma) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	l, err := c.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	return append(l, r...), nil
+}
+
+// index is .[key] and all of its shorthands.
+type index struct {
+	target, key expr
+}
+
+func (x index) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	return cross(x.target, x.key, in, func(t, k jsonparse.Value) (jsonparse.Value, error) {
+		switch {
+		case t.Kind == jsonparse.Null && (k.Kind == jsonparse.String || k.Kind == jsonparse.Number):
+			return jsonparse.NullValue(), nil
+		case t.Kind == jsonparse.Object && k.Kind == jsonparse.String:
+			v, ok := t.Get(k.Str)
+			if !ok {
+				return jsonparse.NullValue(), nil
+			}
+			return v, nil
+		case t.Kind == jsonparse.Array && k.Kind == jsonparse.Number:
+			i := int(math.Floor(k.Num))
+			if i < 0 {
+				i += len(t.Items)
+			}
+			if i < 0 || i >= len(t.Items) {
+				return jsonparse.NullValue(), nil
+			}
+			return t.Items[i], nil
+		}
+		return jsonparse.Value{}, errorf("cannot index %s with %s", t.Kind, describe(k))
+	})
+}
+
+// slice is .[from:to] on arrays and strings; missing bounds default to the
+// start and end.
+type slice struct {
+	target, from, to expr
+}
+
+func (s slice) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	targets, err := s.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	bound := func(e expr, def int) ([]int, error) {
+		if e == nil {
+			return []int{def}, nil
+		}
+		vs, err := e.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]int, len(vs))
+		for i, v := range vs {
+			if v.Kind == jsonparse.Null {
+				out[i] = def
+				continue
+			}
+			if v.Kind != jsonparse.Number {
+				return nil, errorf("slice bounds must be numbers, not %s", v.Kind)
+			}
+			out[i] = int(math.Floor(v.Num))
+		}
+		return out, nil
+	}
+	var out []jsonparse.Value
+	for _, t := range targets {
+		var n int
+		switch t.Kind {
+		case jsonparse.Null:
+			out = append(out, t)
+			continue
+		case jsonparse.Array:
+			n = len(t.Items)
+		case jsonparse.String:
+			n = len([]rune(t.Str))
+		default:
+			return nil, errorf("cannot slice %s", t.Kind)
+		}
+		froms, err := bound(s.from, 0)
+		if err != nil {
+			return nil, err
+		}
+		tos, err := bound(s.to, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, from := range froms {
+			for _, to := range tos {
+				from, to := clampIndex(from, n), clampIndex(to, n)
+				if to < from {
+					to = from
+				}
+				if t.Kind == jsonparse.Array {
+					out = append(out, jsonparse.ArrayValue(t.Items[from:to]...))
+				} else {
+					out = append(out, jsonparse.StringValue(string([]rune(t.Str)[from:to])))
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// iterate is .[], the items of an array or the values of an object.
+type iterate struct {
+	target expr
+}
+
+func (it iterate) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	targets, err := it.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []jsonparse.Value
+	for _, t := range targets {
+		switch t.Kind {
+		case jsonparse.Array:
+			out = append(out, t.Items...)
+		case jsonparse.Object:
+			for _, m := range t.Members {
+				out = append(out, m.Value)
+			}
+		default:
+			return nil, errorf("cannot iterate over %s", describe(t))
+		}
+	}
+	return out, nil
+}
+
+// optional is e?, which turns runtime errors into empty output.
+type optional struct {
+	e expr
+}
+
+func (o optional) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	out, err := o.e.eval(in)
+	if _, ok := err.(*RuntimeError); ok {
+		return nil, nil
+	}
+	return out, err
+}
+
+// collect is [e], which gathers all outputs of e into one array.
+type collect struct {
+	e expr
+}
+
+func (c collect) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	if c.e == nil {
+		return []jsonparse.Value{jsonparse.ArrayValue()}, nil
+	}
+	items, err := c.e.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	return []jsonparse.Value{jsonparse.ArrayValue(items...)}, nil
+}
+
+type objectEntry struct {
+	key, value expr
+}
+
+// object is {k: v, ...}. When keys or values produce several outputs, one
+// object is built for every combination.
+type object struct {
+	entries []objectEntry
+}
+
+func (o object) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	partial := [][]jsonparse.Member{nil}
+	for _, ent := range o.entries {
+		keys, err := ent.key.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		values, err := ent.value.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var next [][]jsonparse.Member
+		for _, members := range partial {
+			for _, k := range keys {
+				if k.Kind != jsonparse.String {
+					return nil, errorf("object keys must be strings, not %s", k.Kind)
+				}
+				for _, v := range values {
+					m := append(members[:len(members):len(members)], jsonparse.Member{Key: k.Str, Value: v})
+					next = append(next, m)
+				}
+			}
+		}
+		partial = next
+	}
+	out := make([]jsonparse.Value, len(partial))
+	for i, members := range partial {
+		out[i] = setMembers(members)
+	}
+	return out, nil
+}
+
+// setMembers builds an object in which a repeated key replaces the earlier
+// value in place.
+func setMembers(members []jsonparse.Member) jsonparse.Value {
+	obj := jsonparse.ObjectValue()
+	for _, m := range members {
+		obj = set(obj, m.Key, m.Value)
+	}
+	return obj
+}
+
+func set(obj jsonparse.Value, key string, v jsonparse.Value) jsonparse.Value {
+	members := make([]jsonparse.Member, 0, len(obj.Members)+1)
+	replaced := false
+	for _, m := range obj.Members {
+		if m.Key == key {
+			m.Value = v
+			replaced = true
+		}
+		members = append(members, m)
+	}
+	if !replaced {
+		members = append(members, jsonparse.Member{Key: key, Value: v})
+	}
+	return jsonparse.ObjectValue(members...)
+}
+
+type negate struct {
+	e expr
+}
+
+func (n negate) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	vs, err := n.e.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]jsonparse.Value, len(vs))
+	for i, v := range vs {
+		if v.Kind != jsonparse.Number {
+			return nil, errorf("%s cannot be negated", describe(v))
+		}
+		out[i] = jsonparse.NumberValue(-v.Num)
+	}
+	return out, nil
+}
+
+// logical is "and" / "or". The right side is only evaluated when the left
+// side does not already decide the result.
+type logical struct {
+	or          bool
+	left, right expr
+}
+
+func (l logical) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	lefts, err := l.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []jsonparse.Value
+	for _, lv := range lefts {
+		if lv.Truthy() == l.or {
+			out = append(out, jsonparse.BoolValue(l.or))
+			continue
+		}
+		rights, err := l.right.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		for _, rv := range rights {
+			out = append(out, jsonparse.BoolValue(rv.Truthy()))
+		}
+	}
+	return out, nil
+}
+
+// alternative is a // b: the truthy outputs of a, or the outputs of b if
+// there are none (errors in a count as no output).
+type alternative struct {
+	left, right expr
+}
+
+func (a alternative) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	lefts, err := a.left.eval(in)
+	if _, ok := err.(*RuntimeError); err != nil && !ok {
+		return nil, err
+	}
+	var out []jsonparse.Value
+	for _, v := range lefts {
+		if v.Truthy() {
+			out = append(out, v)
+		}
+	}
+	if len(out) > 0 {
+		return out, nil
+	}
+	return a.right.eval(in)
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+func (b binary) eval(in jsonparse.Value) ([]jsonparse.Value, error) {
+	return cross(b.left, b.right, in, func(l, r jsonparse.Value) (jsonparse.Value, error) {
+		switch b.op {
+		case "==":
+			return jsonparse.BoolValue(Compare(l, r) == 0), nil
+		case "!=":
+			return jsonparse.BoolValue(Compare(l, r) != 0), nil
+		case "<":
+			return jsonparse.BoolValue(Compare(l, r) < 0), nil
+		case "<=":
+			return jsonparse.BoolValue(Compare(l, r) <= 0), nil
+		case ">":
+			return jsonparse.BoolValue(Compare(l, r) > 0), nil
+		case ">=":
+			return jsonparse.BoolValue(Compare(l, r) >= 0), nil
+		}
+		return arith(b.op, l, r)
+	})
+}
+
+// cross evaluates two sub-expressions against the same input and applies fn
+// to every pair of outputs.
+func cross(left, right expr, in jsonparse.Value, fn func(l, r jsonparse.Value) (jsonparse.Value, error)) ([]jsonparse.Value, error) {
+	ls, err := left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]jsonparse.Value, 0, len(ls)*len(rs))
+	for _, l := range ls {
+		for _, r := range rs {
+			v, err := fn(l, r)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func arith(op string, l, r jsonparse.Value) (jsonparse.Value, error) {
+	if l.Kind == jsonparse.Number && r.Kind == jsonparse.Number {
+		switch op {
+		case "+":
+			return jsonparse.NumberValue(l.Num + r.Num), nil
+		case "-":
+			return jsonparse.NumberValue(l.Num - r.Num), nil
+		case "*":
+			return jsonparse.NumberValue(l.Num * r.Num), nil
+		case "/":
+			if r.Num == 0 {
+				return jsonparse.Value{}, errorf("%s and %s cannot be divided because the divisor is zero", describe(l), describe(r))
+			}
+			return jsonparse.NumberValue(l.Num / r.Num), nil
+		case "%":
+			a, b := int64(l.Num), int64(r.Num)
+			if b == 0 {
+				return jsonparse.Value{}, errorf("%s and %s cannot be divided because the divisor is zero", describe(l), describe(r))
+			}
+			return jsonparse.NumberValue(float64(a % b)), nil
+		}
+	}
+	if op == "+" {
+		switch {
+		case l.Kind == jsonparse.Null:
+			return r, nil
+		case r.Kind == jsonparse.Null:
+			return l, nil
+		case l.Kind == jsonparse.String && r.Kind == jsonparse.String:
+			return jsonparse.StringValue(l.Str + r.Str), nil
+		case l.Kind == jsonparse.Array && r.Kind == jsonparse.Array:
+			items := append(l.Items[:len(l.Items):len(l.Items)], r.Items...)
+			return jsonparse.ArrayValue(items...), nil
+		case l.Kind == jsonparse.Object && r.Kind == jsonparse.Object:
+			obj := l
+			for _, m := range r.Members {
+				obj = set(obj, m.Key, m.Value)
+			}
+			return obj, nil
+		}
+	}
+	if op == "-" && l.Kind == jsonparse.Array && r.Kind == jsonparse.Array {
+		var items []jsonparse.Value
+		for _, item := range l.Items {
+			if !contains(r.Items, item) {
+				items = append(items, item)
+			}
+		}
+		return jsonparse.ArrayValue(items...), nil
+	}
+	verb := map[string]string{"+": "added", "-": "subtracted", "*": "multiplied", "/": "divided", "%": "divided"}[op]
+	return jsonparse.Value{}, errorf("%s and %s cannot be %s", describe(l), describe(r), verb)
+}
+
+func contains(items []jsonparse.Value, v jsonparse.Value) bool {
+	for _, item := range items {
+		if Compare(item, v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// describe formats a value for error messages, e.g. `number (42)`.
+func describe(v jsonparse.Value) string {
+	s := v.String()
+	if len(s) > 20 {
+		s = s[:17] + "..."
+	}
+	return fmt.Sprintf("%s (%s)", v.Kind, s)
+}
@@ -0,0 +1,43 @@
+// Package jq implements a practical subset of the jq query language over
+// jsonparse values.
+//
+// Supported: identity (.), field access (.name, ."name", .["name"]),
+// indexing and slicing (.[0], .[1:3]), iteration (.[]), optional access (?),
+// pipes (|), commas, parentheses, literals, array and object construction,
+// arithmetic (+ - * / %), comparisons (== != < <= > >=), and, or, the
+// alternative operator (//) and the builtins listed in builtins.go such as
+// select, map, length, keys and sort_by.
+//
+// Every filter produces zero or more outputs per input, so results are
+// returned as slices.
+package jq
+
+import (
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// Query is a compiled jq program.
+type Query struct {
+	src  string
+	root expr
+}
+
+// Compile parses src. Syntax errors are returned as *SyntaxError.
+func Compile(src string) (*Query, error) {
+	p := &parser{lex: newLexer(src)}
+	root, err := p.parseProgram()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// String returns the source of the query.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Run applies the query to one input and returns all of its outputs.
+func (q *Query) Run(input jsonparse.Value) ([]jsonparse.Value, error) {
+	return q.root.eval(input)
+}
@@ -0,0 +1,181 @@
+package jq
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+const students = `{"students": [
+	{"name": "Alice", "age": 30, "tags": ["go", "sql"]},
+	{"name": "Bob", "age": 25, "tags": []},
+	{"name": "Sara", "age": 27, "tags": ["go"]}
+]}`
+
+// run compiles query, runs it on the JSON input and returns the outputs
+// in compact form, one per line.
+func run(t *testing.T, query, input string) (string, error) {
+	t.Helper()
+	q, err := Compile(query)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", query, err)
+	}
+	in, err := jsonparse.Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("parsing %s: %v", input, err)
+	}
+	out, err := q.Run(in)
+	lines := make([]string, len(out))
+	for i, v := range out {
+		lines[i] = string(jsonparse.Marshal(v))
+	}
+	return strings.Join(lines, "\n"), err
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		query, input, want string
+	}{
+		{`.`, `[1,2]`, `[1,2]`},
+		{`.students[] | select(.age > 25) | .name`, students, "\"Alice\"\n\"Sara\""},
+		{`.students | map(.age)`, students, `[30,25,27]`},
+		{`.students | sort_by(.age) | map(.name)`, students, `["Bob","Sara","Alice"]`},
+		{`.students | sort_by(.name) | .[0].name`, students, `"Alice"`},
+		{`.students | map({name, next: (.age + 1)}) | .[1]`, students, `{"name":"Bob","next":26}`},
+		{`{n: .students | length, first: .students[0].name}`, students, `{"n":3,"first":"Alice"}`},
+		{`{ages: .students | map(.age) | sort}`, students, `{"ages":[25,27,30]}`},
+		{`[.students[].tags[]] | unique`, students, `["go","sql"]`},
+		{`.students | map(select(.tags | length > 0)) | length`, students, `2`},
+		{`.students[0] | keys`, students, `["age","name","tags"]`},
+		{`.students[0] | has("tags"), has("email")`, students, "true\nfalse"},
+		{`[.students[].age] | add / length`, students, `27.333333333333332`},
+		{`[.students[].age] | min, max`, students, "25\n30"},
+		{`."a b", .["c"]`, `{"a b": 1, "c": 2}`, "1\n2"},
+		{`.[1:3]`, `[0,1,2,3]`, `[1,2]`},
+		{`.[-1]`, `[0,1,2,3]`, `3`},
+		{`.missing.deeper`, `{}`, `null`},
+		{`.a // "default"`, `{"a": null}`, `"default"`},
+		{`.a and (.b or false)`, `{"a": true, "b": 1}`, `true`},
+		{`.[]?`, `3`, ``},
+		{`1, 2 | . * 10`, `null`, "10\n20"},
+		{`"n: " + (.n | tostring)`, `{"n": 4}`, `"n: 4"`},
+		{`[.[] | tonumber] | sort | reverse`, `["10", "9", "100"]`, `[100,10,9]`},
+		{`.[] | type`, `[null, true, 1, "s", [], {}]`, "\"null\"\n\"boolean\"\n\"number\"\n\"string\"\n\"array\"\n\"object\""},
+		{`[.[] | ascii_downcase] | first, last`, `["Go", "SQL"]`, "\"go\"\n\"sql\""},
+	}
+	for _, tt := range tests {
+		got, err := run(t, tt.query, tt.input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s on %s:\ngot  %s\nwant %s", tt.query, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		query, input string
+	}{
+		{`.a`, `[1]`},
+		{`.[0]`, `{"a": 1}`},
+		{`.[]`, `3`},
+		{`. + 1`, `"x"`},
+		{`length`, `true`},
+		{`tonumber`, `"abc"`},
+	}
+	for _, tt := range tests {
+		_, err := run(t, tt.query, tt.input)
+		var re *RuntimeError
+		if !errors.As(err, &re) {
+			t.Errorf("%s on %s: got %v, want a *RuntimeError", tt.query, tt.input, err)
+		}
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query  string
+		column int
+	}{
+		{`.students[] | select(.age > )`, 29},
+		{`.a |`, 5},
+		{`[1, 2`, 6},
+		{`{name: }`, 8},
+		{`nosuch(1)`, 1},
+		{`"سلام" | .[`, 12},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.query)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Compile(%q): got %v, want a *SyntaxError", tt.query, err)
+			continue
+		}
+		if se.column() != tt.column {
+			t.Errorf("Compile(%q): %v, want column %d", tt.query, err, tt.column)
+		}
+	}
+
+	_, err := Compile(`.students[] | select(.age > )`)
+	want := ".students[] | select(.age > )\n                            ^"
+	if got := err.(*SyntaxError).Context(); got != want {
+		t.Errorf("Context:\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestNDJSON runs a query over a stream of values decoded one at a time,
+// as the jq command does.
+func TestNDJSON(t *testing.T) {
+	const input = `{"level": "info", "msg": "started"}
+{"level": "error", "msg": "disk full"}
+{"level": "debug", "msg": "tick"}
+{"level": "error", "msg": "retrying"}
+`
+	q, err := Compile(`select(.level == "error") | .msg`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := jsonparse.NewDecoder(strings.NewReader(input))
+	var got []string
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := q.Run(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range out {
+			got = append(got, o.Str)
+		}
+	}
+	if strings.Join(got, ",") != "disk full,retrying" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// jq orders values by type first: null, false, true, numbers,
+	// strings, arrays, objects.
+	order := []string{`null`, `false`, `true`, `-1`, `2`, `"a"`, `"b"`, `[]`, `[1]`, `{}`}
+	for i := range order {
+		for j := range order {
+			a, _ := jsonparse.Parse([]byte(order[i]))
+			b, _ := jsonparse.Parse([]byte(order[j]))
+			if got, want := Compare(a, b), cmp.Compare(i, j); got != want {
+				t.Errorf("Compare(%s, %s) = %d, want %d", order[i], order[j], got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package jq
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// SyntaxError reports a malformed query. Offset is a byte offset into the
+// query source.
+type SyntaxError struct {
+	Src    string
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.column(), e.Msg)
+}
+
+// column is the 1-based rune column of Offset.
+func (e *SyntaxError) column() int {
+	return utf8.RuneCountInString(e.Src[:e.Offset]) + 1
+}
+
+// Context returns the query with a caret under the error position, for
+// showing on a terminal.
+func (e *SyntaxError) Context() string {
+	return e.Src + "\n" + strings.Repeat(" ", e.column()-1) + "^"
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF    tokenKind = iota
+	tokDot              // .
+	tokDotDot           // ..
+	tokField            // .name
+	tokIdent            // name, and, or, true, ...
+	tokNumber
+	tokString
+	tokPunct // | , ( ) [ ] { } : ?
+	tokOp    // == != < <= > >= + - * / % //
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return "string " + string(jsonparse.AppendQuote(nil, t.text))
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src}
+}
+
+func (l *lexer) errorf(pos int, format string, args ...any) error {
+	return &SyntaxError{Src: l.src, Offset: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9'
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && strings.IndexByte(" \t\r\n", l.src[l.pos]) >= 0 {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	c := l.src[l.pos]
+	switch {
+	case c == '.':
+		l.pos++
+		if l.pos < len(l.src) && l.src[l.pos] == '.' {
+			l.pos++
+			return token{kind: tokDotDot, text: "..", pos: start}, nil
+		}
+		if l.pos < len(l.src) && isIdentStart(l.src[l.pos]) {
+			name := l.ident()
+			return token{kind: tokField, text: name, pos: start}, nil
+		}
+		if l.pos < len(l.src) && l.src[l.pos] >= '0' && l.src[l.pos] <= '9' {
+			l.pos = start
+			return l.number()
+		}
+		return token{kind: tokDot, text: ".", pos: start}, nil
+	case isIdentStart(c):
+		return token{kind: tokIdent, text: l.ident(), pos: start}, nil
+	case c >= '0' && c <= '9':
+		return l.number()
+	case c == '"':
+		return l.string()
+	case strings.IndexByte("|,()[]{}:?", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, text: string(c), pos: start}, nil
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "//", "<", ">", "+", "-", "*", "/", "%"} {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	if c == '=' {
+		return token{}, l.errorf(start, "unexpected '=' (did you mean '=='?)")
+	}
+	return token{}, l.errorf(start, "unexpected character %q", c)
+}
+
+func (l *lexer) ident() string {
+	start := l.pos
+	for l.pos < len(l.src) && isIdentChar(l.src[l.pos]) {
+		l.pos++
+	}
+	return l.src[start:l.pos]
+}
+
+// number reuses the jsonparse lexer, so numbers follow JSON's grammar.
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	end := start
+	for end < len(l.src) && strings.IndexByte("0123456789.eE+-", l.src[end]) >= 0 {
+		// Stop at a sign that is not part of an exponent, as in "1+2".
+		if (l.src[end] == '+' || l.src[end] == '-') && end > start && l.src[end-1] != 'e' && l.src[end-1] != 'E' {
+			break
+		}
+		end++
+	}
+	text := l.src[start:end]
+	if text[0] == '.' {
+		text = "0" + text
+	}
+	v, err := jsonparse.Parse([]byte(text))
+	if err != nil || v.Kind != jsonparse.Number {
+		return token{}, l.errorf(start, "invalid number %q", l.src[start:end])
+	}
+	l.pos = end
+	return token{kind: tokNumber, text: l.src[start:end], num: v.Num, pos: start}, nil
+}
+
+// string finds the closing quote and decodes the literal with jsonparse, so
+// escapes behave exactly like in JSON.
+func (l *lexer) string() (token, error) {
+	start := l.pos
+	i := start + 1
+	for i < len(l.src) && l.src[i] != '"' {
+		if l.src[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	if i >= len(l.src) {
+		return token{}, l.errorf(start, "unterminated string")
+	}
+	v, err := jsonparse.Parse([]byte(l.src[start : i+1]))
+	if err != nil {
+		var off int
+		if se, ok := err.(*jsonparse.SyntaxError); ok {
+			off = se.Pos.Offset
+		}
+		return token{}, l.errorf(start+off, "invalid string literal")
+	}
+	l.pos = i + 1
+	return token{kind: tokString, text: v.Str, pos: start}, nil
+}
@@ -0,0 +1,473 @@
+package jq
+
+import (
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// parser is a recursive-descent parser with one token of lookahead.
+// Precedence, from loosest to tightest:
+//
+//	|   ,   //   or   and   == != < <= > >=   + -   * / %   unary -   postfix
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = t
+	return nil
+}
+
+func (p *parser) is(kind tokenKind, text string) bool {
+	return p.tok.kind == kind && p.tok.text == text
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.is(kind, text) {
+		return p.lex.errorf(p.tok.pos, "unexpected %s, expecting %q", p.tok, text)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseProgram() (expr, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return identity{}, nil
+	}
+	e, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.lex.errorf(p.tok.pos, "unexpected %s", p.tok)
+	}
+	return e, nil
+}
+
+func (p *parser) parsePipe() (expr, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is(tokPunct, "|") {
+		return left, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	return pipe{left, right}, nil
+}
+
+func (p *parser) parseComma() (expr, error) {
+	left, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokPunct, ",") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		left = comma{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAlt() (expr, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is(tokOp, "//") {
+		return left, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	return alternative{left, right}, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokIdent, "or") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.is(tokIdent, "and") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseCompare() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.tok.text; {
+	case p.tok.kind == tokOp && (op == "==" || op == "!=" || op == "<" || op == "<=" || op == ">" || op == ">="):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokOp && isComparison(p.tok.text) {
+			return nil, p.lex.errorf(p.tok.pos, "comparison operators cannot be chained; use parentheses")
+		}
+		return binary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "+" || p.tok.text == "-") {
+		op := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && (p.tok.text == "*" || p.tok.text == "/" || p.tok.text == "%") {
+		op := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.is(tokOp, "-") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negate{e}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (expr, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.tok.kind == tokField:
+			e = index{target: e, key: literal{jsonparse.StringValue(p.tok.text)}}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case p.tok.kind == tokDot:
+			// ."name" or .[...] after another suffix, e.g. .a."b" or .a.[0].
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			switch {
+			case p.tok.kind == tokString:
+				e = index{target: e, key: literal{jsonparse.StringValue(p.tok.text)}}
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			case p.is(tokPunct, "["):
+				if e, err = p.parseBracket(e); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, p.lex.errorf(p.tok.pos, "unexpected %s after '.'", p.tok)
+			}
+		case p.is(tokPunct, "["):
+			if e, err = p.parseBracket(e); err != nil {
+				return nil, err
+			}
+		case p.is(tokPunct, "?"):
+			e = optional{e}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		default:
+			return e, nil
+		}
+	}
+}
+
+// parseBracket parses [], [i], [from:to] applied to target. The current
+// token is the opening bracket.
+func (p *parser) parseBracket(target expr) (expr, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.is(tokPunct, "]") {
+		return iterate{target}, p.advance()
+	}
+	var from, to expr
+	var err error
+	if !p.is(tokPunct, ":") {
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if p.is(tokPunct, ":") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.is(tokPunct, "]") {
+			if to, err = p.parsePipe(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(tokPunct, "]"); err != nil {
+			return nil, err
+		}
+		return slice{target: target, from: from, to: to}, nil
+	}
+	if err := p.expect(tokPunct, "]"); err != nil {
+		return nil, err
+	}
+	return index{target: target, key: from}, nil
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.tok
+	switch t.kind {
+	case tokDot:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.tok.kind == tokString:
+			e := index{target: identity{}, key: literal{jsonparse.StringValue(p.tok.text)}}
+			return e, p.advance()
+		case p.is(tokPunct, "["):
+			return p.parseBracket(identity{})
+		}
+		return identity{}, nil
+	case tokField:
+		return index{target: identity{}, key: literal{jsonparse.StringValue(t.text)}}, p.advance()
+	case tokDotDot:
+		return recurse{}, p.advance()
+	case tokNumber:
+		return literal{jsonparse.Value{Kind: jsonparse.Number, Num: t.num}}, p.advance()
+	case tokString:
+		return literal{jsonparse.StringValue(t.text)}, p.advance()
+	case tokIdent:
+		return p.parseIdent()
+	case tokPunct:
+		switch t.text {
+		case "(":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			e, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return e, p.expect(tokPunct, ")")
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.is(tokPunct, "]") {
+				return collect{}, p.advance()
+			}
+			e, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return collect{e}, p.expect(tokPunct, "]")
+		case "{":
+			return p.parseObject()
+		}
+	}
+	return nil, p.lex.errorf(t.pos, "unexpected %s", t)
+}
+
+func (p *parser) parseIdent() (expr, error) {
+	t := p.tok
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	switch t.text {
+	case "true", "false":
+		return literal{jsonparse.BoolValue(t.text == "true")}, nil
+	case "null":
+		return literal{jsonparse.NullValue()}, nil
+	case "and", "or":
+		return nil, p.lex.errorf(t.pos, "unexpected %q", t.text)
+	}
+
+	var args []expr
+	if p.is(tokPunct, "(") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		arg, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if err := p.expect(tokPunct, ")"); err != nil {
+			return nil, err
+		}
+	}
+	b, ok := builtins[builtinKey{t.text, len(args)}]
+	if !ok {
+		return nil, p.lex.errorf(t.pos, "%s/%d is not defined", t.text, len(args))
+	}
+	return call{name: t.text, fn: b, args: args}, nil
+}
+
+// parseObject parses {key: value, ...}. Keys may be identifiers, strings or
+// parenthesised expressions; {name} is short for {name: .name}.
+func (p *parser) parseObject() (expr, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var entries []objectEntry
+	for !p.is(tokPunct, "}") {
+		var ent objectEntry
+		switch {
+		case p.tok.kind == tokIdent || p.tok.kind == tokString:
+			name := p.tok.text
+			ent.key = literal{jsonparse.StringValue(name)}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if !p.is(tokPunct, ":") {
+				ent.value = index{target: identity{}, key: ent.key}
+			}
+		case p.is(tokPunct, "("):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			k, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokPunct, ")"); err != nil {
+				return nil, err
+			}
+			ent.key = k
+		default:
+			return nil, p.lex.errorf(p.tok.pos, "unexpected %s, expecting object key", p.tok)
+		}
+		if ent.value == nil {
+			if err := p.expect(tokPunct, ":"); err != nil {
+				return nil, err
+			}
+			v, err := p.parseObjectValue()
+			if err != nil {
+				return nil, err
+			}
+			ent.value = v
+		}
+		entries = append(entries, ent)
+		if !p.is(tokPunct, ",") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokPunct, "}"); err != nil {
+		return nil, err
+	}
+	return object{entries}, nil
+}
+
+// parseObjectValue parses the value of an object entry: a pipe, as in
+// {n: .students | length}, but one whose stages bind tighter than ',' so
+// the comma can separate entries.
+func (p *parser) parseObjectValue() (expr, error) {
+	left, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is(tokPunct, "|") {
+		return left, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseObjectValue()
+	if err != nil {
+		return nil, err
+	}
+	return pipe{left, right}, nil
+}
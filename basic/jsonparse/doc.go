@@ -0,0 +1,13 @@
+// Package jsonparse is a hand-written JSON lexer and parser.
+//
+// It exists to show how byte slices and small state machines fit together,
+// so it avoids encoding/json entirely. The package has three layers:
+//
+//   - Lexer turns a byte stream into tokens and tracks line/column positions.
+//   - Reader checks the JSON grammar on top of the lexer and emits a
+//     SAX-style event stream (start/end object, key, value, ...).
+//   - Parse and Decoder build a DOM of Value nodes from those events.
+//
+// Every syntax error is a *SyntaxError carrying the exact position of the
+// offending byte.
+package jsonparse
@@ -0,0 +1,125 @@
+package jsonparse
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Marshal returns the compact JSON encoding of v.
+func Marshal(v Value) []byte {
+	return appendValue(nil, v, "", "", 0)
+}
+
+// MarshalIndent is like Marshal but puts each array item and object member
+// on its own line, starting with prefix and indented by indent per level.
+func MarshalIndent(v Value, prefix, indent string) []byte {
+	return appendValue(nil, v, prefix, indent, 0)
+}
+
+func appendValue(dst []byte, v Value, prefix, indent string, depth int) []byte {
+	switch v.Kind {
+	case Null:
+		return append(dst, "null"...)
+	case Bool:
+		return strconv.AppendBool(dst, v.Bool)
+	case Number:
+		return appendNumber(dst, v)
+	case String:
+		return AppendQuote(dst, v.Str)
+	case Array:
+		if len(v.Items) == 0 {
+			return append(dst, "[]"...)
+		}
+		dst = append(dst, '[')
+		for i, item := range v.Items {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = newline(dst, prefix, indent, depth+1)
+			dst = appendValue(dst, item, prefix, indent, depth+1)
+		}
+		dst = newline(dst, prefix, indent, depth)
+		return append(dst, ']')
+	case Object:
+		if len(v.Members) == 0 {
+			return append(dst, "{}"...)
+		}
+		dst = append(dst, '{')
+		for i, m := range v.Members {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = newline(dst, prefix, indent, depth+1)
+			dst = AppendQuote(dst, m.Key)
+			dst = append(dst, ':')
+			if indent != "" {
+				dst = append(dst, ' ')
+			}
+			dst = appendValue(dst, m.Value, prefix, indent, depth+1)
+		}
+		dst = newline(dst, prefix, indent, depth)
+		return append(dst, '}')
+	}
+	return dst
+}
+
+func newline(dst []byte, prefix, indent string, depth int) []byte {
+	if indent == "" && prefix == "" {
+		return dst
+	}
+	dst = append(dst, '\n')
+	dst = append(dst, prefix...)
+	return append(dst, strings.Repeat(indent, depth)...)
+}
+
+func appendNumber(dst []byte, v Value) []byte {
+	if v.Text != "" {
+		return append(dst, v.Text...)
+	}
+	f := v.Num
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		// JSON has no representation for these.
+		return append(dst, "null"...)
+	case f == math.Trunc(f) && math.Abs(f) < 1e17:
+		return strconv.AppendFloat(dst, f, 'f', -1, 64)
+	}
+	return strconv.AppendFloat(dst, f, 'g', -1, 64)
+}
+
+// AppendQuote appends s to dst as a JSON string literal.
+func AppendQuote(dst []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				dst = append(dst, '\\', c)
+			case c == '\n':
+				dst = append(dst, '\\', 'n')
+			case c == '\r':
+				dst = append(dst, '\\', 'r')
+			case c == '\t':
+				dst = append(dst, '\\', 't')
+			case c < 0x20:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				dst = append(dst, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, "\ufffd"...)
+		} else {
+			dst = append(dst, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,484 @@
+package jsonparse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Pos is a location in the input. Line and Column start at 1; Column counts
+// runes while Offset counts bytes.
+type Pos struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// SyntaxError describes malformed input and where it was found.
+type SyntaxError struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+// TokenKind identifies the lexical class of a Token.
+type TokenKind uint8
+
+const (
+	TokenEOF TokenKind = iota
+	TokenBeginObject
+	TokenEndObject
+	TokenBeginArray
+	TokenEndArray
+	TokenColon
+	TokenComma
+	TokenString
+	TokenNumber
+	TokenTrue
+	TokenFalse
+	TokenNull
+)
+
+var tokenNames = [...]string{
+	TokenEOF:         "end of input",
+	TokenBeginObject: "'{'",
+	TokenEndObject:   "'}'",
+	TokenBeginArray:  "'['",
+	TokenEndArray:    "']'",
+	TokenColon:       "':'",
+	TokenComma:       "','",
+	TokenString:      "string",
+	TokenNumber:      "number",
+	TokenTrue:        "true",
+	TokenFalse:       "false",
+	TokenNull:        "null",
+}
+
+func (k TokenKind) String() string {
+	if int(k) < len(tokenNames) {
+		return tokenNames[k]
+	}
+	return fmt.Sprintf("TokenKind(%d)", k)
+}
+
+// Token is one lexical element. For strings Text holds the decoded bytes,
+// for numbers the literal as written. Text is only valid until the next call
+// to Lexer.Next.
+type Token struct {
+	Kind TokenKind
+	Pos  Pos
+	Text []byte
+}
+
+// Lexer reads tokens from a stream one at a time, so arbitrarily large
+// inputs can be processed without loading them into memory.
+type Lexer struct {
+	r   *bufio.Reader
+	pos Pos
+	buf []byte
+}
+
+// NewLexer returns a Lexer reading from r.
+func NewLexer(r io.Reader) *Lexer {
+	return &Lexer{
+		r:   bufio.NewReader(r),
+		pos: Pos{Line: 1, Column: 1},
+	}
+}
+
+// Pos returns the position of the next unread byte.
+func (l *Lexer) Pos() Pos {
+	return l.pos
+}
+
+func (l *Lexer) errorf(p Pos, format string, args ...any) error {
+	return &SyntaxError{Pos: p, Msg: fmt.Sprintf(format, args...)}
+}
+
+// read consumes one byte and returns it together with its position.
+func (l *Lexer) read() (byte, Pos, error) {
+	p := l.pos
+	b, err := l.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return 0, p, l.errorf(p, "unexpected end of input")
+		}
+		return 0, p, err
+	}
+	l.pos.Offset++
+	switch {
+	case b == '\n':
+		l.pos.Line++
+		l.pos.Column = 1
+	case b&0xC0 != 0x80:
+		// Continuation bytes of a multi-byte rune do not move the column.
+		l.pos.Column++
+	}
+	return b, p, nil
+}
+
+// peek returns the next byte without consuming it; ok is false at EOF.
+func (l *Lexer) peek() (b byte, ok bool, err error) {
+	bs, err := l.r.Peek(1)
+	if err == io.EOF {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return bs[0], true, nil
+}
+
+// Next returns the next token. At the end of the input it returns a token of
+// kind TokenEOF and a nil error.
+func (l *Lexer) Next() (Token, error) {
+	if err := l.skipSpace(); err != nil {
+		return Token{}, err
+	}
+	start := l.pos
+	c, ok, err := l.peek()
+	if err != nil {
+		return Token{}, err
+	}
+	if !ok {
+		return Token{Kind: TokenEOF, Pos: start}, nil
+	}
+
+	var kind TokenKind
+	switch c {
+	case '{':
+		kind = TokenBeginObject
+	case '}':
+		kind = TokenEndObject
+	case '[':
+		kind = TokenBeginArray
+	case ']':
+		kind = TokenEndArray
+	case ':':
+		kind = TokenColon
+	case ',':
+		kind = TokenComma
+	case '"':
+		return l.lexString()
+	case 't':
+		return l.lexLiteral("true", TokenTrue)
+	case 'f':
+		return l.lexLiteral("false", TokenFalse)
+	case 'n':
+		return l.lexLiteral("null", TokenNull)
+	default:
+		if c == '-' || isDigit(c) {
+			return l.lexNumber()
+		}
+		return Token{}, l.errorf(start, "invalid character %s looking for beginning of value", quoteByte(c))
+	}
+	l.read()
+	return Token{Kind: kind, Pos: start}, nil
+}
+
+func (l *Lexer) skipSpace() error {
+	for {
+		c, ok, err := l.peek()
+		if err != nil || !ok {
+			return err
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return nil
+		}
+		l.read()
+	}
+}
+
+func (l *Lexer) lexLiteral(word string, kind TokenKind) (Token, error) {
+	start := l.pos
+	for i := 0; i < len(word); i++ {
+		c, p, err := l.read()
+		if err != nil {
+			return Token{}, err
+		}
+		if c != word[i] {
+			return Token{}, l.errorf(p, "invalid character %s in literal %s (expecting %s)", quoteByte(c), word, quoteByte(word[i]))
+		}
+	}
+	if err := l.checkDelimiter(); err != nil {
+		return Token{}, err
+	}
+	return Token{Kind: kind, Pos: start}, nil
+}
+
+// lexNumber runs the number grammar from RFC 8259 as a state machine:
+//
+//	[-] (0 | [1-9][0-9]*) [. [0-9]+] [(e|E) [+|-] [0-9]+]
+func (l *Lexer) lexNumber() (Token, error) {
+	const (
+		stSign = iota
+		stZero
+		stInt
+		stDot
+		stFrac
+		stExp
+		stExpSign
+		stExpDigits
+	)
+	start := l.pos
+	l.buf = l.buf[:0]
+	state := stSign
+	for {
+		c, ok, err := l.peek()
+		if err != nil {
+			return Token{}, err
+		}
+		next := -1
+		if ok {
+			switch state {
+			case stSign:
+				switch {
+				case c == '-' && len(l.buf) == 0:
+					next = stSign
+				case c == '0':
+					next = stZero
+				case isDigit(c):
+					next = stInt
+				}
+			case stZero, stInt:
+				switch {
+				case isDigit(c) && state == stInt:
+					next = stInt
+				case c == '.':
+					next = stDot
+				case c == 'e' || c == 'E':
+					next = stExp
+				}
+			case stDot, stFrac:
+				switch {
+				case isDigit(c):
+					next = stFrac
+				case state == stFrac && (c == 'e' || c == 'E'):
+					next = stExp
+				}
+			case stExp:
+				switch {
+				case c == '+' || c == '-':
+					next = stExpSign
+				case isDigit(c):
+					next = stExpDigits
+				}
+			case stExpSign, stExpDigits:
+				if isDigit(c) {
+					next = stExpDigits
+				}
+			}
+		}
+		if next < 0 {
+			break
+		}
+		l.read()
+		l.buf = append(l.buf, c)
+		state = next
+	}
+	switch state {
+	case stZero, stInt, stFrac, stExpDigits:
+	default:
+		p := l.pos
+		c, ok, _ := l.peek()
+		if !ok {
+			return Token{}, l.errorf(p, "unexpected end of input in number")
+		}
+		return Token{}, l.errorf(p, "invalid character %s in number", quoteByte(c))
+	}
+	if err := l.checkDelimiter(); err != nil {
+		return Token{}, err
+	}
+	return Token{Kind: TokenNumber, Pos: start, Text: l.buf}, nil
+}
+
+// checkDelimiter rejects input such as "01" or "truex", where a literal runs
+// straight into another word instead of ending at a delimiter.
+func (l *Lexer) checkDelimiter() error {
+	c, ok, err := l.peek()
+	if err != nil || !ok {
+		return err
+	}
+	if isDigit(c) || c == '.' || c == '+' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return l.errorf(l.pos, "invalid character %s after literal", quoteByte(c))
+	}
+	return nil
+}
+
+func (l *Lexer) lexString() (Token, error) {
+	start := l.pos
+	l.read() // opening quote
+	l.buf = l.buf[:0]
+	for {
+		c, p, err := l.read()
+		if err != nil {
+			if _, ok := err.(*SyntaxError); ok {
+				err = l.errorf(p, "unterminated string starting at %s", start)
+			}
+			return Token{}, err
+		}
+		switch {
+		case c == '"':
+			return Token{Kind: TokenString, Pos: start, Text: l.buf}, nil
+		case c == '\\':
+			if err := l.lexEscape(); err != nil {
+				return Token{}, err
+			}
+		case c < 0x20:
+			return Token{}, l.errorf(p, "invalid control character %s in string", quoteByte(c))
+		case c < utf8.RuneSelf:
+			l.buf = append(l.buf, c)
+		default:
+			if err := l.lexRune(c, p); err != nil {
+				return Token{}, err
+			}
+		}
+	}
+}
+
+// lexRune copies one multi-byte UTF-8 sequence whose first byte is c,
+// rejecting invalid encodings.
+func (l *Lexer) lexRune(c byte, p Pos) error {
+	var n int
+	switch {
+	case c&0xE0 == 0xC0:
+		n = 2
+	case c&0xF0 == 0xE0:
+		n = 3
+	case c&0xF8 == 0xF0:
+		n = 4
+	default:
+		return l.errorf(p, "invalid UTF-8 byte %#x in string", c)
+	}
+	var seq [utf8.UTFMax]byte
+	seq[0] = c
+	for i := 1; i < n; i++ {
+		b, ok, err := l.peek()
+		if err != nil {
+			return err
+		}
+		if !ok || b&0xC0 != 0x80 {
+			return l.errorf(p, "invalid UTF-8 sequence in string")
+		}
+		l.read()
+		seq[i] = b
+	}
+	if !utf8.Valid(seq[:n]) {
+		return l.errorf(p, "invalid UTF-8 sequence in string")
+	}
+	l.buf = append(l.buf, seq[:n]...)
+	return nil
+}
+
+func (l *Lexer) lexEscape() error {
+	c, p, err := l.read()
+	if err != nil {
+		return err
+	}
+	switch c {
+	case '"', '\\', '/':
+		l.buf = append(l.buf, c)
+	case 'b':
+		l.buf = append(l.buf, '\b')
+	case 'f':
+		l.buf = append(l.buf, '\f')
+	case 'n':
+		l.buf = append(l.buf, '\n')
+	case 'r':
+		l.buf = append(l.buf, '\r')
+	case 't':
+		l.buf = append(l.buf, '\t')
+	case 'u':
+		r, err := l.readHex4()
+		if err != nil {
+			return err
+		}
+		if utf16.IsSurrogate(r) {
+			r, err = l.lexSurrogate(r)
+			if err != nil {
+				return err
+			}
+		}
+		l.buf = utf8.AppendRune(l.buf, r)
+	default:
+		return l.errorf(p, "invalid escape character %s in string", quoteByte(c))
+	}
+	return nil
+}
+
+// lexSurrogate completes a UTF-16 surrogate pair written as two \u escapes.
+// Lone surrogates cannot be represented in UTF-8 and become U+FFFD.
+func (l *Lexer) lexSurrogate(high rune) (rune, error) {
+	if high >= 0xDC00 {
+		return utf8.RuneError, nil
+	}
+	bs, err := l.r.Peek(2)
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if len(bs) < 2 || bs[0] != '\\' || bs[1] != 'u' {
+		return utf8.RuneError, nil
+	}
+	l.read()
+	l.read()
+	low, err := l.readHex4()
+	if err != nil {
+		return 0, err
+	}
+	if r := utf16.DecodeRune(high, low); r != utf8.RuneError {
+		return r, nil
+	}
+	// Not a valid pair: emit the lone high surrogate as U+FFFD and keep the
+	// second escape if it stands on its own.
+	l.buf = utf8.AppendRune(l.buf, utf8.RuneError)
+	if utf16.IsSurrogate(low) {
+		return utf8.RuneError, nil
+	}
+	return low, nil
+}
+
+func (l *Lexer) readHex4() (rune, error) {
+	var r rune
+	for i := 0; i < 4; i++ {
+		c, p, err := l.read()
+		if err != nil {
+			return 0, err
+		}
+		var d byte
+		switch {
+		case isDigit(c):
+			d = c - '0'
+		case c >= 'a' && c <= 'f':
+			d = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			d = c - 'A' + 10
+		default:
+			return 0, l.errorf(p, "invalid character %s in \\u escape", quoteByte(c))
+		}
+		r = r<<4 | rune(d)
+	}
+	return r, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func quoteByte(c byte) string {
+	if c == '\'' {
+		return `'\''`
+	}
+	if c < 0x20 || c >= 0x7F {
+		return fmt.Sprintf("%#02x", c)
+	}
+	return "'" + string(c) + "'"
+}
@@ -0,0 +1,89 @@
+package jsonparse
+
+import (
+	"bytes"
+	"io"
+)
+
+// Parse parses data, which must hold exactly one JSON value.
+func Parse(data []byte) (Value, error) {
+	r := NewReader(bytes.NewReader(data))
+	v, err := readValue(r)
+	if err == io.EOF {
+		return Value{}, &SyntaxError{Pos: r.lex.Pos(), Msg: "unexpected end of input"}
+	}
+	if err != nil {
+		return Value{}, err
+	}
+	ev, err := r.Next()
+	if err == io.EOF {
+		return v, nil
+	}
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{}, &SyntaxError{Pos: ev.Pos, Msg: "unexpected data after top-level value"}
+}
+
+// Decoder reads a stream of JSON values, such as NDJSON, one at a time.
+type Decoder struct {
+	r *Reader
+}
+
+// NewDecoder returns a Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: NewReader(r)}
+}
+
+// Decode returns the next value in the stream, or io.EOF when there are no
+// more values.
+func (d *Decoder) Decode() (Value, error) {
+	return readValue(d.r)
+}
+
+// readValue builds one Value from the event stream. It keeps its own stack
+// instead of recursing, so deeply nested input cannot overflow the Go stack.
+func readValue(r *Reader) (Value, error) {
+	type frame struct {
+		v   Value
+		key string
+	}
+	var stack []frame
+	for {
+		ev, err := r.Next()
+		if err == io.EOF && len(stack) > 0 {
+			return Value{}, &SyntaxError{Pos: r.lex.Pos(), Msg: "unexpected end of input"}
+		}
+		if err != nil {
+			return Value{}, err
+		}
+
+		var done Value
+		switch ev.Kind {
+		case EventStartObject:
+			stack = append(stack, frame{v: Value{Kind: Object}})
+			continue
+		case EventStartArray:
+			stack = append(stack, frame{v: Value{Kind: Array}})
+			continue
+		case EventKey:
+			stack[len(stack)-1].key = ev.Key
+			continue
+		case EventEndObject, EventEndArray:
+			done = stack[len(stack)-1].v
+			stack = stack[:len(stack)-1]
+		case EventValue:
+			done = ev.Value
+		}
+
+		if len(stack) == 0 {
+			return done, nil
+		}
+		top := &stack[len(stack)-1]
+		if top.v.Kind == Object {
+			top.v.Members = append(top.v.Members, Member{Key: top.key, Value: done})
+		} else {
+			top.v.Items = append(top.v.Items, done)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package jsonparse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSuite runs the fixtures in testdata/suite by JSONTestSuite's naming
+// convention: y_ files must parse, n_ files must be rejected and i_ files
+// may go either way. They are a curated subset of 39 cases, not the full
+// suite of about 300.
+func TestSuite(t *testing.T) {
+	names, err := filepath.Glob(filepath.Join("testdata", "suite", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Fatal("no fixtures in testdata/suite")
+	}
+	for _, name := range names {
+		base := filepath.Base(name)
+		t.Run(strings.TrimSuffix(base, ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Parse(data)
+			switch base[:2] {
+			case "y_":
+				if err != nil {
+					t.Errorf("rejected valid input: %v", err)
+				}
+			case "n_":
+				if err == nil {
+					t.Error("accepted invalid input")
+				}
+			default:
+				t.Logf("implementation-defined, got error %v", err)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorPosition(t *testing.T) {
+	tests := []struct {
+		in   string
+		line int
+		col  int
+	}{
+		{`[1, 2,]`, 1, 7},
+		{"{\n  \"a\": 1,\n  \"b\" 2\n}", 3, 7},
+		{`"سلام`, 1, 6},
+		{`[01]`, 1, 3},
+		{`{"a": tru}`, 1, 10},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.in))
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q): got %v, want a *SyntaxError", tt.in, err)
+			continue
+		}
+		if se.Pos.Line != tt.line || se.Pos.Column != tt.col {
+			t.Errorf("Parse(%q): error at %v (%v), want %d:%d", tt.in, se.Pos, err, tt.line, tt.col)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []string{
+		`null`,
+		`[true,false,-0.5,1e+21,"\u0000\n\"\\"]`,
+		`{"name":"سلام","scores":[90,85.5],"nested":{"empty":{}}}`,
+	}
+	for _, in := range tests {
+		v, err := Parse([]byte(in))
+		if err != nil {
+			t.Fatalf("Parse(%s): %v", in, err)
+		}
+		w, err := Parse(Marshal(v))
+		if err != nil {
+			t.Fatalf("Parse(Marshal(%s)): %v", in, err)
+		}
+		if !v.Equal(w) {
+			t.Errorf("%s round-tripped to %s", in, Marshal(w))
+		}
+	}
+}
+
+func TestEvents(t *testing.T) {
+	r := NewReader(strings.NewReader(`{"a":[1,"x"],"b":null}`))
+	var got []string
+	for {
+		ev, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := fmt.Sprintf("%d %v", ev.Depth, ev.Kind)
+		switch ev.Kind {
+		case EventKey:
+			s += " " + ev.Key
+		case EventValue:
+			s += " " + ev.Value.String()
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"0 StartObject",
+		"1 Key a", "1 StartArray", "2 Value 1", `2 Value "x"`, "1 EndArray",
+		"1 Key b", "1 Value null",
+		"0 EndObject",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got events\n%q\nwant\n%q", got, want)
+	}
+}
+
+// benchData is a document shaped like the students payloads the other
+// commands read: many small objects with strings and numbers.
+var benchData = func() []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"students":[`)
+	for i := range 1000 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"name":"student %d","age":%d,"scores":[%d,%d.5,%d],"active":%t,"note":null}`,
+			i, 18+i%10, i%100, i%50, i%7, i%2 == 0)
+	}
+	b.WriteString(`]}`)
+	return b.Bytes()
+}()
+
+// The benchmarks compare building a tree of Value nodes, only walking the
+// events, and the two matching ways of encoding/json. The allocation
+// columns show what the tree costs.
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchData)))
+	for b.Loop() {
+		if _, err := Parse(benchData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseReader(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchData)))
+	for b.Loop() {
+		r := NewReader(bytes.NewReader(benchData))
+		for {
+			_, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseEncodingJSON(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchData)))
+	for b.Loop() {
+		var v any
+		if err := json.Unmarshal(benchData, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseEncodingJSONToken(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchData)))
+	for b.Loop() {
+		dec := json.NewDecoder(bytes.NewReader(benchData))
+		for {
+			_, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package jsonparse
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// EventKind identifies an Event produced by Reader.
+type EventKind uint8
+
+const (
+	EventStartObject EventKind = iota + 1
+	EventEndObject
+	EventStartArray
+	EventEndArray
+	// EventKey carries an object key in Event.Key.
+	EventKey
+	// EventValue carries a scalar (null, boolean, number or string) in
+	// Event.Value.
+	EventValue
+)
+
+var eventNames = [...]string{
+	EventStartObject: "StartObject",
+	EventEndObject:   "EndObject",
+	EventStartArray:  "StartArray",
+	EventEndArray:    "EndArray",
+	EventKey:         "Key",
+	EventValue:       "Value",
+}
+
+func (k EventKind) String() string {
+	if int(k) < len(eventNames) && eventNames[k] != "" {
+		return eventNames[k]
+	}
+	return fmt.Sprintf("EventKind(%d)", k)
+}
+
+// Event is one step of the SAX-style stream. Depth is the nesting level of
+// the event: 0 for a top-level value, 1 inside the outermost container and so
+// on.
+type Event struct {
+	Kind  EventKind
+	Pos   Pos
+	Depth int
+	Key   string
+	Value Value
+}
+
+// state is what the Reader expects to see next.
+type state uint8
+
+const (
+	stateValue       state = iota // any value
+	stateArrayFirst               // a value or ']'
+	stateObjectFirst              // a key or '}'
+	stateObjectKey                // a key after ','
+	stateColon                    // ':' after a key
+	stateAfterValue               // ',' or the closing bracket
+)
+
+// Reader validates the token stream against the JSON grammar and returns it
+// as events. The input may hold several top-level values one after another,
+// as in NDJSON; Next returns io.EOF once all of them are consumed.
+type Reader struct {
+	lex   *Lexer
+	stack []TokenKind // open containers: TokenBeginObject or TokenBeginArray
+	state state
+}
+
+// NewReader returns a Reader over r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{lex: NewLexer(r)}
+}
+
+// Depth returns the number of containers currently open.
+func (r *Reader) Depth() int {
+	return len(r.stack)
+}
+
+func unexpected(tok Token, want string) error {
+	return &SyntaxError{Pos: tok.Pos, Msg: fmt.Sprintf("unexpected %s, expecting %s", tok.Kind, want)}
+}
+
+// Next returns the next event.
+func (r *Reader) Next() (Event, error) {
+	for {
+		tok, err := r.lex.Next()
+		if err != nil {
+			return Event{}, err
+		}
+		switch r.state {
+		case stateValue, stateArrayFirst:
+			if tok.Kind == TokenEOF && len(r.stack) == 0 {
+				return Event{}, io.EOF
+			}
+			if r.state == stateArrayFirst && tok.Kind == TokenEndArray {
+				return r.close(tok), nil
+			}
+			return r.value(tok)
+
+		case stateObjectFirst, stateObjectKey:
+			if r.state == stateObjectFirst && tok.Kind == TokenEndObject {
+				return r.close(tok), nil
+			}
+			if tok.Kind != TokenString {
+				return Event{}, unexpected(tok, "object key string")
+			}
+			r.state = stateColon
+			return Event{Kind: EventKey, Pos: tok.Pos, Depth: len(r.stack), Key: string(tok.Text)}, nil
+
+		case stateColon:
+			if tok.Kind != TokenColon {
+				return Event{}, unexpected(tok, "':' after object key")
+			}
+			r.state = stateValue
+
+		case stateAfterValue:
+			top := r.stack[len(r.stack)-1]
+			switch {
+			case tok.Kind == TokenComma && top == TokenBeginObject:
+				r.state = stateObjectKey
+			case tok.Kind == TokenComma:
+				r.state = stateValue
+			case tok.Kind == TokenEndObject && top == TokenBeginObject,
+				tok.Kind == TokenEndArray && top == TokenBeginArray:
+				return r.close(tok), nil
+			case top == TokenBeginObject:
+				return Event{}, unexpected(tok, "',' or '}' after object value")
+			default:
+				return Event{}, unexpected(tok, "',' or ']' after array element")
+			}
+		}
+	}
+}
+
+// value handles a token in a position where a value must start.
+func (r *Reader) value(tok Token) (Event, error) {
+	ev := Event{Pos: tok.Pos, Depth: len(r.stack)}
+	switch tok.Kind {
+	case TokenBeginObject:
+		r.stack = append(r.stack, tok.Kind)
+		r.state = stateObjectFirst
+		ev.Kind = EventStartObject
+		return ev, nil
+	case TokenBeginArray:
+		r.stack = append(r.stack, tok.Kind)
+		r.state = stateArrayFirst
+		ev.Kind = EventStartArray
+		return ev, nil
+	case TokenNull:
+		ev.Value = NullValue()
+	case TokenTrue, TokenFalse:
+		ev.Value = BoolValue(tok.Kind == TokenTrue)
+	case TokenString:
+		ev.Value = StringValue(string(tok.Text))
+	case TokenNumber:
+		text := string(tok.Text)
+		// The lexer already checked the syntax; the only possible error is
+		// ErrRange, for which ParseFloat still returns ±Inf or 0.
+		f, _ := strconv.ParseFloat(text, 64)
+		ev.Value = Value{Kind: Number, Num: f, Text: text}
+	default:
+		return Event{}, unexpected(tok, "value")
+	}
+	ev.Kind = EventValue
+	r.afterValue()
+	return ev, nil
+}
+
+func (r *Reader) close(tok Token) Event {
+	r.stack = r.stack[:len(r.stack)-1]
+	ev := Event{Kind: EventEndArray, Pos: tok.Pos, Depth: len(r.stack)}
+	if tok.Kind == TokenEndObject {
+		ev.Kind = EventEndObject
+	}
+	r.afterValue()
+	return ev
+}
+
+func (r *Reader) afterValue() {
+	if len(r.stack) == 0 {
+		r.state = stateValue
+	} else {
+		r.state = stateAfterValue
+	}
+}
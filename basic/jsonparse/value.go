@@ -0,0 +1,189 @@
+package jsonparse
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kind is the JSON type of a Value.
+type Kind uint8
+
+const (
+	Null Kind = iota
+	Bool
+	Number
+	String
+	Array
+	Object
+)
+
+var kindNames = [...]string{
+	Null:   "null",
+	Bool:   "boolean",
+	Number: "number",
+	String: "string",
+	Array:  "array",
+	Object: "object",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+// Value is one node of a parsed JSON document. Only the fields matching Kind
+// are meaningful; switch on Kind before reading them:
+//
+//	switch v.Kind {
+//	case jsonparse.String:
+//		fmt.Println(v.Str)
+//	case jsonparse.Array:
+//		for _, item := range v.Items { ... }
+//	}
+type Value struct {
+	Kind Kind
+	Bool bool
+	Num  float64
+	// Text keeps a number literal exactly as it appeared in the input, so
+	// integers larger than float64 can represent are not lost.
+	Text    string
+	Str     string
+	Items   []Value
+	Members []Member
+}
+
+// Member is one key/value pair of an object. Objects keep their members in
+// input order, duplicates included.
+type Member struct {
+	Key   string
+	Value Value
+}
+
+// NullValue returns a JSON null.
+func NullValue() Value { return Value{Kind: Null} }
+
+// BoolValue returns a JSON boolean.
+func BoolValue(b bool) Value { return Value{Kind: Bool, Bool: b} }
+
+// NumberValue returns a JSON number.
+func NumberValue(f float64) Value { return Value{Kind: Number, Num: f} }
+
+// StringValue returns a JSON string.
+func StringValue(s string) Value { return Value{Kind: String, Str: s} }
+
+// ArrayValue returns a JSON array holding items.
+func ArrayValue(items ...Value) Value { return Value{Kind: Array, Items: items} }
+
+// ObjectValue returns a JSON object holding members.
+func ObjectValue(members ...Member) Value { return Value{Kind: Object, Members: members} }
+
+// Get returns the value stored under key in an object. When a key appears
+// more than once the last occurrence wins, as in most JSON decoders.
+func (v Value) Get(key string) (Value, bool) {
+	if v.Kind != Object {
+		return Value{}, false
+	}
+	for i := len(v.Members) - 1; i >= 0; i-- {
+		if v.Members[i].Key == key {
+			return v.Members[i].Value, true
+		}
+	}
+	return Value{}, false
+}
+
+// Len returns the number of items of an array, members of an object or
+// bytes of a string, and 0 for other kinds.
+func (v Value) Len() int {
+	switch v.Kind {
+	case Array:
+		return len(v.Items)
+	case Object:
+		return len(v.Members)
+	case String:
+		return len(v.Str)
+	}
+	return 0
+}
+
+// Truthy reports whether v counts as true: everything except null and false.
+func (v Value) Truthy() bool {
+	return !(v.Kind == Null || v.Kind == Bool && !v.Bool)
+}
+
+// IsInteger reports whether v is a number without a fractional part.
+func (v Value) IsInteger() bool {
+	return v.Kind == Number && v.Num == math.Trunc(v.Num) && !math.IsInf(v.Num, 0)
+}
+
+// Equal reports whether v and w are the same JSON value. Numbers compare by
+// value and objects compare without regard to member order.
+func (v Value) Equal(w Value) bool {
+	if v.Kind != w.Kind {
+		return false
+	}
+	switch v.Kind {
+	case Null:
+		return true
+	case Bool:
+		return v.Bool == w.Bool
+	case Number:
+		return v.Num == w.Num
+	case String:
+		return v.Str == w.Str
+	case Array:
+		if len(v.Items) != len(w.Items) {
+			return false
+		}
+		for i := range v.Items {
+			if !v.Items[i].Equal(w.Items[i]) {
+				return false
+			}
+		}
+		return true
+	case Object:
+		if len(v.Members) != len(w.Members) {
+			return false
+		}
+		for _, m := range v.Members {
+			other, ok := w.Get(m.Key)
+			if !ok || !m.Value.Equal(other) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// Interface converts v to the Go values encoding/json would produce:
+// nil, bool, float64, string, []any and map[string]any.
+func (v Value) Interface() any {
+	switch v.Kind {
+	case Bool:
+		return v.Bool
+	case Number:
+		return v.Num
+	case String:
+		return v.Str
+	case Array:
+		out := make([]any, len(v.Items))
+		for i, item := range v.Items {
+			out[i] = item.Interface()
+		}
+		return out
+	case Object:
+		out := make(map[string]any, len(v.Members))
+		for _, m := range v.Members {
+			out[m.Key] = m.Value.Interface()
+		}
+		return out
+	}
+	return nil
+}
+
+// String returns the compact JSON encoding of v.
+func (v Value) String() string {
+	return string(Marshal(v))
+}
@@ -0,0 +1,379 @@
+// Package jsonschema validates JSON documents against a subset of JSON
+// Schema draft 2020-12.
+//
+// Supported keywords: type, enum, const, properties, required,
+// additionalProperties, items, prefixItems, minItems, maxItems, minLength,
+// maxLength, minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// multipleOf, pattern, format (email, date-time, uri), allOf, anyOf, oneOf,
+// not and $ref to locations inside the same document ("#", "#/$defs/name").
+// Unknown keywords are ignored, as the specification requires.
+//
+// Documents are jsonparse.Value trees, so schema and instance errors both
+// come with JSON Pointer locations.
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// Schema is a compiled schema, safe for concurrent use by multiple
+// goroutines.
+type Schema struct {
+	root *node
+}
+
+// node is one compiled schema object. Pointer fields are nil when the
+// keyword is absent.
+type node struct {
+	loc string // JSON Pointer of this schema inside the document
+
+	always *bool // set for the boolean schemas true and false
+
+	types    []string
+	enum     []jsonparse.Value
+	constVal *jsonparse.Value
+
+	properties           []property
+	required             []string
+	additionalProperties *node
+
+	prefixItems []*node
+	items       *node
+	minItems    *int
+	maxItems    *int
+
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	format    string
+
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	multipleOf       *float64
+
+	allOf []*node
+	anyOf []*node
+	oneOf []*node
+	not   *node
+
+	ref    string
+	target *node // resolved $ref
+}
+
+type property struct {
+	name   string
+	schema *node
+}
+
+// SchemaError reports a malformed schema.
+type SchemaError struct {
+	Location string // JSON Pointer of the offending keyword
+	Msg      string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("jsonschema: %s: %s", pointerOrRoot(e.Location), e.Msg)
+}
+
+// Compile parses and compiles a schema document.
+func Compile(data []byte) (*Schema, error) {
+	doc, err := jsonparse.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return CompileValue(doc)
+}
+
+// MustCompile is like Compile but panics on error. It is meant for schemas
+// embedded in the program.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// CompileValue compiles an already parsed schema document.
+func CompileValue(doc jsonparse.Value) (*Schema, error) {
+	c := &compiler{doc: doc, nodes: map[string]*node{}}
+	root, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	// Resolve references only after the whole document has been compiled,
+	// so recursive schemas can point at themselves.
+	for i := 0; i < len(c.refs); i++ {
+		n := c.refs[i]
+		target, err := c.resolve(n)
+		if err != nil {
+			return nil, err
+		}
+		n.target = target
+	}
+	return &Schema{root: root}, nil
+}
+
+type compiler struct {
+	doc   jsonparse.Value
+	nodes map[string]*node
+	refs  []*node
+}
+
+func (c *compiler) errorf(loc, format string, args ...any) error {
+	return &SchemaError{Location: loc, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (c *compiler) compile(v jsonparse.Value, loc string) (*node, error) {
+	if n, ok := c.nodes[loc]; ok {
+		return n, nil
+	}
+	n := &node{loc: loc}
+	c.nodes[loc] = n
+
+	switch v.Kind {
+	case jsonparse.Bool:
+		b := v.Bool
+		n.always = &b
+		return n, nil
+	case jsonparse.Object:
+	default:
+		return nil, c.errorf(loc, "schema must be an object or a boolean, not %s", v.Kind)
+	}
+
+	for _, m := range v.Members {
+		kw, val, at := m.Key, m.Value, loc+"/"+escapePointer(m.Key)
+		var err error
+		switch kw {
+		case "type":
+			n.types, err = c.compileTypes(val, at)
+		case "enum":
+			if val.Kind != jsonparse.Array {
+				return nil, c.errorf(at, "must be an array")
+			}
+			n.enum = val.Items
+		case "const":
+			cv := val
+			n.constVal = &cv
+		case "properties":
+			if val.Kind != jsonparse.Object {
+				return nil, c.errorf(at, "must be an object")
+			}
+			for _, p := range val.Members {
+				sub, err := c.compile(p.Value, at+"/"+escapePointer(p.Key))
+				if err != nil {
+					return nil, err
+				}
+				n.properties = append(n.properties, property{name: p.Key, schema: sub})
+			}
+		case "required":
+			n.required, err = c.compileStrings(val, at)
+		case "additionalProperties":
+			n.additionalProperties, err = c.compile(val, at)
+		case "items":
+			n.items, err = c.compile(val, at)
+		case "prefixItems":
+			n.prefixItems, err = c.compileList(val, at)
+		case "allOf":
+			n.allOf, err = c.compileList(val, at)
+		case "anyOf":
+			n.anyOf, err = c.compileList(val, at)
+		case "oneOf":
+			n.oneOf, err = c.compileList(val, at)
+		case "not":
+			n.not, err = c.compile(val, at)
+		case "minItems":
+			n.minItems, err = c.compileCount(val, at)
+		case "maxItems":
+			n.maxItems, err = c.compileCount(val, at)
+		case "minLength":
+			n.minLength, err = c.compileCount(val, at)
+		case "maxLength":
+			n.maxLength, err = c.compileCount(val, at)
+		case "minimum":
+			n.minimum, err = c.compileNumber(val, at)
+		case "maximum":
+			n.maximum, err = c.compileNumber(val, at)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = c.compileNumber(val, at)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = c.compileNumber(val, at)
+		case "multipleOf":
+			n.multipleOf, err = c.compileNumber(val, at)
+			if err == nil && *n.multipleOf <= 0 {
+				err = c.errorf(at, "must be greater than 0")
+			}
+		case "pattern":
+			if val.Kind != jsonparse.String {
+				return nil, c.errorf(at, "must be a string")
+			}
+			n.pattern, err = regexp.Compile(val.Str)
+			if err != nil {
+				err = c.errorf(at, "invalid pattern: %v", err)
+			}
+		case "format":
+			if val.Kind != jsonparse.String {
+				return nil, c.errorf(at, "must be a string")
+			}
+			n.format = val.Str
+		case "$ref":
+			if val.Kind != jsonparse.String {
+				return nil, c.errorf(at, "must be a string")
+			}
+			n.ref = val.Str
+			c.refs = append(c.refs, n)
+		case "$defs", "definitions":
+			if val.Kind != jsonparse.Object {
+				return nil, c.errorf(at, "must be an object")
+			}
+			for _, d := range val.Members {
+				if _, err := c.compile(d.Value, at+"/"+escapePointer(d.Key)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+var typeNames = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "string": true, "integer": true,
+}
+
+func (c *compiler) compileTypes(v jsonparse.Value, loc string) ([]string, error) {
+	if v.Kind == jsonparse.String {
+		v = jsonparse.ArrayValue(v)
+	}
+	names, err := c.compileStrings(v, loc)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if !typeNames[name] {
+			return nil, c.errorf(loc, "unknown type %q", name)
+		}
+	}
+	return names, nil
+}
+
+func (c *compiler) compileStrings(v jsonparse.Value, loc string) ([]string, error) {
+	if v.Kind != jsonparse.Array {
+		return nil, c.errorf(loc, "must be an array of strings")
+	}
+	out := make([]string, len(v.Items))
+	for i, item := range v.Items {
+		if item.Kind != jsonparse.String {
+			return nil, c.errorf(fmt.Sprintf("%s/%d", loc, i), "must be a string")
+		}
+		out[i] = item.Str
+	}
+	return out, nil
+}
+
+func (c *compiler) compileList(v jsonparse.Value, loc string) ([]*node, error) {
+	if v.Kind != jsonparse.Array || len(v.Items) == 0 {
+		return nil, c.errorf(loc, "must be a non-empty array of schemas")
+	}
+	out := make([]*node, len(v.Items))
+	for i, item := range v.Items {
+		n, err := c.compile(item, fmt.Sprintf("%s/%d", loc, i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func (c *compiler) compileCount(v jsonparse.Value, loc string) (*int, error) {
+	if !v.IsInteger() || v.Num < 0 {
+		return nil, c.errorf(loc, "must be a non-negative integer")
+	}
+	n := int(v.Num)
+	return &n, nil
+}
+
+func (c *compiler) compileNumber(v jsonparse.Value, loc string) (*float64, error) {
+	if v.Kind != jsonparse.Number || math.IsInf(v.Num, 0) {
+		return nil, c.errorf(loc, "must be a number")
+	}
+	f := v.Num
+	return &f, nil
+}
+
+// resolve finds the node a $ref points at. Only fragments within the
+// current document are supported.
+func (c *compiler) resolve(n *node) (*node, error) {
+	at := n.loc + "/$ref"
+	if !strings.HasPrefix(n.ref, "#") {
+		return nil, c.errorf(at, "only references within the document are supported, got %q", n.ref)
+	}
+	ptr := n.ref[1:]
+	if target, ok := c.nodes[ptr]; ok {
+		return target, nil
+	}
+	// The reference points at a location that was not compiled as a schema
+	// yet, e.g. a custom container keyword. Compile it on demand.
+	v, err := lookupPointer(c.doc, ptr)
+	if err != nil {
+		return nil, c.errorf(at, "%v", err)
+	}
+	return c.compile(v, ptr)
+}
+
+// lookupPointer evaluates a JSON Pointer (RFC 6901) against doc.
+func lookupPointer(doc jsonparse.Value, ptr string) (jsonparse.Value, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+	if ptr[0] != '/' {
+		return jsonparse.Value{}, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+	cur := doc
+	for _, tok := range strings.Split(ptr[1:], "/") {
+		tok = unescapePointer(tok)
+		switch cur.Kind {
+		case jsonparse.Object:
+			v, ok := cur.Get(tok)
+			if !ok {
+				return jsonparse.Value{}, fmt.Errorf("reference %q not found", ptr)
+			}
+			cur = v
+		case jsonparse.Array:
+			var i int
+			if _, err := fmt.Sscanf(tok, "%d", &i); err != nil || i < 0 || i >= len(cur.Items) {
+				return jsonparse.Value{}, fmt.Errorf("reference %q not found", ptr)
+			}
+			cur = cur.Items[i]
+		default:
+			return jsonparse.Value{}, fmt.Errorf("reference %q not found", ptr)
+		}
+	}
+	return cur, nil
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+func escapePointer(s string) string   { return pointerEscaper.Replace(s) }
+func unescapePointer(s string) string { return pointerUnescaper.Replace(s) }
+
+func pointerOrRoot(p string) string {
+	if p == "" {
+		return "(root)"
+	}
+	return p
+}
@@ -0,0 +1,290 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amiiralihassanpour/golang_learning/jsonparse"
+)
+
+// maxDepth bounds how deep $ref chains may recurse, so a schema such as
+// {"$ref": "#"} reports an error instead of looping forever.
+const maxDepth = 512
+
+// ValidationError is one failed keyword.
+type ValidationError struct {
+	// InstanceLocation is the JSON Pointer of the offending value in the
+	// validated document.
+	InstanceLocation string
+	// KeywordLocation is the JSON Pointer of the failing keyword in the
+	// schema.
+	KeywordLocation string
+	Message         string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", pointerOrRoot(e.InstanceLocation), e.Message)
+}
+
+// ValidationErrors lists every failure found in a document.
+type ValidationErrors []*ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Validate checks v against the schema. It returns nil when v is valid and
+// a ValidationErrors value holding every failure otherwise.
+func (s *Schema) Validate(v jsonparse.Value) error {
+	var errs ValidationErrors
+	s.root.validate(v, "", &errs, 0)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateBytes parses data and validates it.
+func (s *Schema) ValidateBytes(data []byte) error {
+	v, err := jsonparse.Parse(data)
+	if err != nil {
+		return err
+	}
+	return s.Validate(v)
+}
+
+func (n *node) fail(errs *ValidationErrors, inst, keyword, format string, args ...any) {
+	*errs = append(*errs, &ValidationError{
+		InstanceLocation: inst,
+		KeywordLocation:  n.loc + "/" + keyword,
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+// valid reports whether v matches n, discarding the individual errors.
+func (n *node) valid(v jsonparse.Value, inst string, depth int) bool {
+	var errs ValidationErrors
+	n.validate(v, inst, &errs, depth)
+	return len(errs) == 0
+}
+
+func (n *node) validate(v jsonparse.Value, inst string, errs *ValidationErrors, depth int) {
+	if n.always != nil {
+		if !*n.always {
+			*errs = append(*errs, &ValidationError{InstanceLocation: inst, KeywordLocation: n.loc, Message: "no value is allowed here"})
+		}
+		return
+	}
+	if n.target != nil {
+		if depth >= maxDepth {
+			n.fail(errs, inst, "$ref", "reference nesting exceeds %d levels", maxDepth)
+			return
+		}
+		n.target.validate(v, inst, errs, depth+1)
+	}
+
+	if len(n.types) > 0 && !matchesType(v, n.types) {
+		n.fail(errs, inst, "type", "expected %s, got %s", strings.Join(n.types, " or "), typeOf(v))
+	}
+	// Every keyword is checked on its own, even after a type mismatch. The
+	// keywords for one kind of value, such as minLength, only apply to
+	// values of that kind, so they add no follow-up errors of their own.
+	if n.constVal != nil && !v.Equal(*n.constVal) {
+		n.fail(errs, inst, "const", "must be %s", n.constVal)
+	}
+	if n.enum != nil && !inEnum(v, n.enum) {
+		n.fail(errs, inst, "enum", "must be one of %s", jsonparse.ArrayValue(n.enum...))
+	}
+
+	switch v.Kind {
+	case jsonparse.Number:
+		n.validateNumber(v.Num, inst, errs)
+	case jsonparse.String:
+		n.validateString(v.Str, inst, errs)
+	case jsonparse.Array:
+		n.validateArray(v, inst, errs, depth)
+	case jsonparse.Object:
+		n.validateObject(v, inst, errs, depth)
+	}
+
+	for _, sub := range n.allOf {
+		sub.validate(v, inst, errs, depth+1)
+	}
+	if n.anyOf != nil {
+		ok := false
+		for _, sub := range n.anyOf {
+			if sub.valid(v, inst, depth+1) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			n.fail(errs, inst, "anyOf", "must match at least one schema in anyOf")
+		}
+	}
+	if n.oneOf != nil {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if sub.valid(v, inst, depth+1) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			n.fail(errs, inst, "oneOf", "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if n.not != nil && n.not.valid(v, inst, depth+1) {
+		n.fail(errs, inst, "not", "must not match the schema in not")
+	}
+}
+
+func (n *node) validateNumber(f float64, inst string, errs *ValidationErrors) {
+	if n.minimum != nil && f < *n.minimum {
+		n.fail(errs, inst, "minimum", "must be >= %v", *n.minimum)
+	}
+	if n.maximum != nil && f > *n.maximum {
+		n.fail(errs, inst, "maximum", "must be <= %v", *n.maximum)
+	}
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		n.fail(errs, inst, "exclusiveMinimum", "must be > %v", *n.exclusiveMinimum)
+	}
+	if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+		n.fail(errs, inst, "exclusiveMaximum", "must be < %v", *n.exclusiveMaximum)
+	}
+	if n.multipleOf != nil {
+		q := f / *n.multipleOf
+		if math.IsInf(q, 0) || math.Abs(q-math.Round(q)) > 1e-9 {
+			n.fail(errs, inst, "multipleOf", "must be a multiple of %v", *n.multipleOf)
+		}
+	}
+}
+
+func (n *node) validateString(s, inst string, errs *ValidationErrors) {
+	length := utf8.RuneCountInString(s)
+	if n.minLength != nil && length < *n.minLength {
+		n.fail(errs, inst, "minLength", "must be at least %d characters long", *n.minLength)
+	}
+	if n.maxLength != nil && length > *n.maxLength {
+		n.fail(errs, inst, "maxLength", "must be at most %d characters long", *n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		n.fail(errs, inst, "pattern", "must match pattern %q", n.pattern)
+	}
+	if n.format != "" {
+		if err := checkFormat(n.format, s); err != nil {
+			n.fail(errs, inst, "format", "is not a valid %s: %v", n.format, err)
+		}
+	}
+}
+
+func (n *node) validateArray(v jsonparse.Value, inst string, errs *ValidationErrors, depth int) {
+	if n.minItems != nil && len(v.Items) < *n.minItems {
+		n.fail(errs, inst, "minItems", "must have at least %d items", *n.minItems)
+	}
+	if n.maxItems != nil && len(v.Items) > *n.maxItems {
+		n.fail(errs, inst, "maxItems", "must have at most %d items", *n.maxItems)
+	}
+	for i, item := range v.Items {
+		at := fmt.Sprintf("%s/%d", inst, i)
+		switch {
+		case i < len(n.prefixItems):
+			n.prefixItems[i].validate(item, at, errs, depth+1)
+		case n.items != nil:
+			n.items.validate(item, at, errs, depth+1)
+		}
+	}
+}
+
+func (n *node) validateObject(v jsonparse.Value, inst string, errs *ValidationErrors, depth int) {
+	for _, name := range n.required {
+		if _, ok := v.Get(name); !ok {
+			n.fail(errs, inst, "required", "missing required property %q", name)
+		}
+	}
+	for _, m := range v.Members {
+		at := inst + "/" + escapePointer(m.Key)
+		if p := n.property(m.Key); p != nil {
+			p.validate(m.Value, at, errs, depth+1)
+		} else if n.additionalProperties != nil {
+			if n.additionalProperties.always != nil && !*n.additionalProperties.always {
+				n.fail(errs, inst, "additionalProperties", "property %q is not allowed", m.Key)
+				continue
+			}
+			n.additionalProperties.validate(m.Value, at, errs, depth+1)
+		}
+	}
+}
+
+func (n *node) property(name string) *node {
+	for _, p := range n.properties {
+		if p.name == name {
+			return p.schema
+		}
+	}
+	return nil
+}
+
+func matchesType(v jsonparse.Value, types []string) bool {
+	for _, t := range types {
+		if t == typeOf(v) || t == "number" && v.Kind == jsonparse.Number {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type name of v. Integral numbers report
+// "integer", which "number" also accepts.
+func typeOf(v jsonparse.Value) string {
+	if v.IsInteger() {
+		return "integer"
+	}
+	return v.Kind.String()
+}
+
+func inEnum(v jsonparse.Value, enum []jsonparse.Value) bool {
+	for _, e := range enum {
+		if v.Equal(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFormat validates the formats this package knows about. Unknown
+// formats are accepted, because the specification treats format as an
+// annotation unless a validator opts in.
+func checkFormat(format, s string) error {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return err
+		}
+		if addr.Name != "" || addr.Address != s {
+			return fmt.Errorf("expected a bare address")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			return fmt.Errorf("expected RFC 3339 date-time")
+		}
+	case "uri":
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" {
+			return fmt.Errorf("missing scheme")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package jsonschema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// failures validates instance against schema and returns the instance
+// and keyword location of every error, as "instance keyword".
+func failures(t *testing.T, schema, instance string) []string {
+	t.Helper()
+	s, err := Compile([]byte(schema))
+	if err != nil {
+		t.Fatalf("Compile(%s): %v", schema, err)
+	}
+	err = s.ValidateBytes([]byte(instance))
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("validating %s: %v", instance, err)
+	}
+	var out []string
+	for _, e := range errs {
+		out = append(out, pointerOrRoot(e.InstanceLocation)+" "+e.KeywordLocation)
+	}
+	return out
+}
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string // nil if the instance is valid
+	}{
+		{"type", `{"type": "string"}`, `"x"`, nil},
+		{"type mismatch", `{"type": "string"}`, `1`, []string{"(root) /type"}},
+		{"integer is a number", `{"type": "number"}`, `3`, nil},
+		{"number is not an integer", `{"type": "integer"}`, `3.5`, []string{"(root) /type"}},
+		{"type and minimum", `{"type": "integer", "minimum": 0}`, `-1.5`, []string{"(root) /type", "(root) /minimum"}},
+		{"type and unrelated keywords", `{"type": "string", "minLength": 2, "enum": ["a", "b"]}`, `1`, []string{"(root) /type", "(root) /enum"}},
+		{"applicators after a type mismatch", `{"type": "string", "allOf": [{"maximum": 0}], "anyOf": [{"const": "a"}], "oneOf": [{"type": "string"}], "not": {"const": 1}}`, `1`, []string{"(root) /type", "(root) /allOf/0/maximum", "(root) /anyOf", "(root) /oneOf", "(root) /not"}},
+		{"type list", `{"type": ["null", "boolean"]}`, `null`, nil},
+		{"enum", `{"enum": [1, "a", null]}`, `"a"`, nil},
+		{"enum miss", `{"enum": [1, "a", null]}`, `2`, []string{"(root) /enum"}},
+		{"const", `{"const": {"a": [1]}}`, `{"a": [1]}`, nil},
+		{"const miss", `{"const": {"a": [1]}}`, `{"a": [2]}`, []string{"(root) /const"}},
+		{"minimum", `{"minimum": 0}`, `-1`, []string{"(root) /minimum"}},
+		{"maximum", `{"maximum": 10}`, `10`, nil},
+		{"exclusive limits", `{"exclusiveMinimum": 0, "exclusiveMaximum": 10}`, `10`, []string{"(root) /exclusiveMaximum"}},
+		{"multipleOf", `{"multipleOf": 0.1}`, `0.3`, nil},
+		{"multipleOf miss", `{"multipleOf": 2}`, `3`, []string{"(root) /multipleOf"}},
+		{"length counts runes", `{"maxLength": 4}`, `"سلام"`, nil},
+		{"minLength", `{"minLength": 2}`, `"a"`, []string{"(root) /minLength"}},
+		{"pattern", `{"pattern": "^[A-Z]"}`, `"alice"`, []string{"(root) /pattern"}},
+		{"items", `{"items": {"type": "integer"}}`, `[1, "2", 3, "4"]`, []string{"/1 /items/type", "/3 /items/type"}},
+		{"prefixItems", `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}`, `["a", 1, "b"]`, []string{"/2 /items/type"}},
+		{"item counts", `{"minItems": 2, "maxItems": 3}`, `[1]`, []string{"(root) /minItems"}},
+		{"required", `{"required": ["a", "b"]}`, `{"a": 1}`, []string{"(root) /required"}},
+		{"properties", `{"properties": {"a/b": {"type": "string"}}}`, `{"a/b": 1}`, []string{"/a~1b /properties/a~1b/type"}},
+		{"additionalProperties false", `{"properties": {"a": true}, "additionalProperties": false}`, `{"a": 1, "b": 2}`, []string{"(root) /additionalProperties"}},
+		{"additionalProperties schema", `{"additionalProperties": {"type": "string"}}`, `{"x": 1}`, []string{"/x /additionalProperties/type"}},
+		{"allOf reports each", `{"allOf": [{"minimum": 5}, {"multipleOf": 2}]}`, `3`, []string{"(root) /allOf/0/minimum", "(root) /allOf/1/multipleOf"}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `6`, nil},
+		{"anyOf miss", `{"anyOf": [{"type": "string"}, {"minimum": 5}]}`, `4`, []string{"(root) /anyOf"}},
+		{"oneOf matching both", `{"oneOf": [{"minimum": 5}, {"maximum": 10}]}`, `7`, []string{"(root) /oneOf"}},
+		{"oneOf", `{"oneOf": [{"minimum": 5}, {"maximum": 10}]}`, `11`, nil},
+		{"not", `{"not": {"type": "null"}}`, `null`, []string{"(root) /not"}},
+		{"false schema", `false`, `1`, []string{"(root) "}},
+		{"unknown keywords are ignored", `{"title": "x", "x-custom": 1}`, `1`, nil},
+		{"unknown formats are accepted", `{"format": "ipv9"}`, `"anything"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failures(t, tt.schema, tt.instance); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format, value string
+		ok            bool
+	}{
+		{"email", "alice@example.com", true},
+		{"email", "Alice <alice@example.com>", false},
+		{"email", "alice", false},
+		{"date-time", "2024-05-01T10:00:00Z", true},
+		{"date-time", "2024-05-01T10:00:00.123+03:30", true},
+		{"date-time", "2024-05-01", false},
+		{"uri", "https://go.dev/doc", true},
+		{"uri", "go.dev/doc", false},
+	}
+	for _, tt := range tests {
+		schema := `{"format": "` + tt.format + `"}`
+		got := failures(t, schema, `"`+tt.value+`"`)
+		if ok := got == nil; ok != tt.ok {
+			t.Errorf("%s %q: valid = %v, want %v (%q)", tt.format, tt.value, ok, tt.ok, got)
+		}
+	}
+}
+
+func TestRef(t *testing.T) {
+	// A tree whose children are trees, through a $ref back to the root.
+	const tree = `{
+		"type": "object",
+		"required": ["value"],
+		"properties": {
+			"value": {"type": "integer"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		}
+	}`
+	if got := failures(t, tree, `{"value": 1, "children": [{"value": 2, "children": [{"value": 3}]}]}`); got != nil {
+		t.Errorf("valid tree: %q", got)
+	}
+	got := failures(t, tree, `{"value": 1, "children": [{"value": 2, "children": [{"value": "3"}]}]}`)
+	want := []string{"/children/0/children/0/value /properties/value/type"}
+	if !slices.Equal(got, want) {
+		t.Errorf("bad leaf: got %q, want %q", got, want)
+	}
+
+	// A reference to itself never reaches a keyword, so it must stop at
+	// the depth limit instead of recursing forever.
+	got = failures(t, `{"$defs": {"loop": {"$ref": "#/$defs/loop"}}, "$ref": "#/$defs/loop"}`, `1`)
+	if len(got) != 1 || got[0] != "(root) /$defs/loop/$ref" {
+		t.Errorf("reference cycle: got %q", got)
+	}
+
+	// Two definitions that refer to each other.
+	const mutual = `{
+		"$defs": {
+			"a": {"type": "array", "items": {"$ref": "#/$defs/b"}},
+			"b": {"type": "array", "items": {"$ref": "#/$defs/a"}}
+		},
+		"$ref": "#/$defs/a"
+	}`
+	if got := failures(t, mutual, `[[[], [[]]]]`); got != nil {
+		t.Errorf("mutual references: %q", got)
+	}
+	if got := failures(t, mutual, `[[1]]`); !slices.Equal(got, []string{"/0/0 /$defs/a/type"}) {
+		t.Errorf("mutual references: got %q", got)
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		loc    string
+	}{
+		{`1`, ""},
+		{`{"type": "text"}`, "/type"},
+		{`{"required": ["a", 1]}`, "/required/1"},
+		{`{"minLength": -1}`, "/minLength"},
+		{`{"multipleOf": 0}`, "/multipleOf"},
+		{`{"pattern": "("}`, "/pattern"},
+		{`{"anyOf": []}`, "/anyOf"},
+		{`{"properties": {"a": {"items": 3}}}`, "/properties/a/items"},
+		{`{"$ref": "#/$defs/missing"}`, "/$ref"},
+		{`{"$ref": "other.json"}`, "/$ref"},
+	}
+	for _, tt := range tests {
+		_, err := Compile([]byte(tt.schema))
+		var se *SchemaError
+		if !errors.As(err, &se) {
+			t.Errorf("Compile(%s): got %v, want a *SchemaError", tt.schema, err)
+			continue
+		}
+		if se.Location != tt.loc {
+			t.Errorf("Compile(%s): error at %q (%v), want %q", tt.schema, se.Location, err, tt.loc)
+		}
+	}
+}
+
+func TestStudents(t *testing.T) {
+	schema, err := os.ReadFile(filepath.Join("testdata", "students.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := os.ReadFile(filepath.Join("testdata", "students.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := failures(t, string(schema), string(valid)); got != nil {
+		t.Errorf("testdata/students.json: %q", got)
+	}
+
+	got := failures(t, string(schema), `{
+		"updated": "yesterday",
+		"students": [
+			{"name": "alice", "age": -4, "email": "not an address"},
+			{"name": "Bob", "nick": "b"}
+		]
+	}`)
+	want := []string{
+		"/updated /properties/updated/format",
+		"/students/0/name /$defs/student/properties/name/pattern",
+		"/students/0/age /$defs/student/properties/age/minimum",
+		"/students/0/email /$defs/student/properties/email/format",
+		"/students/1 /$defs/student/required",
+		"/students/1 /$defs/student/additionalProperties",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
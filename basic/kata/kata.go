@@ -0,0 +1,212 @@
+// Package kata turns a solution file into an exercise. The bodies of the
+// chosen functions are replaced with panic("TODO"), keeping signatures and
+// doc comments, and a hidden test file checks a student's version against
+// a copy of the original solution on random inputs.
+//
+// A function is chosen by a //kata:todo line in its doc comment or by
+// naming it in Options.Funcs.
+package kata
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Directive marks a function to stub when it appears in its doc comment.
+const Directive = "//kata:todo"
+
+// Options configures Generate.
+type Options struct {
+	// Funcs names functions to stub in addition to the marked ones.
+	Funcs []string
+}
+
+// Kata is a generated exercise.
+type Kata struct {
+	// Filename is the base name of the solution file, e.g. "main.go".
+	Filename string
+	// Stub is the student version of the file.
+	Stub []byte
+	// Test is the hidden test file. It belongs in the same package, next
+	// to the student's file, under TestFilename.
+	Test []byte
+	// Funcs lists the stubbed functions in source order.
+	Funcs []string
+	// Untested explains, per function, why no test could be generated.
+	Untested map[string]string
+}
+
+// TestFilename returns the name of the hidden test file, e.g.
+// "main_kata_test.go".
+func (k *Kata) TestFilename() string {
+	return strings.TrimSuffix(k.Filename, ".go") + "_kata_test.go"
+}
+
+// Generate builds the exercise for the solution file src.
+func Generate(filename string, src []byte, opts Options) (*Kata, error) {
+	k := &Kata{Filename: filepath.Base(filename), Untested: map[string]string{}}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	wanted := map[string]bool{}
+	for _, name := range opts.Funcs {
+		wanted[name] = true
+	}
+	var targets []*ast.FuncDecl
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		if fn.Recv == nil && wanted[fn.Name.Name] || marked(fn) {
+			delete(wanted, fn.Name.Name)
+			targets = append(targets, fn)
+		}
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for name := range wanted {
+			missing = append(missing, name)
+		}
+		slices.Sort(missing)
+		return nil, fmt.Errorf("kata: %s: no function named %s", filename, strings.Join(missing, ", "))
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("kata: %s: no functions to stub; mark them with %s or name them", filename, Directive)
+	}
+
+	// The test is generated first: it copies the solution bodies that
+	// stubbing is about to throw away.
+	if k.Test, err = generateTest(fset, file, targets, k.Untested); err != nil {
+		return nil, err
+	}
+	for _, fn := range targets {
+		k.Funcs = append(k.Funcs, fn.Name.Name)
+		stub(file, fn)
+	}
+	removeUnusedImports(file)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	k.Stub = buf.Bytes()
+	return k, nil
+}
+
+func marked(fn *ast.FuncDecl) bool {
+	if fn.Doc == nil {
+		return false
+	}
+	for _, c := range fn.Doc.List {
+		if strings.TrimSpace(c.Text) == Directive {
+			return true
+		}
+	}
+	return false
+}
+
+// stub replaces the body of fn with panic("TODO"). The comments inside the
+// old body are dropped, and so is the directive: students see the rest of
+// the doc comment unchanged.
+func stub(file *ast.File, fn *ast.FuncDecl) {
+	body := fn.Body
+	file.Comments = slices.DeleteFunc(file.Comments, func(cg *ast.CommentGroup) bool {
+		return cg.Pos() > body.Lbrace && cg.End() < body.Rbrace
+	})
+	if fn.Doc != nil {
+		fn.Doc.List = slices.DeleteFunc(fn.Doc.List, func(c *ast.Comment) bool {
+			return strings.TrimSpace(c.Text) == Directive
+		})
+		if len(fn.Doc.List) == 0 {
+			file.Comments = slices.DeleteFunc(file.Comments, func(cg *ast.CommentGroup) bool { return cg == fn.Doc })
+			fn.Doc = nil
+		}
+	}
+	fn.Body = &ast.BlockStmt{
+		Lbrace: body.Lbrace,
+		List: []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+			Fun:    ast.NewIdent("panic"),
+			Lparen: body.Lbrace + 1,
+			Args:   []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: `"TODO"`}},
+		}}},
+		Rbrace: body.Lbrace + 2,
+	}
+}
+
+// usedPackages returns the names that appear as X in a selector X.Sel,
+// which covers every use of an imported package.
+func usedPackages(nodes ...ast.Node) map[string]bool {
+	used := map[string]bool{}
+	for _, n := range nodes {
+		ast.Inspect(n, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	return used
+}
+
+// importName returns the name an import is referred to by.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	path, _ := strconv.Unquote(spec.Path.Value)
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// removeUnusedImports drops the imports only the stubbed bodies used, so
+// the student version still compiles.
+func removeUnusedImports(file *ast.File) {
+	used := usedPackages(file)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		gen.Specs = slices.DeleteFunc(gen.Specs, func(s ast.Spec) bool {
+			name := importName(s.(*ast.ImportSpec))
+			return name != "_" && name != "." && !used[name]
+		})
+	}
+	file.Decls = slices.DeleteFunc(file.Decls, func(d ast.Decl) bool {
+		gen, ok := d.(*ast.GenDecl)
+		return ok && gen.Tok == token.IMPORT && len(gen.Specs) == 0
+	})
+	file.Imports = slices.DeleteFunc(file.Imports, func(s *ast.ImportSpec) bool {
+		name := importName(s)
+		return name != "_" && name != "." && !used[name]
+	})
+}
+
+// solutionName is the name of the solution copy in the hidden test, e.g.
+// kataSolutionSum for sum.
+func solutionName(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return "kataSolution" + string(unicode.ToUpper(r)) + name[size:]
+}
+
+func nodeString(fset *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, n)
+	return buf.String()
+}
@@ -0,0 +1,59 @@
+package kata
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func generate(t *testing.T) *Kata {
+	t.Helper()
+	path := filepath.Join("testdata", "lesson", "lesson.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := Generate(path, src, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestGenerate(t *testing.T) {
+	k := generate(t)
+	if want := []string{"sum", "passbyreference", "passbyvalue", "double"}; !slices.Equal(k.Funcs, want) {
+		t.Errorf("Funcs = %q, want %q", k.Funcs, want)
+	}
+	if _, ok := k.Untested["passbyvalue"]; !ok || len(k.Untested) != 1 {
+		t.Errorf("Untested = %q, want only passbyvalue", k.Untested)
+	}
+	if n := bytes.Count(k.Stub, []byte(`panic("TODO")`)); n != 4 {
+		t.Errorf("stub has %d panics, want 4:\n%s", n, k.Stub)
+	}
+	if k.TestFilename() != "lesson_kata_test.go" {
+		t.Errorf("TestFilename = %q", k.TestFilename())
+	}
+	// The hidden tests run on the series' own quick package.
+	for _, want := range []string{strconv.Quote(quickPath), "func TestKataSum(", "func TestKataDouble(", "func kataSolutionDouble("} {
+		if !bytes.Contains(k.Test, []byte(want)) {
+			t.Errorf("hidden test lacks %s:\n%s", want, k.Test)
+		}
+	}
+	if bytes.Contains(k.Test, []byte(`"testing/quick"`)) {
+		t.Errorf("hidden test imports testing/quick:\n%s", k.Test)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test twice")
+	}
+	if err := Verify(context.Background(), filepath.Join("testdata", "lesson"), generate(t)); err != nil {
+		t.Fatal(err)
+	}
+}
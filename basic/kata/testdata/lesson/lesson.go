@@ -0,0 +1,27 @@
+package lesson
+
+import "fmt"
+
+//kata:todo
+func sum(a int, b int) int {
+	return a + b
+}
+
+//kata:todo
+func passbyreference(x *int) {
+	*x = *x + 10
+}
+
+//kata:todo
+func passbyvalue(x int) {
+	x = x + 10
+	fmt.Println("Inside passbyvalue, number:", x)
+}
+
+//kata:todo
+func double(xs []float64, scale ...int8) map[string]bool {
+	for i := range xs {
+		xs[i] *= 2
+	}
+	return map[string]bool{"scaled": len(scale) > 0}
+}
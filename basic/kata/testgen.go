@@ -0,0 +1,346 @@
+package kata
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// param is one parameter of a stubbed function as the generated property
+// sees it.
+type param struct {
+	name string
+	typ  string // type of the random input
+	gen  string // quick generator for the input
+	kind paramKind
+}
+
+type paramKind int
+
+const (
+	plain    paramKind = iota
+	pointer            // *T: the input is a T and both calls get its address
+	slice              // []T or ...T: the solution gets a clone
+	mapping            // map[K]V: the solution gets a clone
+	variadic           // ...T: a slice passed with ...
+)
+
+// quickPath is the import path of the property-testing package the hidden
+// tests use, so exercises must live in a module that can import it.
+const quickPath = "github.com/amiiralihassanpour/golang_learning/quick"
+
+// generateTest writes the hidden test file. Every function with something
+// to compare gets a Test function that runs the quick package over the
+// student's version and a copy of the solution: the results have to match,
+// and so do the values behind pointer, slice and map parameters afterwards.
+func generateTest(fset *token.FileSet, file *ast.File, targets []*ast.FuncDecl, untested map[string]string) ([]byte, error) {
+	var decls, tests bytes.Buffer
+	var copies []ast.Node
+	imports := map[string]bool{"reflect": true, "testing": true, quickPath: true}
+	for _, fn := range targets {
+		name := fn.Name.Name
+		params, why := testParams(fset, fn)
+		if why == "" && fn.Recv != nil {
+			why = "methods are not supported"
+		}
+		if why != "" {
+			untested[name] = why
+			continue
+		}
+		for _, p := range params {
+			switch p.kind {
+			case slice, variadic:
+				imports["slices"] = true
+			case mapping:
+				imports["maps"] = true
+			}
+		}
+
+		// The solution copy calls itself, not the student's version.
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				ast.Inspect(sel.X, func(n ast.Node) bool {
+					if id, ok := n.(*ast.Ident); ok && id.Name == name {
+						id.Name = solutionName(name)
+					}
+					return true
+				})
+				return false
+			}
+			if id, ok := n.(*ast.Ident); ok && id.Name == name {
+				id.Name = solutionName(name)
+			}
+			return true
+		})
+		solution := &ast.FuncDecl{Name: ast.NewIdent(solutionName(name)), Type: fn.Type, Body: fn.Body}
+		copies = append(copies, solution)
+		fmt.Fprintf(&decls, "\n%s\n", nodeString(fset, solution))
+		writeTest(&tests, name, params, resultCount(fn))
+	}
+	if len(copies) == 0 {
+		return nil, nil
+	}
+
+	used := usedPackages(copies...)
+	for _, spec := range file.Imports {
+		if used[importName(spec)] {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if spec.Name != nil {
+				// Renamed imports are stored as they are written.
+				path = spec.Name.Name + " " + spec.Path.Value
+			}
+			imports[path] = true
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by basic kata gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "// These tests compare %s against the original solution.\n", strings.Join(funcNames(targets, untested), ", "))
+	fmt.Fprintf(&buf, "package %s\n\nimport (\n", file.Name.Name)
+	// The standard library comes first, then a group for everything else.
+	var std, other []string
+	for path := range imports {
+		line := strconv.Quote(path)
+		if _, quoted, ok := strings.Cut(path, " "); ok {
+			line = path
+			path, _ = strconv.Unquote(quoted)
+		}
+		if isStd(path) {
+			std = append(std, line)
+		} else {
+			other = append(other, line)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	if len(other) > 0 {
+		std = append(std, "")
+	}
+	for _, path := range append(std, other...) {
+		fmt.Fprintf(&buf, "\t%s\n", path)
+	}
+	buf.WriteString(")\n")
+	buf.Write(tests.Bytes())
+	buf.WriteString("\n// The original solutions.\n")
+	buf.Write(decls.Bytes())
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("kata: generated test does not parse: %w", err)
+	}
+	return out, nil
+}
+
+// isStd reports whether path belongs to the standard library, whose
+// import paths have no dot in the first element.
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func funcNames(targets []*ast.FuncDecl, untested map[string]string) []string {
+	var names []string
+	for _, fn := range targets {
+		if _, ok := untested[fn.Name.Name]; !ok {
+			names = append(names, fn.Name.Name)
+		}
+	}
+	return names
+}
+
+func resultCount(fn *ast.FuncDecl) int {
+	if fn.Type.Results == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range fn.Type.Results.List {
+		n += max(len(f.Names), 1)
+	}
+	return n
+}
+
+// testParams describes the parameters of fn, or says why the quick package
+// cannot generate them.
+func testParams(fset *token.FileSet, fn *ast.FuncDecl) ([]param, string) {
+	if fn.Type.TypeParams != nil {
+		return nil, "generic functions are not supported"
+	}
+	var params []param
+	effects := resultCount(fn) > 0
+	for _, f := range fn.Type.Params.List {
+		p := param{kind: plain}
+		typ := f.Type
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			p.kind, typ = pointer, t.X
+		case *ast.Ellipsis:
+			p.kind, typ = variadic, &ast.ArrayType{Elt: t.Elt}
+		case *ast.ArrayType:
+			if t.Len == nil {
+				p.kind = slice
+			}
+		case *ast.MapType:
+			p.kind = mapping
+		}
+		if !generatable(typ) {
+			return nil, fmt.Sprintf("cannot generate random values of type %s", nodeString(fset, f.Type))
+		}
+		if p.kind != plain {
+			effects = true
+		}
+		p.typ = nodeString(fset, typ)
+		p.gen = genExpr(fset, typ)
+		names := f.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("_")}
+		}
+		for _, id := range names {
+			p.name = id.Name
+			if p.name == "_" {
+				p.name = fmt.Sprintf("p%d", len(params))
+			}
+			params = append(params, p)
+		}
+	}
+	if !effects {
+		return nil, "it returns nothing and has no pointer, slice or map parameters to compare"
+	}
+	return params, ""
+}
+
+// basicTypes are the predeclared types genExpr can generate.
+var basicTypes = map[string]bool{
+	"bool": true, "string": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+func generatable(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return basicTypes[t.Name]
+	case *ast.ArrayType:
+		return generatable(t.Elt)
+	case *ast.MapType:
+		return generatable(t.Key) && generatable(t.Value)
+	}
+	return false
+}
+
+// genExpr returns a quick generator for typ, which generatable accepted.
+// Numbers without a generator of their own are converted from quick.Int.
+func genExpr(fset *token.FileSet, typ ast.Expr) string {
+	name := nodeString(fset, typ)
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "bool":
+			return "quick.Bool()"
+		case "int":
+			return "quick.Int()"
+		case "rune":
+			return "quick.Rune()"
+		case "string":
+			return "quick.String()"
+		case "complex64", "complex128":
+			return fmt.Sprintf("quick.Map(quick.Zip(quick.Int(), quick.Int()), func(p quick.Pair[int, int]) %s { return %s(complex(float64(p.A), float64(p.B))) })", name, name)
+		}
+		return fmt.Sprintf("quick.Map(quick.Int(), func(n int) %s { return %s(n) })", name, name)
+	case *ast.ArrayType:
+		elems := fmt.Sprintf("quick.SliceOf(%s)", genExpr(fset, t.Elt))
+		if t.Len == nil {
+			return elems
+		}
+		return fmt.Sprintf("quick.Map(%s, func(s []%s) (a %s) { copy(a[:], s); return a })", elems, nodeString(fset, t.Elt), name)
+	case *ast.MapType:
+		return fmt.Sprintf("quick.MapOf(%s, %s)", genExpr(fset, t.Key), genExpr(fset, t.Value))
+	}
+	panic("kata: no generator for " + name)
+}
+
+// inputGen combines the generators of params into one, nesting quick.Zip
+// as needed, and returns it with its type and the expression that picks
+// each parameter out of a value named in.
+func inputGen(params []param, in string) (gen, typ string, fields []string) {
+	switch len(params) {
+	case 0:
+		return "quick.Const(struct{}{})", "struct{}", nil
+	case 1:
+		return params[0].gen, params[0].typ, []string{in}
+	}
+	gen, typ, rest := inputGen(params[1:], in+".B")
+	gen = fmt.Sprintf("quick.Zip(%s, %s)", params[0].gen, gen)
+	typ = fmt.Sprintf("quick.Pair[%s, %s]", params[0].typ, typ)
+	return gen, typ, append([]string{in + ".A"}, rest...)
+}
+
+// writeTest writes the Test function for one stubbed function.
+func writeTest(w *bytes.Buffer, name string, params []param, results int) {
+	const in = "kataInput"
+	gen, typ, fields := inputGen(params, in)
+	var names, studentArgs, solutionArgs, got, want []string
+	var setup []string
+	for _, p := range params {
+		names = append(names, p.name)
+		copyName := p.name + "Solution"
+		switch p.kind {
+		case plain:
+			studentArgs = append(studentArgs, p.name)
+			solutionArgs = append(solutionArgs, p.name)
+			continue
+		case pointer:
+			setup = append(setup, fmt.Sprintf("%s := %s", copyName, p.name))
+			studentArgs = append(studentArgs, "&"+p.name)
+			solutionArgs = append(solutionArgs, "&"+copyName)
+		case slice, variadic:
+			setup = append(setup, fmt.Sprintf("%s := slices.Clone(%s)", copyName, p.name))
+			studentArgs = append(studentArgs, p.name)
+			solutionArgs = append(solutionArgs, copyName)
+			if p.kind == variadic {
+				studentArgs[len(studentArgs)-1] += "..."
+				solutionArgs[len(solutionArgs)-1] += "..."
+			}
+		case mapping:
+			setup = append(setup, fmt.Sprintf("%s := maps.Clone(%s)", copyName, p.name))
+			studentArgs = append(studentArgs, p.name)
+			solutionArgs = append(solutionArgs, copyName)
+		}
+		got = append(got, p.name)
+		want = append(want, copyName)
+	}
+	var gotResults, wantResults []string
+	for i := range results {
+		gotResults = append(gotResults, fmt.Sprintf("got%d", i))
+		wantResults = append(wantResults, fmt.Sprintf("want%d", i))
+	}
+	call := func(results []string, fn string, args []string) string {
+		c := fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", "))
+		if len(results) == 0 {
+			return c
+		}
+		return strings.Join(results, ", ") + " := " + c
+	}
+
+	// quick counts a panic as a failure and reports it with the shrunk
+	// input, so the property itself does not recover.
+	fmt.Fprintf(w, "\nfunc TestKata%s(t *testing.T) {\n", solutionName(name)[len("kataSolution"):])
+	fmt.Fprintf(w, "\tcheck := func(%s %s) bool {\n", in, typ)
+	if len(names) > 0 {
+		fmt.Fprintf(w, "\t\t%s := %s\n", strings.Join(names, ", "), strings.Join(fields, ", "))
+	}
+	for _, s := range setup {
+		fmt.Fprintf(w, "\t\t%s\n", s)
+	}
+	fmt.Fprintf(w, "\t\t%s\n", call(wantResults, solutionName(name), solutionArgs))
+	fmt.Fprintf(w, "\t\t%s\n", call(gotResults, name, studentArgs))
+	fmt.Fprintf(w, "\t\treturn reflect.DeepEqual([]any{%s}, []any{%s})\n",
+		strings.Join(append(gotResults, got...), ", "), strings.Join(append(wantResults, want...), ", "))
+	fmt.Fprintf(w, "\t}\n\tgen := %s\n", gen)
+	fmt.Fprintf(w, "\tif f := quick.Check(gen, check, nil); f != nil {\n")
+	fmt.Fprintf(w, "\t\tt.Errorf(\"%s does not match the solution: %%v\", f)\n\t}\n}\n", name)
+}
@@ -0,0 +1,76 @@
+package kata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Verify checks the exercise in the package directory dir, where the
+// solution file lives: the hidden tests must pass against the solution and
+// fail against the stub. The package is never modified; go test reads the
+// stub and the test file through an -overlay.
+func Verify(ctx context.Context, dir string, k *Kata) error {
+	if k.Test == nil {
+		return errors.New("kata: no tests were generated, so there is nothing to verify")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp("", "kata-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	testPath := filepath.Join(tmp, k.TestFilename())
+	stubPath := filepath.Join(tmp, k.Filename)
+	if err := os.WriteFile(testPath, k.Test, 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(stubPath, k.Stub, 0o644); err != nil {
+		return err
+	}
+	overlay := map[string]string{filepath.Join(dir, k.TestFilename()): testPath}
+
+	out, err := overlayTest(ctx, dir, tmp, "solution", overlay)
+	if err != nil {
+		return fmt.Errorf("kata: the tests fail against the solution:\n%s", out)
+	}
+	overlay[filepath.Join(dir, k.Filename)] = stubPath
+	out, err = overlayTest(ctx, dir, tmp, "stub", overlay)
+	switch {
+	case err == nil:
+		return errors.New("kata: the tests pass against the stub, so they check nothing")
+	case strings.Contains(out, "[build failed]") || strings.Contains(out, "[setup failed]"):
+		return fmt.Errorf("kata: the stub does not compile:\n%s", out)
+	}
+	return nil
+}
+
+// overlayTest runs the kata tests of the package in dir with the given
+// files replaced.
+func overlayTest(ctx context.Context, dir, tmp, name string, replace map[string]string) (string, error) {
+	data, err := json.Marshal(map[string]any{"Replace": replace})
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(tmp, name+".overlay.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	cmd := exec.CommandContext(ctx, "go", "test", "-count=1", "-overlay", path, "-run", "^TestKata", ".")
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	return out.String(), err
+}
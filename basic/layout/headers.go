@@ -0,0 +1,146 @@
+package layout
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// SliceHeader is what a slice value holds: a pointer to the first element
+// of a backing array, the length and the capacity. Copying a slice copies
+// these three words, not the elements.
+type SliceHeader struct {
+	Data     uintptr
+	Len, Cap int
+	// ElemSize is the size of one element.
+	ElemSize uintptr
+	// Elems holds the elements up to the capacity, formatted with %v;
+	// the ones past the length are reachable only by reslicing.
+	Elems []string
+}
+
+// Slice returns the header of s.
+func Slice[T any](s []T) SliceHeader {
+	h := SliceHeader{
+		Data:     uintptr(unsafe.Pointer(unsafe.SliceData(s))),
+		Len:      len(s),
+		Cap:      cap(s),
+		ElemSize: unsafe.Sizeof(*new(T)),
+	}
+	for _, v := range s[:cap(s)] {
+		h.Elems = append(h.Elems, fmt.Sprint(v))
+	}
+	return h
+}
+
+// WriteDiagram draws the header and the backing array it points to.
+func (h SliceHeader) WriteDiagram(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s: slice header, %d bytes: pointer, len, cap\n", name, 3*unsafe.Sizeof(uintptr(0)))
+	cells := []string{fmt.Sprintf("data %#x", h.Data), fmt.Sprintf("len %d", h.Len), fmt.Sprintf("cap %d", h.Cap)}
+	writeBoxes(w, "  ", cells)
+	fmt.Fprintf(w, "     |\n     v  backing array, %d bytes per element\n", h.ElemSize)
+	elems := make([]string, len(h.Elems))
+	for i, e := range h.Elems {
+		if i >= h.Len {
+			e = "(" + e + ")"
+		}
+		elems[i] = fmt.Sprintf("[%d] %s", i, e)
+	}
+	if len(elems) == 0 {
+		fmt.Fprintln(w, "    (no elements)")
+		return
+	}
+	writeBoxes(w, "    ", elems)
+	if h.Cap > h.Len {
+		fmt.Fprintf(w, "    elements in parentheses are past len: append can use them without allocating\n")
+	}
+}
+
+// StringHeader is what a string value holds: a pointer to the bytes and
+// their count. The bytes are immutable, so copies share them safely.
+type StringHeader struct {
+	Data uintptr
+	Len  int
+}
+
+// String returns the header of s.
+func String(s string) StringHeader {
+	return StringHeader{Data: uintptr(unsafe.Pointer(unsafe.StringData(s))), Len: len(s)}
+}
+
+// WriteDiagram draws the header and the bytes it points to.
+func (h StringHeader) WriteDiagram(w io.Writer, name, s string) {
+	fmt.Fprintf(w, "%s: string header, %d bytes: pointer, len\n", name, 2*unsafe.Sizeof(uintptr(0)))
+	writeBoxes(w, "  ", []string{fmt.Sprintf("data %#x", h.Data), fmt.Sprintf("len %d", h.Len)})
+	fmt.Fprintln(w, "     |\n     v  bytes (read-only)")
+	cells := make([]string, len(s))
+	for i := range len(s) {
+		if c := s[i]; c >= 0x20 && c < 0x7f {
+			cells[i] = string(c)
+		} else {
+			cells[i] = fmt.Sprintf("%02x", c)
+		}
+	}
+	if len(cells) > 0 {
+		writeBoxes(w, "    ", cells)
+	}
+}
+
+// WriteMap describes a map value: a single pointer to a table owned by
+// the runtime. That is why a map passed to a function or assigned to
+// another variable is the same map, and why a nil map has nowhere to
+// write to.
+func WriteMap[K comparable, V any](w io.Writer, name string, m map[K]V) {
+	p := *(*uintptr)(unsafe.Pointer(&m))
+	fmt.Fprintf(w, "%s: map, %d bytes: one pointer to a runtime table\n", name, unsafe.Sizeof(m))
+	writeBoxes(w, "  ", []string{fmt.Sprintf("table %#x", p)})
+	fmt.Fprintf(w, "  %d entries live in the table; its layout is private to the runtime\n", len(m))
+}
+
+// WriteArray lists where every element of the array v lives, relative to
+// its start. Nested arrays such as [2][3]int are shown row by row: they
+// are one block of memory, the rows one after another.
+func WriteArray(w io.Writer, name string, v any) error {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Array {
+		return fmt.Errorf("layout: %T is not an array", v)
+	}
+	fmt.Fprintf(w, "%s: %s, %d bytes in one block\n", name, t, t.Size())
+	writeArray(w, reflect.ValueOf(v), name, 0)
+	return nil
+}
+
+func writeArray(w io.Writer, v reflect.Value, prefix string, base uintptr) {
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Array {
+		for i := range v.Len() {
+			writeArray(w, v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), base+uintptr(i)*elem.Size())
+		}
+		return
+	}
+	cells := make([]string, v.Len())
+	for i := range v.Len() {
+		cells[i] = fmt.Sprintf("+%d: %v", base+uintptr(i)*elem.Size(), v.Index(i))
+	}
+	fmt.Fprintf(w, "  %s\n", prefix)
+	writeBoxes(w, "    ", cells)
+}
+
+// writeBoxes draws cells side by side in a row of boxes.
+func writeBoxes(w io.Writer, indent string, cells []string) {
+	var top, mid, bottom strings.Builder
+	for i, c := range cells {
+		bar := strings.Repeat("-", len(c)+2)
+		if i == 0 {
+			top.WriteString("+")
+			mid.WriteString("|")
+			bottom.WriteString("+")
+		}
+		top.WriteString(bar + "+")
+		mid.WriteString(" " + c + " |")
+		bottom.WriteString(bar + "+")
+	}
+	fmt.Fprintf(w, "%s%s\n%s%s\n%s%s\n", indent, top.String(), indent, mid.String(), indent, bottom.String())
+}
@@ -0,0 +1,199 @@
+// Package layout shows how Go values are laid out in memory: the size,
+// alignment and offset of struct fields with the padding the compiler
+// inserts between them, and the headers behind slices, strings and maps.
+//
+// Sizes come from reflect, which reports exactly what unsafe.Sizeof,
+// unsafe.Alignof and unsafe.Offsetof would for the same type; reflect is
+// needed because the unsafe functions only take expressions whose type is
+// known at compile time.
+package layout
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Field is one struct field and where it lives.
+type Field struct {
+	Name   string
+	Type   string
+	Offset uintptr
+	Size   uintptr
+	Align  uintptr
+}
+
+// Hole is padding the compiler inserted so the next field, or the next
+// element of an array of the struct, is aligned.
+type Hole struct {
+	Offset uintptr
+	Size   uintptr
+	// After is the field the padding follows.
+	After string
+}
+
+// Struct is the layout of a struct type.
+type Struct struct {
+	Name   string
+	Size   uintptr
+	Align  uintptr
+	Fields []Field
+	Holes  []Hole
+}
+
+// Of returns the layout of the struct type t.
+func Of(t reflect.Type) (*Struct, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("layout: %s is not a struct", t)
+	}
+	s := &Struct{Name: t.String(), Size: t.Size(), Align: uintptr(t.Align())}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		s.Fields = append(s.Fields, Field{
+			Name:   f.Name,
+			Type:   f.Type.String(),
+			Offset: f.Offset,
+			Size:   f.Type.Size(),
+			Align:  uintptr(f.Type.Align()),
+		})
+	}
+	s.findHoles()
+	return s, nil
+}
+
+// OfValue returns the layout of the type of v, which must be a struct.
+func OfValue(v any) (*Struct, error) {
+	return Of(reflect.TypeOf(v))
+}
+
+func (s *Struct) findHoles() {
+	s.Holes = nil
+	for i, f := range s.Fields {
+		end := s.Size
+		if i+1 < len(s.Fields) {
+			end = s.Fields[i+1].Offset
+		}
+		if gap := end - (f.Offset + f.Size); gap > 0 {
+			s.Holes = append(s.Holes, Hole{Offset: f.Offset + f.Size, Size: gap, After: f.Name})
+		}
+	}
+}
+
+// Padding returns the number of bytes lost to padding.
+func (s *Struct) Padding() uintptr {
+	var n uintptr
+	for _, h := range s.Holes {
+		n += h.Size
+	}
+	return n
+}
+
+// Reordered returns the layout the struct would have with its fields
+// sorted by decreasing alignment, which never needs padding between
+// fields. This is the smallest layout the fields allow, though
+// grouping fields by meaning can matter more than a few bytes.
+func (s *Struct) Reordered() *Struct {
+	fields := slices.Clone(s.Fields)
+	slices.SortStableFunc(fields, func(a, b Field) int {
+		if c := cmp.Compare(b.Align, a.Align); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Size, a.Size)
+	})
+	return Plan(s.Name, fields)
+}
+
+// Plan lays out fields in the given order the way the compiler does:
+// every field starts at a multiple of its alignment, and the size is
+// rounded up to the largest alignment so arrays of the struct stay
+// aligned. The offsets of fields are ignored and recomputed.
+func Plan(name string, fields []Field) *Struct {
+	s := &Struct{Name: name, Align: 1}
+	var off uintptr
+	for _, f := range fields {
+		off = alignUp(off, f.Align)
+		f.Offset = off
+		off += f.Size
+		s.Align = max(s.Align, f.Align)
+		s.Fields = append(s.Fields, f)
+	}
+	// A zero-size final field gets a byte of its own, so a pointer to it
+	// cannot point past the struct into the next object.
+	if n := len(fields); n > 0 && fields[n-1].Size == 0 && off > 0 {
+		off++
+	}
+	s.Size = alignUp(off, s.Align)
+	s.findHoles()
+	return s
+}
+
+func alignUp(n, align uintptr) uintptr {
+	if align == 0 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
+
+// WriteTable writes the fields and holes of s as a table.
+func (s *Struct) WriteTable(w io.Writer) {
+	fmt.Fprintf(w, "%s: size %d, align %d", s.Name, s.Size, s.Align)
+	switch p := s.Padding(); p {
+	case 0:
+	case 1:
+		fmt.Fprint(w, ", 1 byte of padding")
+	default:
+		fmt.Fprintf(w, ", %d bytes of padding", p)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "  %6s  %-16s %-20s %5s %5s\n", "offset", "field", "type", "size", "align")
+	holes := slices.Clone(s.Holes)
+	for _, f := range s.Fields {
+		fmt.Fprintf(w, "  %6d  %-16s %-20s %5d %5d\n", f.Offset, f.Name, f.Type, f.Size, f.Align)
+		for len(holes) > 0 && holes[0].After == f.Name {
+			fmt.Fprintf(w, "  %6d  %-16s %-20s %5d   <-- hole\n", holes[0].Offset, "(padding)", "", holes[0].Size)
+			holes = holes[1:]
+		}
+	}
+}
+
+// WriteBytes draws s one byte per cell, word bytes per row; a word of 0
+// puts the whole struct on one row. Each field is shown by a letter (the
+// legend lists them) and padding by '.'.
+func (s *Struct) WriteBytes(w io.Writer, word uintptr) {
+	if s.Size == 0 {
+		fmt.Fprintln(w, "  (zero size)")
+		return
+	}
+	if word == 0 {
+		word = s.Size
+	}
+	cells := make([]byte, s.Size)
+	for i := range cells {
+		cells[i] = '.'
+	}
+	var legend []string
+	for i, f := range s.Fields {
+		mark := fieldMark(i)
+		for b := f.Offset; b < f.Offset+f.Size; b++ {
+			cells[b] = mark
+		}
+		legend = append(legend, fmt.Sprintf("%c=%s", mark, f.Name))
+	}
+	for row := uintptr(0); row < s.Size; row += word {
+		var b strings.Builder
+		for i := row; i < min(row+word, s.Size); i++ {
+			b.WriteByte(' ')
+			b.WriteByte(cells[i])
+		}
+		fmt.Fprintf(w, "  %4d |%s |\n", row, b.String())
+	}
+	fmt.Fprintf(w, "        %s  .=padding\n", strings.Join(legend, " "))
+}
+
+func fieldMark(i int) byte {
+	const marks = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	return marks[i%len(marks)]
+}
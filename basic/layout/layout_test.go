@@ -0,0 +1,75 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+type padded struct {
+	Active bool
+	ID     int64
+	Grade  byte
+}
+
+func TestOf(t *testing.T) {
+	s, err := OfValue(padded{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p padded
+	if s.Size != unsafe.Sizeof(p) || s.Fields[1].Offset != unsafe.Offsetof(p.ID) || s.Fields[2].Offset != unsafe.Offsetof(p.Grade) {
+		t.Errorf("layout %+v does not match unsafe", s)
+	}
+	if want := []Hole{{1, 7, "Active"}, {17, 7, "Grade"}}; !reflect.DeepEqual(s.Holes, want) {
+		t.Errorf("holes %+v, want %+v", s.Holes, want)
+	}
+	if _, err := OfValue(3); err == nil {
+		t.Error("OfValue(3) succeeded")
+	}
+}
+
+func TestReordered(t *testing.T) {
+	s, _ := OfValue(padded{})
+	r := s.Reordered()
+	if r.Size != 16 || r.Padding() != 6 || r.Fields[0].Name != "ID" {
+		t.Errorf("reordered: %+v", r)
+	}
+	// Plan matches the compiler, including the byte a zero-size final
+	// field gets.
+	type tail struct {
+		A int32
+		B struct{}
+	}
+	t2, _ := OfValue(tail{})
+	if p := Plan("tail", t2.Fields); p.Size != t2.Size {
+		t.Errorf("Plan gives size %d, the compiler %d", p.Size, t2.Size)
+	}
+}
+
+func TestWriteBytes(t *testing.T) {
+	s, _ := OfValue(padded{})
+	tests := []struct {
+		word uintptr
+		rows int
+	}{
+		{8, 3},
+		{4, 6},
+		{100, 1},
+		{0, 1},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		s.WriteBytes(&b, tt.word)
+		lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+		if rows := len(lines) - 1; rows != tt.rows {
+			t.Errorf("word %d: %d rows, want %d:\n%s", tt.word, rows, tt.rows, b.String())
+		}
+	}
+	var b strings.Builder
+	s.WriteBytes(&b, 8)
+	if want := "     0 | a . . . . . . . |\n"; !strings.HasPrefix(b.String(), want) {
+		t.Errorf("first row:\n%s\nwant\n%s", b.String(), want)
+	}
+}
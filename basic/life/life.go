@@ -0,0 +1,288 @@
+// Package life implements Conway's Game of Life, and other Life-like
+// cellular automata, on a finite grid.
+//
+// A Grid is a two-dimensional array of cells stored row by row in one
+// slice. Its edges are either dead (Bounded) or wrap around (Torus). Step
+// computes the next generation in parallel: the rows are split into bands
+// and each band is computed by its own goroutine, which is safe because
+// every goroutine reads the current generation and writes only its own
+// rows of the next.
+//
+// Patterns are read from the two common file formats, run length encoded
+// (.rle) and plaintext (.cells); a few classic ones are built in. Render
+// draws a grid on a terminal and WriteGIF records generations as an
+// animated GIF.
+package life
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Rule says which cells are alive in the next generation, given their
+// number of live neighbours: a dead cell is born if Birth[n] is set and a
+// live cell survives if Survive[n] is.
+type Rule struct {
+	Birth, Survive [9]bool
+}
+
+// Conway is the Game of Life rule, B3/S23: a cell is born with exactly
+// three live neighbours and survives with two or three.
+var Conway = MustParseRule("B3/S23")
+
+// ParseRule parses a rule in B/S notation, "B3/S23", or in the older S/B
+// notation, "23/3".
+func ParseRule(s string) (Rule, error) {
+	var r Rule
+	first, second, ok := strings.Cut(strings.ToUpper(s), "/")
+	if !ok {
+		return r, fmt.Errorf("life: rule %q has no /", s)
+	}
+	birth, survive := second, first
+	if strings.HasPrefix(first, "B") {
+		birth, survive = first, second
+	}
+	if err := parseCounts(&r.Birth, strings.TrimPrefix(birth, "B")); err != nil {
+		return r, fmt.Errorf("life: rule %q: %v", s, err)
+	}
+	if err := parseCounts(&r.Survive, strings.TrimPrefix(survive, "S")); err != nil {
+		return r, fmt.Errorf("life: rule %q: %v", s, err)
+	}
+	return r, nil
+}
+
+func parseCounts(set *[9]bool, digits string) error {
+	for _, c := range digits {
+		if c < '0' || c > '8' {
+			return fmt.Errorf("bad neighbour count %q", c)
+		}
+		set[c-'0'] = true
+	}
+	return nil
+}
+
+// MustParseRule is like ParseRule but panics on error.
+func MustParseRule(s string) Rule {
+	r, err := ParseRule(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func (r Rule) String() string {
+	var b strings.Builder
+	b.WriteString("B")
+	for n, ok := range r.Birth {
+		if ok {
+			b.WriteString(strconv.Itoa(n))
+		}
+	}
+	b.WriteString("/S")
+	for n, ok := range r.Survive {
+		if ok {
+			b.WriteString(strconv.Itoa(n))
+		}
+	}
+	return b.String()
+}
+
+// Topology is what lies beyond the edges of a grid.
+type Topology uint8
+
+const (
+	// Bounded grids have dead cells all round.
+	Bounded Topology = iota
+	// Torus grids wrap around: the cell right of the last column is in
+	// the first column, and the row below the last is the first.
+	Torus
+)
+
+// Grid is a generation of cells.
+type Grid struct {
+	W, H     int
+	Topology Topology
+	Rule     Rule
+	// Generation counts the steps taken.
+	Generation int
+	cells      []bool
+	next       []bool
+}
+
+// New returns a w by h grid of dead cells, following Conway's rule.
+func New(w, h int, t Topology) *Grid {
+	return &Grid{W: w, H: h, Topology: t, Rule: Conway, cells: make([]bool, w*h), next: make([]bool, w*h)}
+}
+
+// Alive reports whether the cell at column x, row y is alive. Outside the
+// grid, cells are dead on a bounded grid and wrap around on a torus.
+func (g *Grid) Alive(x, y int) bool {
+	if g.Topology == Torus {
+		x, y = mod(x, g.W), mod(y, g.H)
+	} else if x < 0 || x >= g.W || y < 0 || y >= g.H {
+		return false
+	}
+	return g.cells[y*g.W+x]
+}
+
+// Set makes the cell at x, y alive or dead. On a torus, x and y wrap; on
+// a bounded grid, cells outside it are ignored.
+func (g *Grid) Set(x, y int, alive bool) {
+	if g.Topology == Torus {
+		x, y = mod(x, g.W), mod(y, g.H)
+	} else if x < 0 || x >= g.W || y < 0 || y >= g.H {
+		return
+	}
+	g.cells[y*g.W+x] = alive
+}
+
+func mod(a, n int) int {
+	a %= n
+	if a < 0 {
+		a += n
+	}
+	return a
+}
+
+// Place sets the live cells of p with its top left corner at x, y.
+func (g *Grid) Place(p *Pattern, x, y int) {
+	for _, c := range p.Cells {
+		g.Set(x+c.X, y+c.Y, true)
+	}
+}
+
+// Center places p in the middle of the grid.
+func (g *Grid) Center(p *Pattern) {
+	g.Place(p, (g.W-p.W)/2, (g.H-p.H)/2)
+}
+
+// Population returns the number of live cells.
+func (g *Grid) Population() int {
+	n := 0
+	for _, c := range g.cells {
+		if c {
+			n++
+		}
+	}
+	return n
+}
+
+// Live returns the live cells, row by row.
+func (g *Grid) Live() []Point {
+	var pts []Point
+	for i, c := range g.cells {
+		if c {
+			pts = append(pts, Point{i % g.W, i / g.W})
+		}
+	}
+	return pts
+}
+
+// Clone returns a copy of g.
+func (g *Grid) Clone() *Grid {
+	c := *g
+	c.cells = append([]bool(nil), g.cells...)
+	c.next = make([]bool, len(g.next))
+	return &c
+}
+
+// Equal reports whether g and h have the same size and the same live
+// cells.
+func (g *Grid) Equal(h *Grid) bool {
+	if g.W != h.W || g.H != h.H {
+		return false
+	}
+	for i := range g.cells {
+		if g.cells[i] != h.cells[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String draws the grid in plaintext: O for a live cell, . for a dead one.
+func (g *Grid) String() string {
+	var b strings.Builder
+	for y := range g.H {
+		for x := range g.W {
+			if g.cells[y*g.W+x] {
+				b.WriteByte('O')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Step advances the grid one generation, splitting the rows into bands
+// computed by up to workers goroutines. With workers below 2 it runs on
+// the calling goroutine.
+func (g *Grid) Step(workers int) {
+	workers = min(max(workers, 1), g.H)
+	if workers == 1 {
+		g.stepRows(0, g.H)
+	} else {
+		band := (g.H + workers - 1) / workers
+		var wg sync.WaitGroup
+		for lo := 0; lo < g.H; lo += band {
+			wg.Go(func() { g.stepRows(lo, min(lo+band, g.H)) })
+		}
+		wg.Wait()
+	}
+	g.cells, g.next = g.next, g.cells
+	g.Generation++
+}
+
+// Run takes n steps.
+func (g *Grid) Run(n, workers int) {
+	for range n {
+		g.Step(workers)
+	}
+}
+
+// stepRows computes rows lo up to hi of the next generation.
+func (g *Grid) stepRows(lo, hi int) {
+	w := g.W
+	for y := lo; y < hi; y++ {
+		// The rows above and below, or nil beyond a bounded edge.
+		above, below := g.row(y-1), g.row(y+1)
+		cur := g.cells[y*w : (y+1)*w]
+		out := g.next[y*w : (y+1)*w]
+		for x := range w {
+			l, r := x-1, x+1
+			if g.Topology == Torus {
+				l, r = mod(l, w), mod(r, w)
+			}
+			n := at(above, l) + at(above, x) + at(above, r) +
+				at(cur, l) + at(cur, r) +
+				at(below, l) + at(below, x) + at(below, r)
+			if cur[x] {
+				out[x] = g.Rule.Survive[n]
+			} else {
+				out[x] = g.Rule.Birth[n]
+			}
+		}
+	}
+}
+
+func (g *Grid) row(y int) []bool {
+	if g.Topology == Torus {
+		y = mod(y, g.H)
+	} else if y < 0 || y >= g.H {
+		return nil
+	}
+	return g.cells[y*g.W : (y+1)*g.W]
+}
+
+// at returns 1 if the cell at column x of row is alive, and 0 if it is
+// dead or outside the row.
+func at(row []bool, x int) int {
+	if x >= 0 && x < len(row) && row[x] {
+		return 1
+	}
+	return 0
+}
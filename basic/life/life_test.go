@@ -0,0 +1,224 @@
+package life
+
+import (
+	"bytes"
+	"image/gif"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// grid returns a w by h grid with a built-in pattern in the middle.
+func grid(t *testing.T, pattern string, w, h int, top Topology) *Grid {
+	t.Helper()
+	p, err := Builtin(pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := New(w, h, top)
+	g.Center(p)
+	return g
+}
+
+func TestRLEAndPlaintextAgree(t *testing.T) {
+	rle, err := ParseRLE(strings.NewReader("x = 3, y = 3\nbo$2bo$3o!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := ParsePlaintext(strings.NewReader("!Name: Glider\n.O.\n..O\nOOO\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(rle.Cells, plain.Cells) || rle.W != plain.W || rle.H != plain.H {
+		t.Errorf("rle\n%s\nplaintext\n%s", rle, plain)
+	}
+}
+
+func TestRLERowSkips(t *testing.T) {
+	p, err := Builtin("pulsar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Cells) != 48 || p.W != 13 || p.H != 13 {
+		t.Errorf("got %d cells in %dx%d, want 48 in 13x13", len(p.Cells), p.W, p.H)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	for _, name := range Builtins() {
+		if _, err := Builtin(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// TestRLEBounds checks that a pattern cannot ask for more cells than its
+// header declares, or for a header too large to fit in memory.
+func TestRLEBounds(t *testing.T) {
+	for _, text := range []string{
+		"x = 3, y = 3\n99999999999o!",
+		"x = 3, y = 3\n4o!",
+		"x = 3, y = 3\n3b$o3b!",
+		"x = 3, y = 3\n3$o!",
+		"x = 3, y = 3\n$$$o!",
+		"x = 3\no!",
+		"x = -1, y = 3\n!",
+		"x = 100000, y = 100000\no!",
+	} {
+		if p, err := ParseRLE(strings.NewReader(text)); err == nil {
+			t.Errorf("%q: got a %dx%d pattern, want an error", text, p.W, p.H)
+		}
+	}
+	if _, err := ParseRLE(strings.NewReader("x = 3, y = 3\n3o$3b$3o$!")); err != nil {
+		t.Errorf("trailing row end: %v", err)
+	}
+}
+
+func TestPlaintextBounds(t *testing.T) {
+	for name, text := range map[string]string{
+		"wide": "O" + strings.Repeat(".", 60000) + "O\n",
+		"high": "O\n" + strings.Repeat(".\n", 60000) + "O\n",
+	} {
+		if p, err := ParsePlaintext(strings.NewReader(text)); err == nil {
+			t.Errorf("%s: got a %dx%d pattern, want an error", name, p.W, p.H)
+		}
+	}
+	text := "O" + strings.Repeat(".", maxSide-2) + "O\n" + strings.Repeat(".\n", maxSide-1)
+	if p, err := ParsePlaintext(strings.NewReader(text)); err != nil {
+		t.Errorf("largest pattern: %v", err)
+	} else if p.W != maxSide || p.H != maxSide {
+		t.Errorf("largest pattern is %dx%d, want %dx%[3]d", p.W, p.H, maxSide)
+	}
+}
+
+func TestRuleNotations(t *testing.T) {
+	for _, s := range []string{"B3/S23", "b3/s23", "23/3", "S23/B3"} {
+		if r, err := ParseRule(s); err != nil || r != Conway {
+			t.Errorf("%s: got %v, %v, want Conway's rule", s, r, err)
+		}
+	}
+	if r := MustParseRule("B36/S23"); r.String() != "B36/S23" {
+		t.Errorf("HighLife prints as %s", r)
+	}
+}
+
+// TestOscillators checks that each pattern returns to its starting cells
+// after its period and not before.
+func TestOscillators(t *testing.T) {
+	tests := []struct {
+		period   int
+		patterns []string
+	}{
+		{1, []string{"block", "beehive"}},
+		{2, []string{"blinker", "toad", "beacon"}},
+		{3, []string{"pulsar"}},
+		{15, []string{"pentadecathlon"}},
+	}
+	for _, tt := range tests {
+		for _, name := range tt.patterns {
+			g := grid(t, name, 30, 30, Bounded)
+			start := g.Clone()
+			for gen := 1; gen <= tt.period; gen++ {
+				g.Step(1)
+				if same := g.Equal(start); same != (gen == tt.period) {
+					t.Errorf("%s: generation %d equal to the start is %v", name, gen, same)
+				}
+			}
+		}
+	}
+}
+
+// TestSpaceships checks that each pattern has the same shape after four
+// generations, moved by shift.
+func TestSpaceships(t *testing.T) {
+	tests := []struct {
+		name  string
+		shift Point
+	}{
+		{"glider", Point{X: 1, Y: 1}},
+		{"lwss", Point{X: -2}},
+	}
+	for _, tt := range tests {
+		g := grid(t, tt.name, 30, 30, Bounded)
+		start := g.Live()
+		g.Run(4, 1)
+		end := g.Live()
+		if !slices.Equal(Normalize(start), Normalize(end)) {
+			t.Errorf("%s changed shape:\n%s", tt.name, g)
+			continue
+		}
+		if moved := (Point{X: end[0].X - start[0].X, Y: end[0].Y - start[0].Y}); moved != tt.shift {
+			t.Errorf("%s moved %v, want %v", tt.name, moved, tt.shift)
+		}
+	}
+}
+
+func TestGliderOnTorus(t *testing.T) {
+	g := grid(t, "glider", 8, 8, Torus)
+	start := g.Clone()
+	// Four generations move it one cell; eight cells bring it back.
+	g.Run(4*8, 1)
+	if !g.Equal(start) {
+		t.Errorf("after 32 generations:\n%s", g)
+	}
+}
+
+func TestDiehard(t *testing.T) {
+	g := grid(t, "diehard", 60, 60, Bounded)
+	g.Run(129, 1)
+	if g.Population() == 0 {
+		t.Fatal("dead after 129 generations")
+	}
+	g.Step(1)
+	if n := g.Population(); n != 0 {
+		t.Errorf("%d cells left after 130 generations", n)
+	}
+}
+
+func TestGosperGun(t *testing.T) {
+	g := grid(t, "gosper-gun", 100, 80, Bounded)
+	// The gun adds a glider every 30 generations.
+	for k := range 4 {
+		if n, want := g.Population(), 36+5*k; n != want {
+			t.Errorf("generation %d: population %d, want %d", g.Generation, n, want)
+		}
+		g.Run(30, 1)
+	}
+}
+
+func TestParallelStep(t *testing.T) {
+	serial := grid(t, "r-pentomino", 64, 48, Torus)
+	for _, workers := range []int{2, 3, 8, 48, 100} {
+		parallel := serial.Clone()
+		s := serial.Clone()
+		for gen := range 150 {
+			s.Step(1)
+			parallel.Step(workers)
+			if !parallel.Equal(s) {
+				t.Fatalf("%d workers differ from 1 at generation %d", workers, gen+1)
+			}
+		}
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	g := grid(t, "blinker", 5, 5, Bounded)
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, g, 3, GIFOptions{Scale: 2}); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 {
+		t.Fatalf("got %d frames, want 3", len(anim.Image))
+	}
+	// The blinker lies along row 2, then column 2, then row 2 again;
+	// cell (1, 2) is alive only in the first and last.
+	for i, want := range []uint8{1, 0, 1} {
+		if got := anim.Image[i].ColorIndexAt(1*2+1, 2*2+1); got != want {
+			t.Errorf("frame %d: cell (1, 2) has colour %d, want %d", i, got, want)
+		}
+	}
+}
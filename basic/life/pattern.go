@@ -0,0 +1,267 @@
+package life
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Point is a cell position: column X, row Y.
+type Point struct{ X, Y int }
+
+// Pattern is an arrangement of live cells read from a pattern file.
+type Pattern struct {
+	Name     string
+	Comments []string
+	Rule     Rule
+	// W and H are the size of the bounding box, and Cells the live cells
+	// relative to its top left corner.
+	W, H  int
+	Cells []Point
+}
+
+// Parse reads a pattern in either format, deciding by its first line: RLE
+// files start with # lines or an "x =" header, plaintext files with !
+// comments or cells.
+func Parse(r io.Reader) (*Pattern, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("life: empty pattern")
+		}
+		if unicode.IsSpace(rune(b[0])) {
+			br.ReadByte()
+			continue
+		}
+		if b[0] == '#' || b[0] == 'x' {
+			return ParseRLE(br)
+		}
+		return ParsePlaintext(br)
+	}
+}
+
+// ParsePlaintext reads the plaintext format used by .cells files: lines
+// starting with ! are comments, the first of them usually "!Name: ...",
+// and each other line is a row with O for a live cell and . for a dead
+// one.
+func ParsePlaintext(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: Conway}
+	sc := bufio.NewScanner(r)
+	y := 0
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimRight(sc.Text(), " \t\r")
+		if c, ok := strings.CutPrefix(text, "!"); ok {
+			if name, ok := strings.CutPrefix(c, "Name:"); ok {
+				p.Name = strings.TrimSpace(name)
+			} else {
+				p.Comments = append(p.Comments, strings.TrimSpace(c))
+			}
+			continue
+		}
+		if y == maxSide {
+			return nil, fmt.Errorf("life: line %d: pattern is more than %d rows high", line, maxSide)
+		}
+		if len(text) > maxSide {
+			return nil, fmt.Errorf("life: line %d: row is more than %d cells wide", line, maxSide)
+		}
+		for x, c := range text {
+			switch c {
+			case 'O', '*':
+				p.Cells = append(p.Cells, Point{x, y})
+				p.W = max(p.W, x+1)
+			case '.':
+			default:
+				return nil, fmt.Errorf("life: line %d: unexpected %q in plaintext pattern", line, c)
+			}
+		}
+		y++
+	}
+	p.H = y
+	return p, sc.Err()
+}
+
+// ParseRLE reads the run length encoded format used by .rle files. Lines
+// starting with # carry metadata (#N is the name, #C and #c comments), a
+// header gives the size and rule:
+//
+//	x = 3, y = 3, rule = B3/S23
+//
+// and the cells follow as runs: b is a dead cell, o a live one, $ ends a
+// row and ! ends the pattern, each optionally preceded by a count. Runs
+// must stay inside the size in the header. So the glider
+//
+//	.O.
+//	..O
+//	OOO
+//
+// is "bo$2bo$3o!".
+func ParseRLE(r io.Reader) (*Pattern, error) {
+	p := &Pattern{Rule: Conway}
+	sc := bufio.NewScanner(r)
+	header := false
+	x, y, run := 0, 0, 0
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if rest, ok := strings.CutPrefix(text, "#"); ok {
+			if rest == "" {
+				continue
+			}
+			switch tag, value := rest[0], strings.TrimSpace(rest[1:]); tag {
+			case 'N':
+				p.Name = value
+			case 'C', 'c', 'O':
+				p.Comments = append(p.Comments, value)
+			}
+			continue
+		}
+		if !header {
+			if text == "" {
+				continue
+			}
+			if err := p.parseHeader(text); err != nil {
+				return nil, fmt.Errorf("life: line %d: %v", line, err)
+			}
+			header = true
+			continue
+		}
+		for _, c := range text {
+			switch {
+			case c >= '0' && c <= '9':
+				if run = run*10 + int(c-'0'); run > maxSide {
+					return nil, fmt.Errorf("life: line %d: run of more than %d cells", line, maxSide)
+				}
+				continue
+			case c == '!':
+				p.H = max(p.H, y+1)
+				return p, nil
+			case c == '$':
+				y += max(run, 1)
+				x = 0
+				if y > p.H {
+					return nil, fmt.Errorf("life: line %d: row %d is past the height of %d", line, y+1, p.H)
+				}
+			case c == 'b' || c == '.':
+				x += max(run, 1)
+				if x > p.W {
+					return nil, fmt.Errorf("life: line %d: row %d is wider than %d", line, y+1, p.W)
+				}
+			case unicode.IsLetter(c):
+				if x+max(run, 1) > p.W || y >= p.H {
+					return nil, fmt.Errorf("life: line %d: cells outside the %dx%d in the header", line, p.W, p.H)
+				}
+				// o is a live cell; other letters are states of
+				// multi-state rules, which count as alive here.
+				for range max(run, 1) {
+					p.Cells = append(p.Cells, Point{x, y})
+					x++
+				}
+				p.W = max(p.W, x)
+			case unicode.IsSpace(c):
+				continue
+			default:
+				return nil, fmt.Errorf("life: line %d: unexpected %q in RLE pattern", line, c)
+			}
+			run = 0
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("life: RLE pattern has no closing !")
+}
+
+// maxSide bounds the width and height of a pattern, so that a header, a
+// run count or a long plaintext file cannot ask for more memory than a
+// grid can use.
+const maxSide = 4096
+
+// parseHeader reads "x = m, y = n, rule = ...".
+func (p *Pattern) parseHeader(text string) error {
+	for field := range strings.SplitSeq(text, ",") {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return fmt.Errorf("bad RLE header field %q", field)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "x":
+			p.W, err = strconv.Atoi(value)
+		case "y":
+			p.H, err = strconv.Atoi(value)
+		case "rule":
+			p.Rule, err = ParseRule(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if p.W < 0 || p.W > maxSide || p.H < 0 || p.H > maxSide {
+		return fmt.Errorf("pattern size %dx%d is outside 0 to %d", p.W, p.H, maxSide)
+	}
+	return nil
+}
+
+// String draws the pattern in plaintext.
+func (p *Pattern) String() string {
+	g := New(p.W, p.H, Bounded)
+	g.Place(p, 0, 0)
+	return g.String()
+}
+
+// Normalize returns cells moved so that the smallest X and Y are zero, in
+// row order. Two sets of cells with the same normal form are the same
+// shape in different places.
+func Normalize(cells []Point) []Point {
+	if len(cells) == 0 {
+		return nil
+	}
+	minX, minY := cells[0].X, cells[0].Y
+	for _, c := range cells {
+		minX, minY = min(minX, c.X), min(minY, c.Y)
+	}
+	out := make([]Point, len(cells))
+	for i, c := range cells {
+		out[i] = Point{c.X - minX, c.Y - minY}
+	}
+	slices.SortFunc(out, func(a, b Point) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return out
+}
+
+//go:embed patterns
+var builtins embed.FS
+
+// Builtins returns the names of the built-in patterns.
+func Builtins() []string {
+	entries, _ := builtins.ReadDir("patterns")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	return names
+}
+
+// Builtin returns the built-in pattern called name.
+func Builtin(name string) (*Pattern, error) {
+	for _, ext := range []string{".rle", ".cells"} {
+		f, err := builtins.Open("patterns/" + name + ext)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+	return nil, fmt.Errorf("life: no built-in pattern %q", name)
+}
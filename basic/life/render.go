@@ -0,0 +1,87 @@
+package life
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+)
+
+// Render draws the grid for a terminal. Each character shows two rows,
+// using the half block characters ▀ and ▄, so that cells come out roughly
+// square.
+func (g *Grid) Render(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < g.H; y += 2 {
+		for x := range g.W {
+			top := g.cells[y*g.W+x]
+			bottom := y+1 < g.H && g.cells[(y+1)*g.W+x]
+			switch {
+			case top && bottom:
+				bw.WriteString("█")
+			case top:
+				bw.WriteString("▀")
+			case bottom:
+				bw.WriteString("▄")
+			default:
+				bw.WriteByte(' ')
+			}
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// GIFOptions controls WriteGIF.
+type GIFOptions struct {
+	// Scale is the width and height of a cell in pixels; it defaults to 4.
+	Scale int
+	// Delay is the time each frame is shown, in hundredths of a second;
+	// it defaults to 10.
+	Delay int
+	// Workers is passed to Step.
+	Workers int
+}
+
+var gifPalette = color.Palette{
+	color.RGBA{0x1e, 0x1e, 0x2e, 0xff}, // dead
+	color.RGBA{0xf9, 0xe2, 0xaf, 0xff}, // alive
+}
+
+// WriteGIF writes an animated GIF of frames generations, starting with
+// the current one, advancing g as it goes. The animation loops forever.
+func WriteGIF(w io.Writer, g *Grid, frames int, opts GIFOptions) error {
+	scale, delay := opts.Scale, opts.Delay
+	if scale <= 0 {
+		scale = 4
+	}
+	if delay <= 0 {
+		delay = 10
+	}
+	anim := &gif.GIF{}
+	for i := range frames {
+		if i > 0 {
+			g.Step(opts.Workers)
+		}
+		img := image.NewPaletted(image.Rect(0, 0, g.W*scale, g.H*scale), gifPalette)
+		for y := range g.H {
+			for x := range g.W {
+				if !g.cells[y*g.W+x] {
+					continue
+				}
+				// Fill the cell's first pixel row, then copy it down.
+				row := img.Pix[y*scale*img.Stride+x*scale:]
+				for k := range scale {
+					row[k] = 1
+				}
+				for k := 1; k < scale; k++ {
+					copy(img.Pix[(y*scale+k)*img.Stride+x*scale:][:scale], row[:scale])
+				}
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
@@ -1,7 +1,18 @@
 package main
 
-import "fmt"
-
+import (
+	"fmt"
+	"os"
+
+	"github.com/amiiralihassanpour/golang_learning/grader"
+	"github.com/amiiralihassanpour/golang_learning/runner"
+	"github.com/amiiralihassanpour/golang_learning/sorting"
+	"github.com/amiiralihassanpour/golang_learning/stats"
+	"github.com/amiiralihassanpour/golang_learning/text"
+	"github.com/amiiralihassanpour/golang_learning/validate"
+)
+
+//kata:todo
 func sum(a int, b int) int {
 	return a + b
 }
@@ -15,13 +26,36 @@ func passbyvalue(x int) {
 	fmt.Println("Inside passbyvalue, number:", x)
 }
 
+//kata:todo
 func passbyreference(x *int) {
 	*x = *x + 10
 	fmt.Println("Inside passbyreference, number:", *x)
 }
 
+// lessons are the demos main runs, in order. `basic lessons` runs them
+// through the runner to count their allocations.
+var lessons = []runner.Lesson{
+	{Name: "variables", Run: variables},
+	{Name: "loops", Run: loops},
+	{Name: "conditionals", Run: conditionals},
+	{Name: "collections", Run: collections},
+	{Name: "runes", Run: runes},
+	{Name: "functions", Run: functions},
+}
+
 func main() {
-	
+	// `basic grade` runs each submission through this binary again.
+	grader.MaybeEnterSandbox()
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	for _, l := range lessons {
+		l.Run()
+	}
+}
+
+func variables() {
 	fmt.Println("Hello, World!")
 	fmt.Println("Welcome to Go programming,", "Let's learn Go together.")
 
@@ -39,15 +73,15 @@ func main() {
 
 	const pi = 3.14
 	fmt.Println("The value of pi is", pi)
+}
 
-	// --------------------------------------------------------------------
-
+func loops() {
 	for i:=0; i<5; i++ {
 		fmt.Println("Iteration:", i)
 	}
+}
 
-	// --------------------------------------------------------------------
-
+func conditionals() {
 	for i:=1; i<=5; i++ {
 		if i%2 == 0 {
 			fmt.Println(i, "is even")
@@ -74,9 +108,9 @@ func main() {
 	default:
 		fmt.Println("key is not in range [1,3]")		
 	}
+}
 
-	// --------------------------------------------------------------------
-
+func collections() {
 	var nums [5]int
 	nums[1] = 20
 	fmt.Println(nums)
@@ -84,6 +118,12 @@ func main() {
 	var arr [5]int = [5]int{1, 2, 3, 4, 5}
 	fmt.Println("Array:", arr)
 
+	// Slicing an array shares its storage, so sorting the slice sorts the
+	// array. `basic sort show` animates this and the other algorithms.
+	shuffled := [5]int{4, 2, 5, 1, 3}
+	counts := sorting.Sort(sorting.Insertion, shuffled[:])
+	fmt.Println("Sorted through a slice:", shuffled, "with", counts)
+
 	s := make([]int, 3, 4)
 	s[0] = 10
 	s[1] = 20
@@ -101,6 +141,10 @@ func main() {
 	slice := []string{"Go", "Python", "Java"}
 	fmt.Println("Slice:", slice)
 
+	// One int per name is enough for an age. `basic gradebook` maps each
+	// name to a slice of scores instead. A map lives only as long as the
+	// program: `basic students save` writes one to a file and reads it
+	// back on the next run.
 	students := make(map[string]int)
 	students["Alice"] = 30
 	students["Bob"] = 25
@@ -114,6 +158,23 @@ func main() {
 
 	var TwoDArray [2][3]int = [2][3]int{{1, 2, 3}, {4, 5, 6}}
 	fmt.Println("Two-dimensional array:", TwoDArray)
+	// A 2D array is an array of rows: range over the rows, then over the
+	// cells of each. `basic life` steps a whole grid of cells this way.
+	for i, row := range TwoDArray {
+		for j, v := range row {
+			fmt.Printf("TwoDArray[%d][%d] = %d\n", i, j, v)
+		}
+	}
+	// A 2D slice is a slice of row slices. Cutting the rows out of one
+	// backing slice keeps the grid in a single allocation; `basic maze`
+	// stores its cells this way.
+	cells := make([]int, 2*3)
+	rows := make([][]int, 2)
+	for i := range rows {
+		rows[i] = cells[i*3 : (i+1)*3]
+	}
+	rows[1][2] = 9
+	fmt.Println("2D slice:", rows, "backing slice:", cells)
 
 	// --------------------------------------------------------------------
 
@@ -121,18 +182,51 @@ func main() {
 	for index, value := range list {
 		fmt.Printf("Index: %d, Value: %d\n", index, value)
 	}
+	// The stats package works on any numeric slice; `basic stats describe`
+	// adds quantiles and a histogram.
+	fmt.Println("Mean:", stats.Mean(list), "standard deviation:", stats.StdDev(list), stats.Sparkline(list))
 
 	for key, value := range students {
 		fmt.Printf("Key: %s, Value: %d\n", key, value)
 	}
+}
+
+func runes() {
+	greeting := "Hi سلام"
+	fmt.Println(greeting, "is", len(greeting), "bytes")
+
+	for i := 0; i < len(greeting); i++ {
+		fmt.Printf("%x ", greeting[i])
+	}
+	fmt.Println()
+
+	for index, char := range greeting {
+		fmt.Printf("Index: %d, Rune: %c (%U)\n", index, char, char)
+	}
 
-	//--------------------------------------------------------------------
+	word := "cafe\u0301"
+	fmt.Println(word, "has", len([]rune(word)), "runes and", text.GraphemeCount(word), "characters")
+	fmt.Println("Reversed rune by rune:", text.ReverseRunes(word))
+	fmt.Println("Reversed by character:", text.Reverse(word))
+	fmt.Println("2025 in Persian digits:", text.ToPersianDigits("2025"))
+}
 
+func functions() {
 	fmt.Println("Sum of 5 and 10 is", sum(5, 10))
 
 	myname, myage := myfunction("Alice")
 	fmt.Printf("Name: %s, Age: %d\n", myname, myage)	
 
+	type person struct {
+		Name string `validate:"required"`
+		Age  int    `validate:"min=0,max=150"`
+	}
+	if err := validate.Struct(person{Name: myname, Age: myage}); err != nil {
+		fmt.Println("Invalid person:", err)
+	} else {
+		fmt.Println("Person is valid")
+	}
+
 	z := 20
 	fmt.Println("Before passbyvalue, z:", z)
 	passbyvalue(z)
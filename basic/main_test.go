@@ -0,0 +1,137 @@
+package main
+
+import (
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amiiralihassanpour/golang_learning/quick"
+)
+
+var (
+	intPair   = quick.Zip(quick.Int(), quick.Int())
+	smallInts = quick.SliceOf(quick.IntRange(-100, 100))
+)
+
+func TestSumProperties(t *testing.T) {
+	t.Run("commutative", func(t *testing.T) {
+		quick.ForAll(t, intPair, func(p quick.Pair[int, int]) bool {
+			return sum(p.A, p.B) == sum(p.B, p.A)
+		})
+	})
+	t.Run("0 is the identity", func(t *testing.T) {
+		quick.ForAll(t, quick.Int(), func(a int) bool {
+			return sum(a, 0) == a && sum(0, a) == a
+		})
+	})
+	t.Run("wraps around on overflow and stays invertible", func(t *testing.T) {
+		quick.ForAll(t, intPair, func(p quick.Pair[int, int]) bool {
+			return sum(sum(p.A, p.B), -p.B) == p.A
+		})
+	})
+}
+
+// TestSumOverflows states a property that is false for machine ints and
+// checks that quick finds a counterexample: adding a non-negative number
+// can decrease the sum once it overflows.
+func TestSumOverflows(t *testing.T) {
+	f := quick.Check(intPair, func(p quick.Pair[int, int]) bool {
+		return p.B < 0 || sum(p.A, p.B) >= p.A
+	}, nil)
+	if f == nil {
+		t.Fatal("no overflow found")
+	}
+	if p := f.Minimal; p.B < 0 || sum(p.A, p.B) >= p.A {
+		t.Errorf("the minimal counterexample %+v does not overflow", p)
+	}
+}
+
+func TestSliceProperties(t *testing.T) {
+	t.Run("reversing twice gives the original", func(t *testing.T) {
+		quick.ForAll(t, smallInts, func(s []int) bool {
+			r := slices.Clone(s)
+			slices.Reverse(r)
+			slices.Reverse(r)
+			return slices.Equal(r, s)
+		})
+	})
+	t.Run("append adds the lengths", func(t *testing.T) {
+		quick.ForAll(t, quick.Zip(smallInts, smallInts), func(p quick.Pair[[]int, []int]) bool {
+			return len(append(slices.Clip(p.A), p.B...)) == len(p.A)+len(p.B)
+		})
+	})
+	t.Run("sorting is idempotent and keeps the length", func(t *testing.T) {
+		quick.ForAll(t, smallInts, func(s []int) bool {
+			once := slices.Clone(s)
+			slices.Sort(once)
+			twice := slices.Clone(once)
+			slices.Sort(twice)
+			return slices.IsSorted(once) && slices.Equal(once, twice) && len(once) == len(s)
+		})
+	})
+	t.Run("deleting a map key removes exactly one entry", func(t *testing.T) {
+		quick.ForAll(t, quick.MapOf(quick.String(), quick.IntRange(0, 150)), func(m map[string]int) bool {
+			for k := range m {
+				n := len(m)
+				delete(m, k)
+				_, ok := m[k]
+				return !ok && len(m) == n-1
+			}
+			return true
+		})
+	})
+}
+
+func TestPassByReference(t *testing.T) {
+	z := 20
+	stdout(t, func() { passbyreference(&z) })
+	if z != 30 {
+		t.Errorf("z = %d after passbyreference, want 30", z)
+	}
+	z = 20
+	stdout(t, func() { passbyvalue(z) })
+	if z != 20 {
+		t.Errorf("z = %d after passbyvalue, want 20", z)
+	}
+}
+
+func TestLessonOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func()
+		want string
+	}{
+		{"loops", loops, "Iteration: 0\nIteration: 1\nIteration: 2\nIteration: 3\nIteration: 4\n"},
+		{"conditionals", conditionals, "1 is odd\n2 is even\n3 is odd\n4 is even\n5 is odd\nHello, Alice!\nkey is 3\n"},
+	}
+	for _, tt := range tests {
+		if got := stdout(t, tt.run); got != tt.want {
+			t.Errorf("%s printed\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+	if got := stdout(t, functions); !strings.Contains(got, "Sum of 5 and 10 is 15\n") {
+		t.Errorf("functions printed\n%s", got)
+	}
+}
+
+// stdout calls fn and returns what it printed.
+func stdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	fn()
+	os.Stdout = saved
+	w.Close()
+	return <-done
+}
@@ -0,0 +1,148 @@
+package maze
+
+import "math/rand/v2"
+
+// unvisited returns the neighbours of p inside the grid that have not
+// been carved into yet, with the direction to each.
+func (m *Maze) unvisited(p Point) (dirs []Dir) {
+	for _, d := range Dirs {
+		if q := p.Step(d); m.In(q) && m.cells[q.Y][q.X] == 0 {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// Backtracker carves a maze by a random depth-first walk: from the current
+// cell it moves to a random unvisited neighbour, and when there is none it
+// backs up to the last cell that has one. The long runs before each back
+// up make long, winding corridors with few dead ends.
+func Backtracker(m *Maze, r *rand.Rand) {
+	stack := []Point{{r.IntN(m.W), r.IntN(m.H)}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		dirs := m.unvisited(p)
+		if len(dirs) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		d := dirs[r.IntN(len(dirs))]
+		m.Carve(p, d)
+		stack = append(stack, p.Step(d))
+	}
+}
+
+// Prim grows the maze from one cell, like Prim's minimum spanning tree
+// algorithm with random weights: it keeps a frontier of cells next to the
+// maze, picks one at random and connects it to a random neighbour already
+// in the maze. Growing everywhere at once makes many short dead ends.
+func Prim(m *Maze, r *rand.Rand) {
+	in := grid[bool](m.W, m.H)
+	queued := grid[bool](m.W, m.H)
+	var frontier []Point
+	add := func(p Point) {
+		in[p.Y][p.X] = true
+		for _, d := range Dirs {
+			if q := p.Step(d); m.In(q) && !in[q.Y][q.X] && !queued[q.Y][q.X] {
+				queued[q.Y][q.X] = true
+				frontier = append(frontier, q)
+			}
+		}
+	}
+	add(Point{r.IntN(m.W), r.IntN(m.H)})
+	for len(frontier) > 0 {
+		// Remove a random cell by swapping the last one into its place.
+		i := r.IntN(len(frontier))
+		p := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		var inside []Dir
+		for _, d := range Dirs {
+			if q := p.Step(d); m.In(q) && in[q.Y][q.X] {
+				inside = append(inside, d)
+			}
+		}
+		m.Carve(p, inside[r.IntN(len(inside))])
+		add(p)
+	}
+}
+
+// Kruskal knocks down the walls in random order, skipping each wall whose
+// two cells are already connected, as Kruskal's minimum spanning tree
+// algorithm does. A union-find structure tracks which cells are
+// connected.
+func Kruskal(m *Maze, r *rand.Rand) {
+	type wall struct {
+		p Point
+		d Dir
+	}
+	var walls []wall
+	for y := range m.H {
+		for x := range m.W {
+			if x+1 < m.W {
+				walls = append(walls, wall{Point{x, y}, East})
+			}
+			if y+1 < m.H {
+				walls = append(walls, wall{Point{x, y}, South})
+			}
+		}
+	}
+	r.Shuffle(len(walls), func(i, j int) { walls[i], walls[j] = walls[j], walls[i] })
+
+	parent := make([]int, m.W*m.H)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for _, w := range walls {
+		q := w.p.Step(w.d)
+		a, b := find(w.p.Y*m.W+w.p.X), find(q.Y*m.W+q.X)
+		if a != b {
+			parent[a] = b
+			m.Carve(w.p, w.d)
+		}
+	}
+}
+
+// Wilson builds the maze from loop-erased random walks: from a cell not
+// yet in the maze it walks at random until it hits the maze, forgetting
+// any loop it makes on the way, and adds the path it walked. It is slower
+// to start than the others, while the maze is small and hard to hit, but
+// it is unbiased: every possible maze is equally likely.
+func Wilson(m *Maze, r *rand.Rand) {
+	in := grid[bool](m.W, m.H)
+	// exit[y][x] is the direction the current walk last left a cell by.
+	// Overwriting it when the walk comes back erases the loop.
+	exit := grid[Dir](m.W, m.H)
+	in[r.IntN(m.H)][r.IntN(m.W)] = true
+	for _, i := range r.Perm(m.W * m.H) {
+		start := Point{i % m.W, i / m.W}
+		if in[start.Y][start.X] {
+			continue
+		}
+		for p := start; !in[p.Y][p.X]; {
+			var d Dir
+			for {
+				d = Dirs[r.IntN(4)]
+				if m.In(p.Step(d)) {
+					break
+				}
+			}
+			exit[p.Y][p.X] = d
+			p = p.Step(d)
+		}
+		for p := start; !in[p.Y][p.X]; {
+			in[p.Y][p.X] = true
+			d := exit[p.Y][p.X]
+			m.Carve(p, d)
+			p = p.Step(d)
+		}
+	}
+}
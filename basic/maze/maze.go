@@ -0,0 +1,227 @@
+// Package maze generates mazes on rectangular grids, solves them and
+// draws them as ASCII, Unicode box drawing or PNG.
+//
+// A Maze is a two-dimensional slice of cells, each recording which of its
+// four sides are open. All the generators make perfect mazes: every cell
+// can be reached from every other by exactly one path, which is to say the
+// passages form a spanning tree of the grid. They differ in texture.
+// The recursive backtracker makes long winding corridors, Prim's algorithm
+// many short dead ends, Kruskal's something in between, and Wilson's
+// algorithm picks uniformly among all possible mazes.
+//
+// Generation takes a *rand.Rand, so the same seed always gives the same
+// maze.
+package maze
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Dir is a set of sides of a cell.
+type Dir uint8
+
+const (
+	North Dir = 1 << iota
+	East
+	South
+	West
+)
+
+// Dirs lists the four directions.
+var Dirs = [4]Dir{North, East, South, West}
+
+func (d Dir) String() string {
+	s := ""
+	for i, name := range [4]string{"N", "E", "S", "W"} {
+		if d&Dirs[i] != 0 {
+			s += name
+		}
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// Opposite returns the direction facing d.
+func (d Dir) Opposite() Dir {
+	switch d {
+	case North:
+		return South
+	case East:
+		return West
+	case South:
+		return North
+	}
+	return East
+}
+
+// Point is a cell: column X, row Y, with 0, 0 at the top left.
+type Point struct{ X, Y int }
+
+// Step returns the neighbouring cell in direction d.
+func (p Point) Step(d Dir) Point {
+	switch d {
+	case North:
+		p.Y--
+	case East:
+		p.X++
+	case South:
+		p.Y++
+	case West:
+		p.X--
+	}
+	return p
+}
+
+// Maze is a grid of cells with walls between them.
+type Maze struct {
+	W, H int
+	// cells[y][x] has the bit of each open side of the cell set. The rows
+	// are slices of one backing array, so the whole grid is a single
+	// allocation.
+	cells [][]Dir
+}
+
+// New returns a w by h grid with every wall standing.
+func New(w, h int) *Maze {
+	backing := make([]Dir, w*h)
+	cells := make([][]Dir, h)
+	for y := range cells {
+		// The three-index slice caps each row, so an append to one row
+		// could never run into the next.
+		cells[y] = backing[y*w : (y+1)*w : (y+1)*w]
+	}
+	return &Maze{W: w, H: h, cells: cells}
+}
+
+// In reports whether p is inside the grid.
+func (m *Maze) In(p Point) bool {
+	return p.X >= 0 && p.X < m.W && p.Y >= 0 && p.Y < m.H
+}
+
+// Open reports whether the side d of cell p is open.
+func (m *Maze) Open(p Point, d Dir) bool {
+	return m.cells[p.Y][p.X]&d != 0
+}
+
+// Carve knocks down the wall on side d of p, opening it from both sides.
+func (m *Maze) Carve(p Point, d Dir) {
+	q := p.Step(d)
+	if !m.In(p) || !m.In(q) {
+		panic(fmt.Sprintf("maze: carving %v from %v leaves the grid", d, p))
+	}
+	m.cells[p.Y][p.X] |= d
+	m.cells[q.Y][q.X] |= d.Opposite()
+}
+
+// Exits returns the open sides of p.
+func (m *Maze) Exits(p Point) Dir { return m.cells[p.Y][p.X] }
+
+// Neighbors returns the cells reachable from p in one step.
+func (m *Maze) Neighbors(p Point) []Point {
+	var out []Point
+	for _, d := range Dirs {
+		if m.Open(p, d) {
+			out = append(out, p.Step(d))
+		}
+	}
+	return out
+}
+
+// Passages returns the number of open walls between cells.
+func (m *Maze) Passages() int {
+	n := 0
+	for _, row := range m.cells {
+		for _, c := range row {
+			// Count each passage once, from its west or north end.
+			if c&East != 0 {
+				n++
+			}
+			if c&South != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// DeadEnds returns the number of cells with a single exit.
+func (m *Maze) DeadEnds() int {
+	n := 0
+	for _, row := range m.cells {
+		for _, c := range row {
+			if c == North || c == East || c == South || c == West {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Perfect reports whether the maze is a spanning tree: every cell is
+// reachable from the top left one, and there are exactly W*H-1 passages,
+// so there are no loops.
+func (m *Maze) Perfect() bool {
+	if m.Passages() != m.W*m.H-1 {
+		return false
+	}
+	seen := grid[bool](m.W, m.H)
+	stack := []Point{{0, 0}}
+	seen[0][0] = true
+	reached := 1
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, q := range m.Neighbors(p) {
+			if !seen[q.Y][q.X] {
+				seen[q.Y][q.X] = true
+				reached++
+				stack = append(stack, q)
+			}
+		}
+	}
+	return reached == m.W*m.H
+}
+
+// grid returns a w by h two-dimensional slice of zero values.
+func grid[T any](w, h int) [][]T {
+	backing := make([]T, w*h)
+	g := make([][]T, h)
+	for y := range g {
+		g[y] = backing[y*w : (y+1)*w : (y+1)*w]
+	}
+	return g
+}
+
+// Generator is a named maze generation algorithm.
+type Generator struct {
+	Name     string
+	Generate func(m *Maze, r *rand.Rand)
+}
+
+// Generators lists the algorithms.
+var Generators = []Generator{
+	{"backtracker", Backtracker},
+	{"prim", Prim},
+	{"kruskal", Kruskal},
+	{"wilson", Wilson},
+}
+
+// Lookup returns the generator called name.
+func Lookup(name string) (Generator, bool) {
+	for _, g := range Generators {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return Generator{}, false
+}
+
+// Generate returns a w by h maze made by gen, seeded with seed.
+func Generate(gen Generator, w, h int, seed uint64) *Maze {
+	m := New(w, h)
+	gen.Generate(m, rand.New(rand.NewPCG(seed, 0)))
+	return m
+}
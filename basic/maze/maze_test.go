@@ -0,0 +1,202 @@
+package maze
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// validPath checks that path runs from from to to through open walls.
+func validPath(m *Maze, path []Point, from, to Point) error {
+	if len(path) == 0 || path[0] != from || path[len(path)-1] != to {
+		return fmt.Errorf("path %v does not run from %v to %v", path, from, to)
+	}
+	for i := 1; i < len(path); i++ {
+		ok := false
+		for _, d := range Dirs {
+			if path[i-1].Step(d) == path[i] && m.Open(path[i-1], d) {
+				ok = true
+			}
+		}
+		if !ok {
+			return fmt.Errorf("path goes through a wall from %v to %v", path[i-1], path[i])
+		}
+	}
+	return nil
+}
+
+func render(m *Maze, style Style, path []Point) string {
+	var b strings.Builder
+	m.Render(&b, style, path)
+	return b.String()
+}
+
+func lookup(t *testing.T, name string) Generator {
+	t.Helper()
+	gen, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("no generator %q", name)
+	}
+	return gen
+}
+
+func TestPerfect(t *testing.T) {
+	sizes := [][2]int{{1, 1}, {1, 7}, {7, 1}, {2, 2}, {10, 10}, {31, 17}}
+	for _, gen := range Generators {
+		for _, size := range sizes {
+			for seed := range uint64(5) {
+				if m := Generate(gen, size[0], size[1], seed); !m.Perfect() {
+					t.Errorf("%s %dx%d, seed %d: %d passages\n%s", gen.Name, size[0], size[1], seed, m.Passages(), render(m, ASCII, nil))
+				}
+			}
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	for _, gen := range Generators {
+		a := render(Generate(gen, 12, 8, 42), ASCII, nil)
+		b := render(Generate(gen, 12, 8, 42), ASCII, nil)
+		c := render(Generate(gen, 12, 8, 43), ASCII, nil)
+		if a != b {
+			t.Errorf("%s: seed 42 made\n%s\nthen\n%s", gen.Name, a, b)
+		}
+		if a == c {
+			t.Errorf("%s: seeds 42 and 43 made the same maze", gen.Name)
+		}
+	}
+}
+
+// TestWilsonUniform checks that Wilson's algorithm picks every spanning
+// tree equally often. A 3x2 grid has 15 of them; 15000 mazes should give
+// each about 1000 times. The standard deviation is about 31, so the
+// bounds allow for five of them.
+func TestWilsonUniform(t *testing.T) {
+	if testing.Short() {
+		t.Skip("generates 15000 mazes")
+	}
+	wilson := lookup(t, "wilson")
+	counts := map[string]int{}
+	for seed := range uint64(15000) {
+		counts[render(Generate(wilson, 3, 2, seed), ASCII, nil)]++
+	}
+	if len(counts) != 15 {
+		t.Fatalf("got %d different mazes, want 15", len(counts))
+	}
+	for m, n := range counts {
+		if n < 850 || n > 1150 {
+			t.Errorf("made %d times, want about 1000:\n%s", n, m)
+		}
+	}
+}
+
+func TestSolversAgree(t *testing.T) {
+	astar, bfs := 0, 0
+	for _, gen := range Generators {
+		for seed := range uint64(10) {
+			m := Generate(gen, 25, 15, seed)
+			from, to := Point{}, Point{X: 24, Y: 14}
+			var first Solution
+			for i, s := range Solvers {
+				sol, err := s.Solve(m, from, to)
+				if err == nil {
+					err = validPath(m, sol.Path, from, to)
+				}
+				if err != nil {
+					t.Fatalf("%s on %s, seed %d: %v", s.Name, gen.Name, seed, err)
+				}
+				// A perfect maze has only one path, so they must agree on
+				// it, not just on its length.
+				if i == 0 {
+					first = sol
+				} else if !slices.Equal(sol.Path, first.Path) {
+					t.Errorf("%s on %s, seed %d: %d steps, %s found %d", s.Name, gen.Name, seed, len(sol.Path)-1, Solvers[0].Name, len(first.Path)-1)
+				}
+				switch s.Name {
+				case "bfs":
+					bfs += sol.Explored
+				case "astar":
+					astar += sol.Explored
+				}
+			}
+		}
+	}
+	if astar >= bfs {
+		t.Errorf("A* explored %d cells in all, BFS %d", astar, bfs)
+	}
+}
+
+func TestSolveAnyCells(t *testing.T) {
+	m := Generate(lookup(t, "kruskal"), 9, 6, 7)
+	for _, s := range Solvers {
+		for _, ends := range [][2]Point{{{X: 4, Y: 3}, {X: 4, Y: 3}}, {{X: 8, Y: 0}, {X: 0, Y: 5}}, {{X: 3, Y: 5}, {X: 6, Y: 1}}} {
+			sol, err := s.Solve(m, ends[0], ends[1])
+			if err == nil {
+				err = validPath(m, sol.Path, ends[0], ends[1])
+			}
+			if err != nil {
+				t.Errorf("%s: %v", s.Name, err)
+			}
+		}
+		if _, err := s.Solve(m, Point{}, Point{X: 9, Y: 0}); err == nil {
+			t.Errorf("%s accepts a goal outside the maze", s.Name)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	// A hand-carved 2x2 maze shaped like a U lying on its side.
+	m := New(2, 2)
+	m.Carve(Point{X: 0, Y: 0}, East)
+	m.Carve(Point{X: 0, Y: 0}, South)
+	m.Carve(Point{X: 0, Y: 1}, East)
+	path := []Point{{X: 1, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{ASCII, "+---+---+\n| *   S |\n+   +---+\n| *   G |\n+---+---+\n"},
+		{Unicode, "┌───────┐\n│ •   S │\n│   ╶───┤\n│ •   G │\n└───────┘\n"},
+	}
+	for _, tt := range tests {
+		if got := render(m, tt.style, path); got != tt.want {
+			t.Errorf("got\n%swant\n%s", got, tt.want)
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	m := Generate(lookup(t, "backtracker"), 8, 5, 3)
+	opts := PNGOptions{Cell: 10, Wall: 2}
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, m, nil, opts); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 8*10+2 || b.Dy() != 5*10+2 {
+		t.Fatalf("image is %dx%d, want 82x52", b.Dx(), b.Dy())
+	}
+	// The middle of each cell's north and west side is dark exactly when
+	// the wall is there.
+	for y := range m.H {
+		for x := range m.W {
+			p := Point{X: x, Y: y}
+			for _, side := range []struct {
+				d    Dir
+				x, y int
+			}{{North, x*10 + 6, y*10 + 1}, {West, x*10 + 1, y*10 + 6}} {
+				r, _, _, _ := img.At(side.x, side.y).RGBA()
+				wall := r < 0x8000
+				if open := m.Open(p, side.d); wall == open {
+					t.Errorf("cell %v: wall %v drawn %v, open %v", p, side.d, wall, open)
+				}
+			}
+		}
+	}
+}
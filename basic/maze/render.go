@@ -0,0 +1,203 @@
+package maze
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Style picks the characters Render draws with.
+type Style int
+
+const (
+	// ASCII draws walls with + - and |, and marks the path with *.
+	ASCII Style = iota
+	// Unicode draws walls with box drawing characters, joining them
+	// properly at the corners, and marks the path with •.
+	Unicode
+)
+
+// boxes[arms] is the box drawing character with the given arms: 1 up,
+// 2 right, 4 down, 8 left.
+var boxes = []rune(" ╵╶└╷│┌├╴┘─┴┐┤┬┼")
+
+// wallAbove reports whether there is a wall along the top of cell x, y;
+// y may be H for the bottom edge of the grid.
+func (m *Maze) wallAbove(x, y int) bool {
+	return y == 0 || y == m.H || !m.Open(Point{x, y}, North)
+}
+
+// wallLeft reports whether there is a wall along the left of cell x, y;
+// x may be W for the right edge of the grid.
+func (m *Maze) wallLeft(x, y int) bool {
+	return x == 0 || x == m.W || !m.Open(Point{x, y}, West)
+}
+
+// corner returns the arms of the wall corner at the top left of cell x, y.
+func (m *Maze) corner(x, y int) int {
+	arms := 0
+	if y > 0 && m.wallLeft(x, y-1) {
+		arms |= 1
+	}
+	if x < m.W && m.wallAbove(x, y) {
+		arms |= 2
+	}
+	if y < m.H && m.wallLeft(x, y) {
+		arms |= 4
+	}
+	if x > 0 && m.wallAbove(x-1, y) {
+		arms |= 8
+	}
+	return arms
+}
+
+// Render draws the maze as text, three characters wide and two lines high
+// per cell, with the cells of path marked and its ends labelled S and G.
+func (m *Maze) Render(w io.Writer, style Style, path []Point) error {
+	marks := grid[rune](m.W, m.H)
+	mark := '*'
+	if style == Unicode {
+		mark = '•'
+	}
+	for _, p := range path {
+		marks[p.Y][p.X] = mark
+	}
+	if len(path) > 0 {
+		first, last := path[0], path[len(path)-1]
+		marks[first.Y][first.X] = 'S'
+		marks[last.Y][last.X] = 'G'
+	}
+
+	horizontal, vertical := "---", '|'
+	if style == Unicode {
+		horizontal, vertical = "───", '│'
+	}
+	bw := bufio.NewWriter(w)
+	for y := range m.H + 1 {
+		// The wall line along the top of row y.
+		for x := range m.W + 1 {
+			if style == Unicode {
+				bw.WriteRune(boxes[m.corner(x, y)])
+			} else {
+				bw.WriteByte('+')
+			}
+			if x == m.W {
+				break
+			}
+			if m.wallAbove(x, y) {
+				bw.WriteString(horizontal)
+			} else {
+				bw.WriteString("   ")
+			}
+		}
+		bw.WriteByte('\n')
+		if y == m.H {
+			break
+		}
+		// The cells of row y and the walls between them.
+		for x := range m.W + 1 {
+			if m.wallLeft(x, y) {
+				bw.WriteRune(vertical)
+			} else {
+				bw.WriteByte(' ')
+			}
+			if x == m.W {
+				break
+			}
+			bw.WriteByte(' ')
+			if r := marks[y][x]; r != 0 {
+				bw.WriteRune(r)
+			} else {
+				bw.WriteByte(' ')
+			}
+			bw.WriteByte(' ')
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// PNGOptions controls Image and WritePNG.
+type PNGOptions struct {
+	// Cell is the distance between walls in pixels; it defaults to 12.
+	Cell int
+	// Wall is the thickness of a wall in pixels; it defaults to 2.
+	Wall int
+}
+
+func (o PNGOptions) sizes() (cell, wall int) {
+	cell, wall = o.Cell, o.Wall
+	if cell <= 0 {
+		cell = 12
+	}
+	if wall <= 0 {
+		wall = 2
+	}
+	return cell, min(wall, cell-1)
+}
+
+// The palette indexes of Image.
+const (
+	background = iota
+	wallColor
+	pathColor
+	endColor
+)
+
+var pngPalette = color.Palette{
+	background: color.White,
+	wallColor:  color.RGBA{0x1e, 0x1e, 0x2e, 0xff},
+	pathColor:  color.RGBA{0xe6, 0x4a, 0x19, 0xff},
+	endColor:   color.RGBA{0x2e, 0x7d, 0x32, 0xff},
+}
+
+// Image draws the maze as a paletted image, with the path as a line
+// through the middle of its cells and a square on each end.
+func (m *Maze) Image(path []Point, opts PNGOptions) *image.Paletted {
+	cell, wall := opts.sizes()
+	img := image.NewPaletted(image.Rect(0, 0, m.W*cell+wall, m.H*cell+wall), pngPalette)
+	fill := func(r image.Rectangle, c uint8) {
+		draw.Draw(img, r, &image.Uniform{pngPalette[c]}, image.Point{}, draw.Src)
+	}
+
+	// Each wall runs from corner to corner, thickened down and to the
+	// right, so the walls of the bottom and right edges fit in the extra
+	// wall pixels.
+	for y := range m.H + 1 {
+		for x := range m.W + 1 {
+			if x < m.W && m.wallAbove(x, y) {
+				fill(image.Rect(x*cell, y*cell, (x+1)*cell+wall, y*cell+wall), wallColor)
+			}
+			if y < m.H && m.wallLeft(x, y) {
+				fill(image.Rect(x*cell, y*cell, x*cell+wall, (y+1)*cell+wall), wallColor)
+			}
+		}
+	}
+
+	if len(path) == 0 {
+		return img
+	}
+	centre := func(p Point) image.Point {
+		return image.Pt(p.X*cell+(cell+wall)/2, p.Y*cell+(cell+wall)/2)
+	}
+	line := max(cell/4, 1)
+	for i := 1; i < len(path); i++ {
+		a, b := centre(path[i-1]), centre(path[i])
+		r := image.Rectangle{a, b}.Canon()
+		fill(image.Rect(r.Min.X-line/2, r.Min.Y-line/2, r.Max.X-line/2+line, r.Max.Y-line/2+line), pathColor)
+	}
+	end := max(cell/2, 1)
+	for _, p := range []Point{path[0], path[len(path)-1]} {
+		c := centre(p)
+		fill(image.Rect(c.X-end/2, c.Y-end/2, c.X-end/2+end, c.Y-end/2+end), endColor)
+	}
+	return img
+}
+
+// WritePNG writes the maze as a PNG image, drawn by Image.
+func WritePNG(w io.Writer, m *Maze, path []Point, opts PNGOptions) error {
+	return png.Encode(w, m.Image(path, opts))
+}
@@ -0,0 +1,221 @@
+package maze
+
+import (
+	"fmt"
+
+	"github.com/amiiralihassanpour/golang_learning/containers"
+)
+
+// Solution is a path through a maze and what it cost to find.
+type Solution struct {
+	// Path runs from the start to the goal, both included.
+	Path []Point
+	// Explored is the number of cells the solver examined: taken off the
+	// queue for BFS and A*, filled in for dead-end filling.
+	Explored int
+}
+
+// Solver is a named maze solving algorithm.
+type Solver struct {
+	Name  string
+	Solve func(m *Maze, from, to Point) (Solution, error)
+}
+
+// Solvers lists the algorithms.
+var Solvers = []Solver{
+	{"bfs", BFS},
+	{"astar", AStar},
+	{"deadend", DeadEndFill},
+}
+
+// LookupSolver returns the solver called name.
+func LookupSolver(name string) (Solver, bool) {
+	for _, s := range Solvers {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Solver{}, false
+}
+
+func (m *Maze) check(from, to Point) error {
+	for _, p := range []Point{from, to} {
+		if !m.In(p) {
+			return fmt.Errorf("maze: %v is outside the %dx%d grid", p, m.W, m.H)
+		}
+	}
+	return nil
+}
+
+// noPath is the error for a goal that cannot be reached, which can only
+// happen in a maze that is not perfect.
+func noPath(from, to Point) error {
+	return fmt.Errorf("maze: no path from %v to %v", from, to)
+}
+
+// walkBack follows prev from to back to from and returns the path the
+// right way round.
+func walkBack(prev [][]Point, from, to Point) []Point {
+	var path []Point
+	for p := to; ; p = prev[p.Y][p.X] {
+		path = append(path, p)
+		if p == from {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// BFS finds a shortest path by breadth-first search. It spreads out from
+// the start evenly in every direction, so it explores every cell closer
+// than the goal.
+func BFS(m *Maze, from, to Point) (Solution, error) {
+	if err := m.check(from, to); err != nil {
+		return Solution{}, err
+	}
+	seen := grid[bool](m.W, m.H)
+	prev := grid[Point](m.W, m.H)
+	seen[from.Y][from.X] = true
+	queue := []Point{from}
+	explored := 0
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		explored++
+		if p == to {
+			return Solution{walkBack(prev, from, to), explored}, nil
+		}
+		for _, q := range m.Neighbors(p) {
+			if !seen[q.Y][q.X] {
+				seen[q.Y][q.X] = true
+				prev[q.Y][q.X] = p
+				queue = append(queue, q)
+			}
+		}
+	}
+	return Solution{Explored: explored}, noPath(from, to)
+}
+
+// AStar finds a shortest path by A* search, guided by the Manhattan
+// distance to the goal, which never overestimates the length of a path
+// along the grid. It explores the cells that look closest first, which in
+// a maze helps less than in open country: the corridors often lead away
+// from the goal before they turn back.
+func AStar(m *Maze, from, to Point) (Solution, error) {
+	if err := m.check(from, to); err != nil {
+		return Solution{}, err
+	}
+	type queued struct {
+		p    Point
+		g, f int
+	}
+	// Among equal estimates prefer the cell furthest along, which follows
+	// one promising corridor to its end rather than widening the search.
+	h := containers.NewHeap(func(a, b queued) bool {
+		if a.f != b.f {
+			return a.f < b.f
+		}
+		return a.g > b.g
+	}, queued{from, 0, manhattan(from, to)})
+	done := grid[bool](m.W, m.H)
+	dist := grid[int](m.W, m.H)
+	prev := grid[Point](m.W, m.H)
+	explored := 0
+	for h.Len() > 0 {
+		e, _ := h.Pop()
+		if done[e.p.Y][e.p.X] {
+			continue
+		}
+		done[e.p.Y][e.p.X] = true
+		explored++
+		if e.p == to {
+			return Solution{walkBack(prev, from, to), explored}, nil
+		}
+		for _, q := range m.Neighbors(e.p) {
+			g := e.g + 1
+			if done[q.Y][q.X] || (dist[q.Y][q.X] != 0 && dist[q.Y][q.X] <= g) {
+				continue
+			}
+			dist[q.Y][q.X] = g
+			prev[q.Y][q.X] = e.p
+			h.Push(queued{q, g, g + manhattan(q, to)})
+		}
+	}
+	return Solution{Explored: explored}, noPath(from, to)
+}
+
+func manhattan(a, b Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// DeadEndFill solves a maze without searching at all: it fills in every
+// dead end other than the start and goal, then the cells that became dead
+// ends once those were filled, and so on until none are left. In a perfect
+// maze what remains is exactly the path. It looks at every cell, so it is
+// no quicker than BFS, but it is how a person would solve a maze on paper.
+// Loops are never filled, so in a maze that is not perfect the walk along
+// what remains can take a wrong turn and report no path.
+func DeadEndFill(m *Maze, from, to Point) (Solution, error) {
+	if err := m.check(from, to); err != nil {
+		return Solution{}, err
+	}
+	filled := grid[bool](m.W, m.H)
+	exits := grid[int](m.W, m.H)
+	var queue []Point
+	for y := range m.H {
+		for x := range m.W {
+			p := Point{x, y}
+			exits[y][x] = len(m.Neighbors(p))
+			if exits[y][x] <= 1 && p != from && p != to {
+				queue = append(queue, p)
+			}
+		}
+	}
+	explored := 0
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		filled[p.Y][p.X] = true
+		explored++
+		for _, q := range m.Neighbors(p) {
+			if filled[q.Y][q.X] {
+				continue
+			}
+			if exits[q.Y][q.X]--; exits[q.Y][q.X] == 1 && q != from && q != to {
+				queue = append(queue, q)
+			}
+		}
+	}
+
+	// Walk the cells left over, which in a perfect maze form a single
+	// corridor.
+	path := []Point{from}
+	seen := grid[bool](m.W, m.H)
+	seen[from.Y][from.X] = true
+	for p := from; p != to; {
+		next, ok := Point{}, false
+		for _, q := range m.Neighbors(p) {
+			if !filled[q.Y][q.X] && !seen[q.Y][q.X] {
+				next, ok = q, true
+				break
+			}
+		}
+		if !ok {
+			return Solution{Explored: explored}, noPath(from, to)
+		}
+		seen[next.Y][next.X] = true
+		path = append(path, next)
+		p = next
+	}
+	return Solution{path, explored}, nil
+}
@@ -0,0 +1,149 @@
+// Package mutate performs mutation testing: it makes small changes to the
+// source (mutants), runs the tests against each one and reports the
+// mutants the tests did not notice. A surviving mutant points at behaviour
+// no test checks.
+//
+// Mutations are found on the AST and applied as byte edits, so everything
+// else in the file, including comments and formatting, stays the same.
+package mutate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Mutant is one change to one file.
+type Mutant struct {
+	ID      int
+	Package string // import path of the package the file belongs to
+	File    string // path relative to the module root
+	Line    int
+	Col     int
+	// Desc says what changed, e.g. `"+" -> "-"`.
+	Desc string
+
+	offset int    // byte offset of the replaced text
+	old    string // replaced text
+	new    string // replacement
+}
+
+// Apply returns src with the mutation applied.
+func (m *Mutant) Apply(src []byte) ([]byte, error) {
+	end := m.offset + len(m.old)
+	if end > len(src) || string(src[m.offset:end]) != m.old {
+		return nil, fmt.Errorf("mutate: %s:%d: source changed since the mutant was found", m.File, m.Line)
+	}
+	out := make([]byte, 0, len(src)-len(m.old)+len(m.new))
+	out = append(out, src[:m.offset]...)
+	out = append(out, m.new...)
+	return append(out, src[end:]...), nil
+}
+
+// Diff returns the changed line of src in unified-diff style. Every
+// mutation stays within one line.
+func (m *Mutant) Diff(src []byte) string {
+	mutated, err := m.Apply(src)
+	if err != nil {
+		return err.Error()
+	}
+	oldLine := strings.Split(string(src), "\n")[m.Line-1]
+	newLine := strings.Split(string(mutated), "\n")[m.Line-1]
+	return fmt.Sprintf("--- a/%s\n+++ b/%s\n@@ -%d +%d @@\n-%s\n+%s\n", m.File, m.File, m.Line, m.Line, oldLine, newLine)
+}
+
+// swaps lists the operator replacements. Every operator maps to the
+// mutations that are most likely to slip past a weak test.
+var swaps = map[token.Token][]token.Token{
+	token.ADD:        {token.SUB},
+	token.SUB:        {token.ADD},
+	token.MUL:        {token.QUO},
+	token.QUO:        {token.MUL},
+	token.REM:        {token.MUL},
+	token.EQL:        {token.NEQ},
+	token.NEQ:        {token.EQL},
+	token.LSS:        {token.LEQ, token.GEQ},
+	token.LEQ:        {token.LSS, token.GTR},
+	token.GTR:        {token.GEQ, token.LEQ},
+	token.GEQ:        {token.GTR, token.LSS},
+	token.LAND:       {token.LOR},
+	token.LOR:        {token.LAND},
+	token.INC:        {token.DEC},
+	token.DEC:        {token.INC},
+	token.ADD_ASSIGN: {token.SUB_ASSIGN},
+	token.SUB_ASSIGN: {token.ADD_ASSIGN},
+}
+
+// FindFile returns the mutants of one file. rel is the name used in
+// reports.
+func FindFile(path, rel string) ([]*Mutant, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*Mutant
+	add := func(pos token.Pos, old, new, desc string) {
+		p := fset.Position(pos)
+		out = append(out, &Mutant{File: rel, Line: p.Line, Col: p.Column, Desc: desc, offset: p.Offset, old: old, new: new})
+	}
+	opSwap := func(pos token.Pos, op token.Token) {
+		for _, to := range swaps[op] {
+			add(pos, op.String(), to.String(), fmt.Sprintf("%q -> %q", op, to))
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			// Constant expressions such as array lengths must stay
+			// constant, and mutating them rarely says anything about tests.
+			return n.Tok != token.CONST
+		case *ast.ArrayType:
+			return false
+		case *ast.BinaryExpr:
+			// String concatenation has no "-" counterpart.
+			if n.Op == token.ADD && (isStringLit(n.X) || isStringLit(n.Y)) {
+				return true
+			}
+			opSwap(n.OpPos, n.Op)
+		case *ast.IncDecStmt:
+			opSwap(n.TokPos, n.Tok)
+		case *ast.AssignStmt:
+			if n.Tok == token.ADD_ASSIGN || n.Tok == token.SUB_ASSIGN {
+				opSwap(n.TokPos, n.Tok)
+			}
+			// Writes through a pointer are dropped by assigning the value
+			// to the blank identifier instead, which keeps the right-hand
+			// side (and its variables) in use so the mutant still compiles.
+			if n.Tok == token.ASSIGN && len(n.Lhs) == 1 {
+				if star, ok := n.Lhs[0].(*ast.StarExpr); ok {
+					old := string(src[fset.Position(star.Pos()).Offset:fset.Position(star.End()).Offset])
+					add(star.Pos(), old, "_", fmt.Sprintf("drop assignment to %s", old))
+				}
+			}
+		case *ast.Ident:
+			if n.Name == "true" || n.Name == "false" {
+				to := map[string]string{"true": "false", "false": "true"}[n.Name]
+				add(n.Pos(), n.Name, to, fmt.Sprintf("%s -> %s", n.Name, to))
+			}
+		}
+		return true
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].offset < out[j].offset })
+	return out, nil
+}
+
+func isStringLit(e ast.Expr) bool {
+	lit, ok := e.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING
+}
@@ -0,0 +1,351 @@
+package mutate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of testing one mutant.
+type Status int
+
+const (
+	// Killed means the tests failed, i.e. they noticed the mutation.
+	Killed Status = iota
+	// Survived means the tests still passed.
+	Survived
+	// TimedOut means the tests did not finish in time; it counts as killed,
+	// since mutations often turn loops into endless ones.
+	TimedOut
+	// Invalid means the mutant did not compile. It is left out of the score.
+	Invalid
+)
+
+func (s Status) String() string {
+	switch s {
+	case Killed:
+		return "killed"
+	case Survived:
+		return "survived"
+	case TimedOut:
+		return "timed out"
+	case Invalid:
+		return "invalid"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Options configures Run.
+type Options struct {
+	// Dir is where the package patterns are resolved; "" means the current
+	// directory.
+	Dir string
+	// Patterns are go package patterns such as "./..."; none means ".".
+	Patterns []string
+	// Workers is the number of mutants tested at once, each in its own copy
+	// of the module (default: GOMAXPROCS).
+	Workers int
+	// Timeout bounds the test run of a single mutant (default 1 minute).
+	Timeout time.Duration
+	// Progress, if set, receives one line per finished mutant.
+	Progress io.Writer
+}
+
+// Result is the outcome for one mutant.
+type Result struct {
+	Mutant *Mutant
+	Status Status
+	// Diff shows the mutated line.
+	Diff string
+	// Output is the go test output, kept for killed and invalid mutants.
+	Output string
+}
+
+// Report is the outcome of a whole run.
+type Report struct {
+	Results []Result
+	// NoTests lists packages that have mutants but no test files. Their
+	// mutants are reported as survived without running anything.
+	NoTests []string
+}
+
+// Count returns the number of results with status s.
+func (r *Report) Count(s Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Score is the share of valid mutants the tests killed, from 0 to 1.
+func (r *Report) Score() float64 {
+	killed := r.Count(Killed) + r.Count(TimedOut)
+	valid := killed + r.Count(Survived)
+	if valid == 0 {
+		return 0
+	}
+	return float64(killed) / float64(valid)
+}
+
+// listedPackage is the part of `go list -json` output Run needs.
+type listedPackage struct {
+	ImportPath   string
+	Dir          string
+	GoFiles      []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+	Module       *struct{ Dir string }
+}
+
+// Run finds the mutants of every package matched by the patterns and tests
+// each of them.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if len(opts.Patterns) == 0 {
+		opts.Patterns = []string{"."}
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Minute
+	}
+
+	pkgs, err := listPackages(ctx, opts.Dir, opts.Patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.New("mutate: no packages matched")
+	}
+	if pkgs[0].Module == nil {
+		return nil, errors.New("mutate: packages must belong to a module")
+	}
+	root := pkgs[0].Module.Dir
+
+	report := &Report{}
+	var mutants []*Mutant
+	var importPaths []string // packages with tests
+	noTests := make(map[string]bool)
+	for _, p := range pkgs {
+		before := len(mutants)
+		for _, name := range p.GoFiles {
+			path := filepath.Join(p.Dir, name)
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil, err
+			}
+			found, err := FindFile(path, filepath.ToSlash(rel))
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range found {
+				m.Package = p.ImportPath
+			}
+			mutants = append(mutants, found...)
+		}
+		switch {
+		case len(p.TestGoFiles)+len(p.XTestGoFiles) > 0:
+			importPaths = append(importPaths, p.ImportPath)
+		case len(mutants) > before:
+			report.NoTests = append(report.NoTests, p.ImportPath)
+			noTests[p.ImportPath] = true
+		}
+	}
+	for i, m := range mutants {
+		m.ID = i + 1
+	}
+
+	// Mutation testing only makes sense on top of a passing test suite.
+	if len(importPaths) > 0 {
+		if out, err := goTest(ctx, root, importPaths, 0); err != nil {
+			return nil, fmt.Errorf("mutate: tests fail without any mutation:\n%s", out)
+		}
+	}
+
+	// No test can kill a mutant in a package without tests, so only the
+	// others are worth a go test run each.
+	results := make([]Result, len(mutants))
+	var pending []int
+	for i, m := range mutants {
+		if !noTests[m.Package] {
+			pending = append(pending, i)
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(m.File)))
+		if err != nil {
+			return nil, err
+		}
+		results[i] = Result{Mutant: m, Status: Survived, Diff: m.Diff(src)}
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex // guards Progress and the first worker error
+	var workerErr error
+	for w := 0; w < min(opts.Workers, len(pending)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dir, err := os.MkdirTemp("", "mutate-*")
+			if err == nil {
+				defer os.RemoveAll(dir)
+				err = copyTree(root, dir)
+			}
+			if err != nil {
+				mu.Lock()
+				workerErr = errors.Join(workerErr, err)
+				mu.Unlock()
+				for range jobs {
+					// Drain so the other workers get everything.
+				}
+				return
+			}
+			for i := range jobs {
+				res, err := testMutant(ctx, dir, mutants[i], opts.Timeout)
+				mu.Lock()
+				if err != nil {
+					workerErr = errors.Join(workerErr, err)
+				} else if opts.Progress != nil {
+					fmt.Fprintf(opts.Progress, "[%d/%d] %s:%d: %s: %s\n", mutants[i].ID, len(mutants), mutants[i].File, mutants[i].Line, mutants[i].Desc, res.Status)
+				}
+				mu.Unlock()
+				results[i] = res
+			}
+		}()
+	}
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if workerErr != nil {
+		return nil, workerErr
+	}
+	report.Results = results
+	return report, nil
+}
+
+// testMutant applies m inside the module copy at dir, runs the package's
+// tests and puts the original file back.
+func testMutant(ctx context.Context, dir string, m *Mutant, timeout time.Duration) (Result, error) {
+	path := filepath.Join(dir, filepath.FromSlash(m.File))
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+	mutated, err := m.Apply(src)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := os.WriteFile(path, mutated, 0o644); err != nil {
+		return Result{}, err
+	}
+	defer os.WriteFile(path, src, 0o644)
+
+	res := Result{Mutant: m, Diff: m.Diff(src)}
+	out, err := goTest(ctx, dir, []string{m.Package}, timeout)
+	switch {
+	case err == nil:
+		res.Status = Survived
+	case errors.Is(err, context.DeadlineExceeded):
+		res.Status = TimedOut
+	case strings.Contains(out, "[build failed]") || strings.Contains(out, "[setup failed]"):
+		res.Status = Invalid
+		res.Output = out
+	default:
+		res.Status = Killed
+		res.Output = out
+	}
+	return res, nil
+}
+
+func goTest(ctx context.Context, dir string, pkgs []string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	args := append([]string{"test", "-count=1", "-failfast"}, pkgs...)
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return out.String(), ctx.Err()
+	}
+	return out.String(), err
+}
+
+func listPackages(ctx context.Context, dir string, patterns []string) ([]listedPackage, error) {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"list", "-json"}, patterns...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("mutate: go list: %v\n%s", err, stderr.String())
+	}
+	var pkgs []listedPackage
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var p listedPackage
+		if err := dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].ImportPath < pkgs[j].ImportPath })
+	return pkgs, nil
+}
+
+// copyTree copies the module at src into dst, skipping hidden directories
+// such as .git.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), data, 0o644)
+	})
+}
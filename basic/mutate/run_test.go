@@ -0,0 +1,117 @@
+package mutate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeModule creates a module from name -> contents pairs and returns its
+// directory.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFindFile(t *testing.T) {
+	dir := writeModule(t, map[string]string{"a.go": `package a
+
+const n = 1 + 2
+
+func f(x *int, s string) bool {
+	*x = *x + 1
+	for i := 0; i < 3; i++ {
+	}
+	return s+"!" == "a!" && true
+}
+`})
+	found, err := FindFile(filepath.Join(dir, "a.go"), "a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range found {
+		got = append(got, m.Desc)
+	}
+	want := []string{
+		"drop assignment to *x",
+		`"+" -> "-"`,
+		`"<" -> "<="`,
+		`"<" -> ">="`,
+		`"++" -> "--"`,
+		`"==" -> "!="`,
+		`"&&" -> "||"`,
+		"true -> false",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	src, _ := os.ReadFile(filepath.Join(dir, "a.go"))
+	if diff := found[0].Diff(src); diff != "--- a/a.go\n+++ b/a.go\n@@ -6 +6 @@\n-\t*x = *x + 1\n+\t_ = *x + 1\n" {
+		t.Errorf("diff:\n%s", diff)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs go test once per mutant")
+	}
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.21\n",
+		"tested/add.go": `package tested
+
+func Add(a, b int) int { return a + b }
+`,
+		"tested/add_test.go": `package tested
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(2, 3) != 5 {
+		t.Fatal("2 + 3 != 5")
+	}
+}
+`,
+		"untested/sub.go": `package untested
+
+func Sub(a, b int) int { return a - b }
+`,
+	})
+	r, err := Run(context.Background(), Options{Dir: dir, Patterns: []string{"./..."}, Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(r.NoTests, []string{"example.com/m/untested"}) {
+		t.Errorf("NoTests = %q", r.NoTests)
+	}
+	if len(r.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(r.Results))
+	}
+	for _, res := range r.Results {
+		want := Killed
+		if res.Mutant.Package == "example.com/m/untested" {
+			want = Survived
+			if res.Output != "" || res.Diff == "" {
+				t.Errorf("untested mutant: output %q, diff %q", res.Output, res.Diff)
+			}
+		}
+		if res.Status != want {
+			t.Errorf("%s %s: %s, want %s", res.Mutant.File, res.Mutant.Desc, res.Status, want)
+		}
+	}
+	if s := r.Score(); s != 0.5 {
+		t.Errorf("score %v, want 0.5", s)
+	}
+}
@@ -0,0 +1,139 @@
+package quick
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SeedEnv names the environment variable that fixes the seed of every
+// check, for replaying a reported failure.
+const SeedEnv = "QUICK_SEED"
+
+// Config controls a check. The zero value is usable.
+type Config struct {
+	// Seed makes the runs reproducible. Zero means QUICK_SEED if set, or a
+	// random seed otherwise.
+	Seed uint64
+	// Runs is the number of random inputs to try (default 100).
+	Runs int
+	// MaxSize is the size passed to the generator on the last run; sizes
+	// grow linearly from 1 (default 64).
+	MaxSize int
+	// MaxShrinks bounds the number of successful shrink steps (default
+	// 1000).
+	MaxShrinks int
+}
+
+func (c *Config) withDefaults() Config {
+	var out Config
+	if c != nil {
+		out = *c
+	}
+	if out.Seed == 0 {
+		if s, err := strconv.ParseUint(os.Getenv(SeedEnv), 10, 64); err == nil {
+			out.Seed = s
+		} else {
+			out.Seed = uint64(time.Now().UnixNano())
+		}
+	}
+	if out.Runs <= 0 {
+		out.Runs = 100
+	}
+	if out.MaxSize <= 0 {
+		out.MaxSize = 64
+	}
+	if out.MaxShrinks <= 0 {
+		out.MaxShrinks = 1000
+	}
+	return out
+}
+
+// Failure describes a falsified property.
+type Failure[T any] struct {
+	Seed     uint64
+	Run      int // 1-based index of the failing run
+	Original T   // the input that first failed
+	Minimal  T   // the input after shrinking
+	Shrinks  int // shrink steps taken
+	// Err is the panic or error raised by the property on the minimal
+	// input, or nil if it just returned false.
+	Err error
+}
+
+func (f *Failure[T]) Error() string {
+	msg := fmt.Sprintf("property falsified after %d runs (%d shrinks)\n  minimal: %#v\n  original: %#v",
+		f.Run, f.Shrinks, f.Minimal, f.Original)
+	if f.Err != nil {
+		msg += "\n  error: " + f.Err.Error()
+	}
+	return msg + fmt.Sprintf("\n  replay with %s=%d", SeedEnv, f.Seed)
+}
+
+// Check runs prop against random inputs from g. It returns nil if every run
+// passed and the shrunk counterexample otherwise. A panic inside prop counts
+// as a failure.
+func Check[T any](g Gen[T], prop func(T) bool, cfg *Config) *Failure[T] {
+	c := cfg.withDefaults()
+	r := rand.New(rand.NewPCG(c.Seed, 0x9E3779B97F4A7C15))
+	for run := 1; run <= c.Runs; run++ {
+		size := 1 + (run-1)*(c.MaxSize-1)/max(c.Runs-1, 1)
+		t := g(r, size)
+		ok, err := holds(prop, t.Value)
+		if ok {
+			continue
+		}
+		f := &Failure[T]{Seed: c.Seed, Run: run, Original: t.Value, Err: err}
+		t, f.Shrinks, f.Err = shrink(t, prop, err, c.MaxShrinks)
+		f.Minimal = t.Value
+		return f
+	}
+	return nil
+}
+
+// shrink walks the shrink tree greedily: it moves to the first candidate
+// that still fails and repeats until no candidate fails.
+func shrink[T any](t Tree[T], prop func(T) bool, err error, limit int) (Tree[T], int, error) {
+	steps := 0
+	for steps < limit {
+		progressed := false
+		for _, c := range t.Shrinks() {
+			if ok, cerr := holds(prop, c.Value); !ok {
+				t, err = c, cerr
+				steps++
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+	return t, steps, err
+}
+
+func holds[T any](prop func(T) bool, v T) (ok bool, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			ok, err = false, fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return prop(v), nil
+}
+
+// TB is the part of testing.TB that ForAll needs.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// ForAll checks prop with the default Config and fails t with the minimal
+// counterexample and the replay seed.
+func ForAll[T any](t TB, g Gen[T], prop func(T) bool) {
+	t.Helper()
+	if f := Check(g, prop, nil); f != nil {
+		t.Fatalf("%v", f)
+	}
+}
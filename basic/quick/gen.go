@@ -0,0 +1,310 @@
+// Package quick is a small property-based testing library in the spirit of
+// testing/quick, with composable generators and automatic shrinking.
+//
+// A property is a function that should hold for every input. Check feeds it
+// random inputs from a generator; when one fails, the input is shrunk to a
+// minimal counterexample before it is reported, together with the seed that
+// replays the run:
+//
+//	quick.ForAll(t, quick.Zip(quick.Int(), quick.Int()), func(p quick.Pair[int, int]) bool {
+//		return sum(p.A, p.B) == sum(p.B, p.A)
+//	})
+//
+// Generators produce shrink trees rather than bare values: every value
+// carries a lazy list of smaller candidates. Combinators such as Map and
+// SliceOf transform whole trees, so shrinking keeps working through them.
+package quick
+
+import (
+	"math"
+	"math/rand/v2"
+	"unicode/utf8"
+)
+
+// Tree is a generated value together with its shrink candidates, simplest
+// first.
+type Tree[T any] struct {
+	Value   T
+	shrinks func() []Tree[T]
+}
+
+// Shrinks returns the candidates for a smaller counterexample.
+func (t Tree[T]) Shrinks() []Tree[T] {
+	if t.shrinks == nil {
+		return nil
+	}
+	return t.shrinks()
+}
+
+// Leaf returns a tree that cannot be shrunk.
+func Leaf[T any](v T) Tree[T] {
+	return Tree[T]{Value: v}
+}
+
+// Gen generates random shrink trees. size grows over the runs of a check,
+// so early runs try small values and later runs larger ones.
+type Gen[T any] func(r *rand.Rand, size int) Tree[T]
+
+// Map applies f to every value a generator produces, shrinks included.
+func Map[T, U any](g Gen[T], f func(T) U) Gen[U] {
+	return func(r *rand.Rand, size int) Tree[U] {
+		return mapTree(g(r, size), f)
+	}
+}
+
+func mapTree[T, U any](t Tree[T], f func(T) U) Tree[U] {
+	return Tree[U]{
+		Value: f(t.Value),
+		shrinks: func() []Tree[U] {
+			src := t.Shrinks()
+			out := make([]Tree[U], len(src))
+			for i, s := range src {
+				out[i] = mapTree(s, f)
+			}
+			return out
+		},
+	}
+}
+
+// Filter keeps only values for which keep returns true, both when
+// generating and when shrinking. It gives up after 100 rejected attempts in
+// a row and panics, since that usually means the filter is too strict.
+func Filter[T any](g Gen[T], keep func(T) bool) Gen[T] {
+	return func(r *rand.Rand, size int) Tree[T] {
+		for i := 0; i < 100; i++ {
+			if t := g(r, size); keep(t.Value) {
+				return filterTree(t, keep)
+			}
+		}
+		panic("quick: Filter rejected 100 values in a row")
+	}
+}
+
+func filterTree[T any](t Tree[T], keep func(T) bool) Tree[T] {
+	return Tree[T]{
+		Value: t.Value,
+		shrinks: func() []Tree[T] {
+			var out []Tree[T]
+			for _, s := range t.Shrinks() {
+				if keep(s.Value) {
+					out = append(out, filterTree(s, keep))
+				}
+			}
+			return out
+		},
+	}
+}
+
+// Const always generates v.
+func Const[T any](v T) Gen[T] {
+	return func(*rand.Rand, int) Tree[T] { return Leaf(v) }
+}
+
+// OneOf picks one of the generators at random for every value.
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	return func(r *rand.Rand, size int) Tree[T] {
+		return gens[r.IntN(len(gens))](r, size)
+	}
+}
+
+// Bool generates booleans, shrinking true to false.
+func Bool() Gen[bool] {
+	return func(r *rand.Rand, _ int) Tree[bool] {
+		if r.IntN(2) == 0 {
+			return Leaf(false)
+		}
+		return Tree[bool]{Value: true, shrinks: func() []Tree[bool] { return []Tree[bool]{Leaf(false)} }}
+	}
+}
+
+// Int generates ints of any magnitude, shrinking towards zero. The range
+// grows with size; the extremes math.MinInt, math.MaxInt and their
+// neighbours are mixed in deliberately, because overflow bugs hide there.
+func Int() Gen[int] {
+	edges := []int{0, 1, -1, math.MaxInt, math.MinInt, math.MaxInt - 1, math.MinInt + 1}
+	return func(r *rand.Rand, size int) Tree[int] {
+		if r.IntN(10) == 0 {
+			return intTree(edges[r.IntN(len(edges))], 0)
+		}
+		bits := min(max(size, 1), 63)
+		v := int(r.Uint64N(1 << bits))
+		if r.IntN(2) == 0 {
+			v = -v
+		}
+		return intTree(v, 0)
+	}
+}
+
+// IntRange generates ints in [lo, hi], shrinking towards the value in the
+// range closest to zero.
+func IntRange(lo, hi int) Gen[int] {
+	if lo > hi {
+		panic("quick: IntRange with lo > hi")
+	}
+	target := min(max(0, lo), hi)
+	return func(r *rand.Rand, _ int) Tree[int] {
+		span := uint64(hi) - uint64(lo)
+		var off uint64
+		if span == math.MaxUint64 {
+			off = r.Uint64()
+		} else {
+			off = r.Uint64N(span + 1)
+		}
+		return intTree(int(uint64(lo)+off), target)
+	}
+}
+
+// intTree shrinks v towards target: first target itself, then values that
+// close the remaining distance by halves, which gives a binary search for
+// the boundary where a property starts failing.
+func intTree(v, target int) Tree[int] {
+	return Tree[int]{
+		Value: v,
+		shrinks: func() []Tree[int] {
+			if v == target {
+				return nil
+			}
+			out := []Tree[int]{intTree(target, target)}
+			// Work with the distance as uint64 so that MinInt - 0 does not
+			// overflow.
+			neg := v < target
+			dist := uint64(v) - uint64(target)
+			if neg {
+				dist = uint64(target) - uint64(v)
+			}
+			for d := dist / 2; d > 0; d /= 2 {
+				c := uint64(v) - d
+				if neg {
+					c = uint64(v) + d
+				}
+				out = append(out, intTree(int(c), target))
+			}
+			return out
+		},
+	}
+}
+
+// Rune generates mostly printable ASCII with occasional runes from the
+// rest of Unicode, shrinking towards 'a'.
+func Rune() Gen[rune] {
+	return func(r *rand.Rand, _ int) Tree[rune] {
+		var c rune
+		if r.IntN(4) == 0 {
+			for {
+				c = rune(r.IntN(0x10FFFF + 1))
+				if utf8.ValidRune(c) {
+					break
+				}
+			}
+		} else {
+			c = rune(' ' + r.IntN('~'-' '+1))
+		}
+		return runeTree(c)
+	}
+}
+
+func runeTree(c rune) Tree[rune] {
+	return Tree[rune]{
+		Value: c,
+		shrinks: func() []Tree[rune] {
+			if c == 'a' {
+				return nil
+			}
+			out := []Tree[rune]{Leaf('a')}
+			if c > 0x7F {
+				out = append(out, Leaf('z'))
+			}
+			return out
+		},
+	}
+}
+
+// String generates strings of up to size runes, shrinking towards "".
+func String() Gen[string] {
+	return Map(SliceOf(Rune()), func(rs []rune) string { return string(rs) })
+}
+
+// SliceOf generates slices of up to size elements. Shrinking first removes
+// elements (halves, then single elements) and then shrinks the elements
+// that remain.
+func SliceOf[T any](g Gen[T]) Gen[[]T] {
+	return func(r *rand.Rand, size int) Tree[[]T] {
+		n := r.IntN(max(size, 0) + 1)
+		elems := make([]Tree[T], n)
+		for i := range elems {
+			elems[i] = g(r, size)
+		}
+		return sliceTree(elems)
+	}
+}
+
+func sliceTree[T any](elems []Tree[T]) Tree[[]T] {
+	vals := make([]T, len(elems))
+	for i, e := range elems {
+		vals[i] = e.Value
+	}
+	return Tree[[]T]{
+		Value: vals,
+		shrinks: func() []Tree[[]T] {
+			var out []Tree[[]T]
+			// Drop chunks of decreasing size.
+			for chunk := len(elems); chunk > 0; chunk /= 2 {
+				for start := 0; start+chunk <= len(elems); start += chunk {
+					rest := append(append([]Tree[T]{}, elems[:start]...), elems[start+chunk:]...)
+					out = append(out, sliceTree(rest))
+				}
+			}
+			// Shrink one element at a time.
+			for i, e := range elems {
+				for _, s := range e.Shrinks() {
+					next := append([]Tree[T]{}, elems...)
+					next[i] = s
+					out = append(out, sliceTree(next))
+				}
+			}
+			return out
+		},
+	}
+}
+
+// Pair holds two generated values.
+type Pair[A, B any] struct {
+	A A
+	B B
+}
+
+// Zip combines two generators. Shrinking tries the first component before
+// the second.
+func Zip[A, B any](ga Gen[A], gb Gen[B]) Gen[Pair[A, B]] {
+	return func(r *rand.Rand, size int) Tree[Pair[A, B]] {
+		return pairTree(ga(r, size), gb(r, size))
+	}
+}
+
+func pairTree[A, B any](a Tree[A], b Tree[B]) Tree[Pair[A, B]] {
+	return Tree[Pair[A, B]]{
+		Value: Pair[A, B]{a.Value, b.Value},
+		shrinks: func() []Tree[Pair[A, B]] {
+			var out []Tree[Pair[A, B]]
+			for _, s := range a.Shrinks() {
+				out = append(out, pairTree(s, b))
+			}
+			for _, s := range b.Shrinks() {
+				out = append(out, pairTree(a, s))
+			}
+			return out
+		},
+	}
+}
+
+// MapOf generates maps of up to size entries. Entries with duplicate keys
+// collapse, so maps may be smaller than the number of generated pairs.
+func MapOf[K comparable, V any](gk Gen[K], gv Gen[V]) Gen[map[K]V] {
+	return Map(SliceOf(Zip(gk, gv)), func(entries []Pair[K, V]) map[K]V {
+		m := make(map[K]V, len(entries))
+		for _, e := range entries {
+			m[e.A] = e.B
+		}
+		return m
+	})
+}
@@ -0,0 +1,125 @@
+package quick
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestShrinkSortedSlice falsifies "every slice is sorted". Whatever the
+// random slice that first fails, shrinking must end at the smallest
+// unsorted slice of non-negative ints.
+func TestShrinkSortedSlice(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		f := Check(SliceOf(IntRange(0, 1000)), slices.IsSorted, &Config{Seed: seed})
+		if f == nil {
+			t.Fatalf("seed %d: no counterexample found", seed)
+		}
+		if !slices.Equal(f.Minimal, []int{1, 0}) {
+			t.Errorf("seed %d: shrunk %v to %v, want [1 0]", seed, f.Original, f.Minimal)
+		}
+	}
+}
+
+// TestShrinkIntBoundary checks that shrinking an int binary-searches for
+// the exact point where the property starts failing.
+func TestShrinkIntBoundary(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		f := Check(Int(), func(x int) bool { return x < 1000 }, &Config{Seed: seed})
+		if f == nil {
+			t.Fatalf("seed %d: no counterexample found", seed)
+		}
+		if f.Minimal != 1000 {
+			t.Errorf("seed %d: shrunk %d to %d, want 1000", seed, f.Original, f.Minimal)
+		}
+	}
+}
+
+func TestShrinkThroughCombinators(t *testing.T) {
+	// Map and Zip must keep shrinking: the smallest pair of strings whose
+	// lengths add up to at least 3.
+	g := Zip(String(), Map(IntRange(0, 50), func(n int) string { return strings.Repeat("x", n) }))
+	f := Check(g, func(p Pair[string, string]) bool { return len(p.A)+len(p.B) < 3 }, &Config{Seed: 7})
+	if f == nil {
+		t.Fatal("no counterexample found")
+	}
+	if len(f.Minimal.A)+len(f.Minimal.B) != 3 {
+		t.Errorf("shrunk to %q, want lengths adding up to 3", f.Minimal)
+	}
+}
+
+func TestPanicIsFailure(t *testing.T) {
+	// An off-by-one that only bites once the slice has three elements.
+	f := Check(SliceOf(Int()), func(s []int) bool { return len(s) < 3 || s[len(s)] == 0 }, &Config{Seed: 1})
+	if f == nil {
+		t.Fatal("a panicking property passed")
+	}
+	if f.Err == nil || !strings.Contains(f.Err.Error(), "index out of range") {
+		t.Errorf("Err = %v, want the panic", f.Err)
+	}
+	if !slices.Equal(f.Minimal, []int{0, 0, 0}) {
+		t.Errorf("shrunk to %v, want [0 0 0]", f.Minimal)
+	}
+}
+
+func TestPassingProperty(t *testing.T) {
+	runs := 0
+	f := Check(Int(), func(int) bool { runs++; return true }, &Config{Seed: 1, Runs: 50})
+	if f != nil {
+		t.Errorf("a true property failed: %v", f)
+	}
+	if runs != 50 {
+		t.Errorf("the property ran %d times, want 50", runs)
+	}
+}
+
+func TestSeedReplay(t *testing.T) {
+	prop := func(s []int) bool { return len(s) < 5 }
+	first := Check(SliceOf(Int()), prop, &Config{Seed: 42})
+	again := Check(SliceOf(Int()), prop, &Config{Seed: 42})
+	if first == nil || again == nil {
+		t.Fatal("no counterexample found")
+	}
+	if !slices.Equal(first.Original, again.Original) || first.Run != again.Run {
+		t.Errorf("the same seed gave %v in run %d and %v in run %d", first.Original, first.Run, again.Original, again.Run)
+	}
+
+	t.Setenv(SeedEnv, "42")
+	env := Check(SliceOf(Int()), prop, nil)
+	if env == nil || env.Seed != 42 || !slices.Equal(env.Original, first.Original) {
+		t.Errorf("%s=42 did not replay the run: %v", SeedEnv, env)
+	}
+	if !strings.Contains(env.Error(), SeedEnv+"=42") {
+		t.Errorf("the report does not say how to replay it:\n%v", env)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	ForAll(t, IntRange(-5, 5), func(x int) bool { return -5 <= x && x <= 5 })
+	ForAll(t, IntRange(10, 20), func(x int) bool { return 10 <= x && x <= 20 })
+	ForAll(t, Filter(Int(), func(x int) bool { return x%2 == 0 }), func(x int) bool { return x%2 == 0 })
+	ForAll(t, String(), func(s string) bool { return strings.ToValidUTF8(s, "") == s })
+	ForAll(t, MapOf(String(), Bool()), func(m map[string]bool) bool { return len(m) <= 64 })
+	ForAll(t, OneOf(Const(1), Const(2)), func(x int) bool { return x == 1 || x == 2 })
+}
+
+// fakeTB records what ForAll reports.
+type fakeTB struct{ msg string }
+
+func (*fakeTB) Helper() {}
+
+func (f *fakeTB) Fatalf(format string, args ...any) { f.msg = fmt.Sprintf(format, args...) }
+
+func TestForAll(t *testing.T) {
+	var tb fakeTB
+	ForAll(&tb, SliceOf(IntRange(0, 100)), slices.IsSorted)
+	if !strings.Contains(tb.msg, "minimal: []int{1, 0}") {
+		t.Errorf("ForAll reported:\n%s", tb.msg)
+	}
+	tb.msg = ""
+	ForAll(&tb, Int(), func(int) bool { return true })
+	if tb.msg != "" {
+		t.Errorf("a true property failed:\n%s", tb.msg)
+	}
+}
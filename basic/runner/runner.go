@@ -0,0 +1,277 @@
+// Package runner runs lessons, the small demo functions of the module, and
+// accounts for the memory each one uses:
+//
+//   - heap allocations, bytes allocated and GC cycles, read from
+//     runtime/metrics before and after the lesson
+//   - the average allocations per run from testing.AllocsPerRun, which
+//     repeats the lesson with its output discarded
+//   - optionally, which source lines allocated, from a memory profile
+//     recorded at a rate of one sample per allocation
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/metrics"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Lesson is a named demo.
+type Lesson struct {
+	Name string
+	Run  func()
+}
+
+// Options says what to measure besides the runtime/metrics counters.
+type Options struct {
+	// Runs is how often testing.AllocsPerRun repeats the lesson; 0 skips it.
+	Runs int
+	// Lines attributes allocations to source lines with a memory profile.
+	Lines bool
+}
+
+// Report is the accounting for one lesson run.
+type Report struct {
+	Name string
+	// Allocs and Bytes count the heap objects and bytes allocated.
+	Allocs, Bytes uint64
+	// GCCycles is the number of garbage collections that finished.
+	GCCycles uint64
+	Elapsed  time.Duration
+	// Runs and AllocsPerRun are the repetitions and result of
+	// testing.AllocsPerRun; Runs is 0 when it was skipped.
+	Runs         int
+	AllocsPerRun float64
+	// Lines lists the allocating lines of the lesson's package, most bytes
+	// first, when Options.Lines was set.
+	Lines []Line
+}
+
+// Line is the allocation total of one source line.
+type Line struct {
+	File   string
+	Line   int
+	Func   string
+	Allocs int64
+	Bytes  int64
+}
+
+var metricNames = []string{
+	"/gc/heap/allocs:objects",
+	"/gc/heap/allocs:bytes",
+	"/gc/cycles/total:gc-cycles",
+}
+
+func readMetrics() [3]uint64 {
+	// The allocation counters are kept per P and only added up when a
+	// cache is flushed; ReadMemStats flushes them all, so the totals are
+	// exact and small lessons do not read as zero.
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	samples := make([]metrics.Sample, len(metricNames))
+	for i, name := range metricNames {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	var out [3]uint64
+	for i, s := range samples {
+		if s.Value.Kind() == metrics.KindUint64 {
+			out[i] = s.Value.Uint64()
+		}
+	}
+	return out
+}
+
+// Measure runs the lesson once, and again Runs times if asked, and
+// reports what it cost. The runtime counters are process-wide, so other
+// goroutines allocating at the same time are counted too.
+func Measure(l Lesson, opts Options) Report {
+	r := Report{Name: l.Name}
+	var before map[[32]uintptr]profileEntry
+	if opts.Lines {
+		defer func(rate int) { runtime.MemProfileRate = rate }(runtime.MemProfileRate)
+		runtime.MemProfileRate = 1
+		// The profile only includes allocations up to the last GC.
+		runtime.GC()
+		before = memProfile()
+	}
+
+	m0 := readMetrics()
+	start := time.Now()
+	l.Run()
+	r.Elapsed = time.Since(start)
+	m1 := readMetrics()
+	r.Allocs, r.Bytes, r.GCCycles = m1[0]-m0[0], m1[1]-m0[1], m1[2]-m0[2]
+
+	if opts.Lines {
+		runtime.GC()
+		r.Lines = attribute(before, memProfile(), funcName(l.Run))
+	}
+	if opts.Runs > 0 {
+		r.Runs = opts.Runs
+		r.AllocsPerRun = silently(func() float64 { return testing.AllocsPerRun(opts.Runs, l.Run) })
+	}
+	return r
+}
+
+// Run measures every lesson in order.
+func Run(lessons []Lesson, opts Options) []Report {
+	reports := make([]Report, 0, len(lessons))
+	for _, l := range lessons {
+		reports = append(reports, Measure(l, opts))
+	}
+	return reports
+}
+
+// silently calls fn with os.Stdout pointing at the null device, so
+// repeated runs of a lesson do not repeat its output.
+func silently[T any](fn func() T) T {
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fn()
+	}
+	defer null.Close()
+	stdout := os.Stdout
+	os.Stdout = null
+	defer func() { os.Stdout = stdout }()
+	return fn()
+}
+
+type profileEntry struct {
+	allocs, bytes int64
+}
+
+func memProfile() map[[32]uintptr]profileEntry {
+	var records []runtime.MemProfileRecord
+	n, _ := runtime.MemProfile(nil, true)
+	for {
+		records = make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		if n, ok = runtime.MemProfile(records, true); ok {
+			records = records[:n]
+			break
+		}
+	}
+	out := make(map[[32]uintptr]profileEntry, len(records))
+	for _, r := range records {
+		out[r.Stack0] = profileEntry{r.AllocObjects, r.AllocBytes}
+	}
+	return out
+}
+
+func funcName(fn func()) string {
+	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+}
+
+// packageOf returns the package prefix of a function's symbol name, e.g.
+// "main." for main.collections.
+func packageOf(name string) string {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return name
+	}
+	return name[:slash+1+dot+1]
+}
+
+// attribute charges what each stack through the lesson function allocated
+// between the two profiles to the innermost frame in the lesson's
+// package: the line of the lesson (or of a helper it calls) that led to
+// the allocation, even when fmt or append did the allocating.
+func attribute(before, after map[[32]uintptr]profileEntry, lesson string) []Line {
+	pkg := packageOf(lesson)
+	type key struct {
+		file string
+		line int
+	}
+	totals := map[key]*Line{}
+	for stack, e := range after {
+		prev := before[stack]
+		allocs, bytes := e.allocs-prev.allocs, e.bytes-prev.bytes
+		if allocs <= 0 {
+			continue
+		}
+		n := slices.Index(stack[:], 0)
+		if n < 0 {
+			n = len(stack)
+		}
+		var at *runtime.Frame
+		inLesson := false
+		frames := runtime.CallersFrames(stack[:n])
+		for {
+			f, more := frames.Next()
+			if at == nil && strings.HasPrefix(f.Function, pkg) {
+				at = &f
+			}
+			if f.Function == lesson {
+				inLesson = true
+				break
+			}
+			if !more {
+				break
+			}
+		}
+		if at == nil || !inLesson {
+			continue
+		}
+		k := key{at.File, at.Line}
+		if totals[k] == nil {
+			totals[k] = &Line{File: at.File, Line: at.Line, Func: at.Function}
+		}
+		totals[k].Allocs += allocs
+		totals[k].Bytes += bytes
+	}
+	lines := make([]Line, 0, len(totals))
+	for _, l := range totals {
+		lines = append(lines, *l)
+	}
+	slices.SortFunc(lines, func(a, b Line) int {
+		if a.Bytes != b.Bytes {
+			return int(b.Bytes - a.Bytes)
+		}
+		return a.Line - b.Line
+	})
+	return lines
+}
+
+// WriteReport writes a table of the reports and, for reports with line
+// attribution, the top allocating lines with their source text.
+func WriteReport(w io.Writer, reports []Report, top int) {
+	fmt.Fprintf(w, "%-14s %8s %10s %4s %11s %10s\n", "lesson", "allocs", "bytes", "GCs", "allocs/run", "time")
+	for _, r := range reports {
+		perRun := "-"
+		if r.Runs > 0 {
+			perRun = fmt.Sprintf("%.1f", r.AllocsPerRun)
+		}
+		fmt.Fprintf(w, "%-14s %8d %10d %4d %11s %10s\n", r.Name, r.Allocs, r.Bytes, r.GCCycles, perRun, r.Elapsed.Round(time.Microsecond))
+	}
+	src := map[string][]string{}
+	for _, r := range reports {
+		if len(r.Lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s: allocations by line\n", r.Name)
+		for i, l := range r.Lines {
+			if top > 0 && i == top {
+				fmt.Fprintf(w, "  ... %d more lines\n", len(r.Lines)-top)
+				break
+			}
+			if _, ok := src[l.File]; !ok {
+				data, _ := os.ReadFile(l.File)
+				src[l.File] = strings.Split(string(data), "\n")
+			}
+			text := ""
+			if lines := src[l.File]; l.Line-1 < len(lines) {
+				text = strings.TrimSpace(lines[l.Line-1])
+			}
+			base := l.File[strings.LastIndex(l.File, "/")+1:]
+			fmt.Fprintf(w, "  %s:%-4d %5d allocs %7d B  %s\n", base, l.Line, l.Allocs, l.Bytes, text)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackageOf(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"main.collections", "main."},
+		{"main.collections.func1", "main."},
+		{"github.com/amiiralihassanpour/golang_learning/runner.Measure", "github.com/amiiralihassanpour/golang_learning/runner."},
+		{"github.com/amiiralihassanpour/golang_learning/runner.(*T).m", "github.com/amiiralihassanpour/golang_learning/runner."},
+		{"example.com/a.b/pkg.F", "example.com/a.b/pkg."},
+		{"nodot", "nodot"},
+	}
+	for _, tt := range tests {
+		if got := packageOf(tt.name); got != tt.want {
+			t.Errorf("packageOf(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// sink keeps allocate's objects on the heap.
+var sink [10]*[64]byte
+
+// allocate is a lesson that allocates ten 64-byte objects, all on one
+// line.
+func allocate() {
+	for i := range sink {
+		sink[i] = new([64]byte)
+	}
+}
+
+// sourceLine returns the trimmed text of a line of a file.
+func sourceLine(t *testing.T, file string, line int) string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		t.Fatalf("%s has no line %d", file, line)
+	}
+	return strings.TrimSpace(lines[line-1])
+}
+
+func TestMeasure(t *testing.T) {
+	r := Measure(Lesson{Name: "allocate", Run: allocate}, Options{Runs: 20, Lines: true})
+	if r.Name != "allocate" {
+		t.Errorf("name %q", r.Name)
+	}
+	// The counters are process-wide, so they are at least the lesson's.
+	if r.Allocs < 10 || r.Bytes < 640 {
+		t.Errorf("counted %d allocs, %d bytes; want at least 10 and 640", r.Allocs, r.Bytes)
+	}
+	if r.Runs != 20 || r.AllocsPerRun != 10 {
+		t.Errorf("%v allocs per run over %d runs, want 10 over 20", r.AllocsPerRun, r.Runs)
+	}
+	if len(r.Lines) != 1 {
+		t.Fatalf("got lines %+v, want one", r.Lines)
+	}
+	l := r.Lines[0]
+	if !strings.HasSuffix(l.Func, ".allocate") || l.Allocs != 10 || l.Bytes != 640 {
+		t.Errorf("got %+v, want 10 allocs of 640 bytes in allocate", l)
+	}
+	if text := sourceLine(t, l.File, l.Line); text != "sink[i] = new([64]byte)" {
+		t.Errorf("charged to %s:%d, %q", l.File, l.Line, text)
+	}
+
+	// Without the options only the counters are read.
+	r = Measure(Lesson{Name: "allocate", Run: allocate}, Options{})
+	if r.Runs != 0 || r.AllocsPerRun != 0 || r.Lines != nil {
+		t.Errorf("measured without options: %+v", r)
+	}
+}
+
+// callers returns its caller's stack, as a memory profile records it.
+func callers() [32]uintptr {
+	var stack [32]uintptr
+	runtime.Callers(2, stack[:])
+	return stack
+}
+
+// twoStacks stands in for a lesson with two allocating lines.
+func twoStacks() (a, b [32]uintptr) {
+	a = callers()
+	b = callers()
+	return a, b
+}
+
+func TestAttribute(t *testing.T) {
+	a, b := twoStacks()
+	other := callers()
+	before := map[[32]uintptr]profileEntry{a: {1, 8}, b: {2, 100}}
+	after := map[[32]uintptr]profileEntry{
+		a:     {4, 32},  // 3 more allocations, 24 more bytes
+		b:     {2, 100}, // nothing new
+		other: {5, 500}, // not through the lesson
+	}
+	lines := attribute(before, after, funcName(func() { twoStacks() }))
+	if len(lines) != 0 {
+		t.Errorf("a closure that is not on any stack got %+v", lines)
+	}
+	lesson := "github.com/amiiralihassanpour/golang_learning/runner.twoStacks"
+	lines = attribute(before, after, lesson)
+	if len(lines) != 1 {
+		t.Fatalf("got %+v, want one line", lines)
+	}
+	if l := lines[0]; l.Func != lesson || l.Allocs != 3 || l.Bytes != 24 || sourceLine(t, l.File, l.Line) != "a = callers()" {
+		t.Errorf("got %+v", l)
+	}
+
+	// Lines are sorted by bytes, most first.
+	after[b] = profileEntry{3, 1100}
+	lines = attribute(before, after, lesson)
+	if len(lines) != 2 || lines[0].Bytes != 1000 || lines[1].Bytes != 24 {
+		t.Errorf("got %+v, want b's 1000 bytes before a's 24", lines)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	a, b := twoStacks()
+	lines := attribute(nil, map[[32]uintptr]profileEntry{a: {2, 32}, b: {1, 16}}, "github.com/amiiralihassanpour/golang_learning/runner.twoStacks")
+	if len(lines) != 2 {
+		t.Fatalf("got %+v", lines)
+	}
+	reports := []Report{
+		{Name: "loops", Allocs: 3, Bytes: 48, Elapsed: 1500 * time.Microsecond},
+		{Name: "functions", Allocs: 12, Bytes: 960, GCCycles: 1, Elapsed: 2 * time.Millisecond, Runs: 10, AllocsPerRun: 11.5, Lines: lines},
+	}
+	var buf strings.Builder
+	WriteReport(&buf, reports, 1)
+	want := `lesson           allocs      bytes  GCs  allocs/run       time
+loops                 3         48    0           -      1.5ms
+functions            12        960    1        11.5        2ms
+
+functions: allocations by line
+` + fmt.Sprintf("  runner_test.go:%-4d     2 allocs      32 B  a = callers()\n", lines[0].Line) + `  ... 1 more lines
+`
+	if buf.String() != want {
+		t.Errorf("got\n%swant\n%s", buf.String(), want)
+	}
+}
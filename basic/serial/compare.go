@@ -0,0 +1,70 @@
+package serial
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RoundTrip encodes people with f, decodes the result and checks that the
+// records came back unchanged. It returns the encoded size.
+func RoundTrip(f Format, people []Person) (int, error) {
+	data, err := f.Encode(people)
+	if err != nil {
+		return 0, fmt.Errorf("%s: encode: %w", f.Name, err)
+	}
+	back, err := f.Decode(data)
+	if err != nil {
+		return 0, fmt.Errorf("%s: decode: %w", f.Name, err)
+	}
+	if !reflect.DeepEqual(back, people) {
+		return 0, fmt.Errorf("%s: round trip changed the records", f.Name)
+	}
+	return len(data), nil
+}
+
+// Evolution records how a format copes with PersonV2 on one side only. A
+// nil error means the records came through intact (minus or plus the zero
+// Email).
+type Evolution struct {
+	Format string
+	// OldReadsNew is the outcome of decoding V2 data with the V1 reader
+	// (forward compatibility).
+	OldReadsNew error
+	// NewReadsOld is the outcome of decoding V1 data with the V2 reader
+	// (backward compatibility).
+	NewReadsOld error
+}
+
+// CheckEvolution runs both schema-evolution directions for f.
+func CheckEvolution(f Format, people []Person) Evolution {
+	v2 := make([]PersonV2, len(people))
+	for i, p := range people {
+		v2[i] = PersonV2{Name: p.Name, Age: p.Age, Email: p.Name + "@example.com"}
+	}
+	ev := Evolution{Format: f.Name}
+
+	data, err := f.EncodeV2(v2)
+	if err == nil {
+		var got []Person
+		got, err = f.Decode(data)
+		if err == nil && !reflect.DeepEqual(got, people) {
+			err = fmt.Errorf("records decoded incorrectly")
+		}
+	}
+	ev.OldReadsNew = err
+
+	data, err = f.Encode(people)
+	if err == nil {
+		var got []PersonV2
+		got, err = f.DecodeV2(data)
+		want := make([]PersonV2, len(people))
+		for i, p := range people {
+			want[i] = PersonV2{Name: p.Name, Age: p.Age}
+		}
+		if err == nil && !reflect.DeepEqual(got, want) {
+			err = fmt.Errorf("records decoded incorrectly")
+		}
+	}
+	ev.NewReadsOld = err
+	return ev
+}
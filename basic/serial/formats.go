@@ -0,0 +1,288 @@
+package serial
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// JSON uses encoding/json. Unknown fields are ignored and missing ones keep
+// their zero value, so both evolution directions work.
+var JSON = Format{
+	Name:     "json",
+	Encode:   func(p []Person) ([]byte, error) { return json.Marshal(p) },
+	Decode:   decodeJSON[Person],
+	EncodeV2: func(p []PersonV2) ([]byte, error) { return json.Marshal(p) },
+	DecodeV2: decodeJSON[PersonV2],
+}
+
+func decodeJSON[T any](data []byte) ([]T, error) {
+	var out []T
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+// Gob uses encoding/gob. Each stream carries its type description, and
+// fields are matched by name, so gob also tolerates added fields.
+var Gob = Format{
+	Name:     "gob",
+	Encode:   encodeGob[Person],
+	Decode:   decodeGob[Person],
+	EncodeV2: encodeGob[PersonV2],
+	DecodeV2: decodeGob[PersonV2],
+}
+
+func encodeGob[T any](p []T) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(p)
+	return buf.Bytes(), err
+}
+
+func decodeGob[T any](data []byte) ([]T, error) {
+	var out []T
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out)
+	return out, err
+}
+
+// Binary is a hand-rolled format built from encoding/binary varints:
+//
+//	count, then per record: len(name), name bytes, age (zig-zag varint)
+//	V2 appends: len(email), email bytes
+//
+// Fields carry no tags or lengths, so a reader must know the exact schema:
+// the format is the smallest of the four but breaks in both evolution
+// directions.
+var Binary = Format{
+	Name: "binary",
+	Encode: func(p []Person) ([]byte, error) {
+		buf := binary.AppendUvarint(nil, uint64(len(p)))
+		for _, r := range p {
+			buf = appendString(buf, r.Name)
+			buf = binary.AppendVarint(buf, int64(r.Age))
+		}
+		return buf, nil
+	},
+	Decode: func(data []byte) ([]Person, error) {
+		d := &binDecoder{data: data}
+		n := d.count()
+		out := make([]Person, 0, min(n, len(data)))
+		for i := 0; i < n && d.err == nil; i++ {
+			out = append(out, Person{Name: d.string(), Age: int(d.varint())})
+		}
+		return out, d.finish()
+	},
+	EncodeV2: func(p []PersonV2) ([]byte, error) {
+		buf := binary.AppendUvarint(nil, uint64(len(p)))
+		for _, r := range p {
+			buf = appendString(buf, r.Name)
+			buf = binary.AppendVarint(buf, int64(r.Age))
+			buf = appendString(buf, r.Email)
+		}
+		return buf, nil
+	},
+	DecodeV2: func(data []byte) ([]PersonV2, error) {
+		d := &binDecoder{data: data}
+		n := d.count()
+		out := make([]PersonV2, 0, min(n, len(data)))
+		for i := 0; i < n && d.err == nil; i++ {
+			out = append(out, PersonV2{Name: d.string(), Age: int(d.varint()), Email: d.string()})
+		}
+		return out, d.finish()
+	},
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+var errTruncated = errors.New("binary: truncated input")
+
+// binDecoder reads varints and strings and remembers the first error, so
+// the decode loops stay free of error checks.
+type binDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *binDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binDecoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		// Every record takes at least one byte.
+		d.err = fmt.Errorf("binary: record count %d exceeds input size", n)
+		return 0
+	}
+	return int(n)
+}
+
+func (d *binDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *binDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if n > uint64(len(d.data)) {
+		d.err = errTruncated
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+	return s
+}
+
+func (d *binDecoder) finish() error {
+	if d.err == nil && len(d.data) > 0 {
+		return fmt.Errorf("binary: %d trailing bytes", len(d.data))
+	}
+	return d.err
+}
+
+// Protobuf writes the wire format of
+//
+//	message Person { string name = 1; int64 age = 2; string email = 3; }
+//	message People { repeated Person people = 1; }
+//
+// with protowire. Every field is tagged and length-delimited, so readers
+// skip fields they do not know and missing fields default to zero.
+var Protobuf = Format{
+	Name: "protobuf",
+	Encode: func(p []Person) ([]byte, error) {
+		var buf, msg []byte
+		for _, r := range p {
+			msg = appendPersonProto(msg[:0], r.Name, r.Age, "")
+			buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+			buf = protowire.AppendBytes(buf, msg)
+		}
+		return buf, nil
+	},
+	Decode: func(data []byte) ([]Person, error) {
+		var out []Person
+		err := consumePeopleProto(data, false, func(name string, age int, _ string) {
+			out = append(out, Person{Name: name, Age: age})
+		})
+		return out, err
+	},
+	EncodeV2: func(p []PersonV2) ([]byte, error) {
+		var buf, msg []byte
+		for _, r := range p {
+			msg = appendPersonProto(msg[:0], r.Name, r.Age, r.Email)
+			buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+			buf = protowire.AppendBytes(buf, msg)
+		}
+		return buf, nil
+	},
+	DecodeV2: func(data []byte) ([]PersonV2, error) {
+		var out []PersonV2
+		err := consumePeopleProto(data, true, func(name string, age int, email string) {
+			out = append(out, PersonV2{Name: name, Age: age, Email: email})
+		})
+		return out, err
+	},
+}
+
+// appendPersonProto encodes one Person message. Like generated code, it
+// omits fields that hold their zero value.
+func appendPersonProto(buf []byte, name string, age int, email string) []byte {
+	if name != "" {
+		buf = protowire.AppendTag(buf, 1, protowire.BytesType)
+		buf = protowire.AppendString(buf, name)
+	}
+	if age != 0 {
+		buf = protowire.AppendTag(buf, 2, protowire.VarintType)
+		buf = protowire.AppendVarint(buf, uint64(int64(age)))
+	}
+	if email != "" {
+		buf = protowire.AppendTag(buf, 3, protowire.BytesType)
+		buf = protowire.AppendString(buf, email)
+	}
+	return buf
+}
+
+// consumePeopleProto walks a People message and calls fn for each Person.
+// With v2 false the email field is unknown, as it would be to code generated
+// from the old schema, and is skipped like any other unknown field.
+func consumePeopleProto(data []byte, v2 bool, fn func(name string, age int, email string)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if num != 1 || typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+		msg, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		name, age, email, err := consumePersonProto(msg, v2)
+		if err != nil {
+			return err
+		}
+		fn(name, age, email)
+	}
+	return nil
+}
+
+func consumePersonProto(msg []byte, v2 bool) (name string, age int, email string, err error) {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return "", 0, "", protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType:
+			name, n = protowire.ConsumeString(msg)
+		case num == 2 && typ == protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(msg)
+			age = int(int64(v))
+		case v2 && num == 3 && typ == protowire.BytesType:
+			email, n = protowire.ConsumeString(msg)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, msg)
+		}
+		if n < 0 {
+			return "", 0, "", protowire.ParseError(n)
+		}
+		msg = msg[n:]
+	}
+	return name, age, email, nil
+}
@@ -0,0 +1,55 @@
+// Package serial encodes the same records with several serialization
+// formats so their size, speed and schema-evolution behaviour can be
+// compared side by side:
+//
+//   - encoding/json: self-describing text
+//   - encoding/gob: self-describing binary, Go only
+//   - a hand-rolled encoding/binary varint format with no field tags
+//   - protobuf, written with protowire so no generated code is needed
+//
+// The records are the name + age pairs from the lessons (Person). PersonV2
+// adds an Email field and is used to check what happens when the schema
+// evolves on one side only.
+package serial
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Person is the record from the lessons: a name and an age.
+type Person struct {
+	Name string
+	Age  int
+}
+
+// PersonV2 is the next version of Person with one more field.
+type PersonV2 struct {
+	Name  string
+	Age   int
+	Email string
+}
+
+// Format is one serialization format, able to read and write both schema
+// versions.
+type Format struct {
+	Name     string
+	Encode   func([]Person) ([]byte, error)
+	Decode   func([]byte) ([]Person, error)
+	EncodeV2 func([]PersonV2) ([]byte, error)
+	DecodeV2 func([]byte) ([]PersonV2, error)
+}
+
+// Formats lists every format in the comparison.
+var Formats = []Format{JSON, Gob, Binary, Protobuf}
+
+// FromMap turns the students map into records sorted by name, so every
+// format sees the same input order.
+func FromMap(m map[string]int) []Person {
+	people := make([]Person, 0, len(m))
+	for name, age := range m {
+		people = append(people, Person{Name: name, Age: age})
+	}
+	slices.SortFunc(people, func(a, b Person) int { return cmp.Compare(a.Name, b.Name) })
+	return people
+}
@@ -0,0 +1,111 @@
+package serial
+
+import (
+	"fmt"
+	"testing"
+)
+
+// people returns n records shaped like the lesson's students map.
+func people(n int) []Person {
+	m := map[string]int{"Alice": 30, "Bob": 25, "سارا": 22}
+	for i := range n {
+		m[fmt.Sprintf("student%05d", i)] = 18 + i%60
+	}
+	return FromMap(m)
+}
+
+func TestRoundTrip(t *testing.T) {
+	edge := []Person{{Name: "", Age: 0}, {Name: "neg", Age: -5}, {Name: "big", Age: 1 << 40}}
+	for _, f := range Formats {
+		for _, p := range [][]Person{people(100), edge} {
+			if _, err := RoundTrip(f, p); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+// TestEvolution adds an Email field on one side only. Every format but
+// the hand-rolled one carries enough about its fields to skip one it does
+// not know and to leave one it does not find at its zero value.
+func TestEvolution(t *testing.T) {
+	compatible := map[string]bool{"json": true, "gob": true, "binary": false, "protobuf": true}
+	for _, f := range Formats {
+		ev := CheckEvolution(f, people(10))
+		want, ok := compatible[f.Name]
+		if !ok {
+			t.Errorf("%s: no expectation for this format", f.Name)
+			continue
+		}
+		if got := ev.OldReadsNew == nil; got != want {
+			t.Errorf("%s: old reader on new data: %v, want ok = %v", f.Name, ev.OldReadsNew, want)
+		}
+		if got := ev.NewReadsOld == nil; got != want {
+			t.Errorf("%s: new reader on old data: %v, want ok = %v", f.Name, ev.NewReadsOld, want)
+		}
+	}
+}
+
+// TestSize checks the ranking the README describes: the hand-rolled
+// format, with no field tags, is the smallest, and JSON the largest.
+func TestSize(t *testing.T) {
+	sizes := map[string]int{}
+	for _, f := range Formats {
+		n, err := RoundTrip(f, people(1000))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes[f.Name] = n
+	}
+	for name, n := range sizes {
+		if name != "binary" && n <= sizes["binary"] {
+			t.Errorf("%s (%d bytes) is not larger than binary (%d bytes)", name, n, sizes["binary"])
+		}
+		if name != "json" && n >= sizes["json"] {
+			t.Errorf("%s (%d bytes) is not smaller than json (%d bytes)", name, n, sizes["json"])
+		}
+	}
+}
+
+func TestDecodeGarbage(t *testing.T) {
+	for _, f := range Formats {
+		for _, data := range [][]byte{{0xff}, {0x05, 'a'}, []byte("not a record")} {
+			if _, err := f.Decode(data); err == nil {
+				t.Errorf("%s: decoded %q without an error", f.Name, data)
+			}
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	p := people(1000)
+	for _, f := range Formats {
+		b.Run(f.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := f.Encode(p); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	p := people(1000)
+	for _, f := range Formats {
+		data, err := f.Encode(p)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(f.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				if _, err := f.Decode(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
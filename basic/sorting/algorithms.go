@@ -0,0 +1,197 @@
+package sorting
+
+// Bubble sort passes over the array swapping neighbours that are out of
+// order, so each pass carries the largest remaining element to the end.
+// It stops early after a pass with no swaps, which makes it linear on
+// sorted input; otherwise it takes quadratic time.
+func Bubble(a *Array) {
+	for end := a.Len(); end > 1; end-- {
+		swapped := false
+		for i := 1; i < end; i++ {
+			if a.Less(i, i-1) {
+				a.Swap(i, i-1)
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+}
+
+// Insertion sort grows a sorted prefix one element at a time, swapping
+// each new element back until it is in place. It is quadratic in general
+// but linear on nearly sorted input, which is why fast sorts hand small
+// ranges over to it.
+func Insertion(a *Array) {
+	for i := 1; i < a.Len(); i++ {
+		for j := i; j > 0 && a.Less(j, j-1); j-- {
+			a.Swap(j, j-1)
+		}
+	}
+}
+
+// Selection sort finds the smallest remaining element and swaps it into
+// place. It always makes about n²/2 comparisons but only n-1 swaps, the
+// fewest of any algorithm here.
+func Selection(a *Array) {
+	for i := 0; i < a.Len()-1; i++ {
+		m := i
+		for j := i + 1; j < a.Len(); j++ {
+			if a.Less(j, m) {
+				m = j
+			}
+		}
+		if m != i {
+			a.Swap(i, m)
+		}
+	}
+}
+
+// Merge sort sorts each half and then merges them, always in n log n
+// time. Merging needs a buffer: each range is copied out and written back
+// in order, so merge sort writes rather than swaps.
+func Merge(a *Array) {
+	buf := make([]int, a.Len())
+	mergeSort(a, buf, 0, a.Len())
+}
+
+func mergeSort(a *Array, buf []int, lo, hi int) {
+	if hi-lo < 2 {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	mergeSort(a, buf, lo, mid)
+	mergeSort(a, buf, mid, hi)
+	for i := lo; i < hi; i++ {
+		buf[i] = a.At(i)
+	}
+	i, j := lo, mid
+	for k := lo; k < hi; k++ {
+		// Taking from the left half on ties keeps the sort stable.
+		if j >= hi || i < mid && !a.lessValues(buf[j], buf[i], j, i) {
+			a.Set(k, buf[i])
+			i++
+		} else {
+			a.Set(k, buf[j])
+			j++
+		}
+	}
+}
+
+// Quick sort partitions the array around a pivot, with smaller elements
+// to its left and larger to its right, then sorts the two sides. It takes
+// n log n time on average. The pivot is the middle element, so sorted
+// input is not a worst case. The partition stops on elements equal to the
+// pivot, which keeps it balanced when there are many duplicates.
+func Quick(a *Array) {
+	quickSort(a, 0, a.Len()-1)
+}
+
+func quickSort(a *Array, lo, hi int) {
+	for lo < hi {
+		a.Swap(lo, lo+(hi-lo)/2)
+		p := partition(a, lo, hi)
+		// Recurse into the smaller side and loop on the larger, so the
+		// stack stays logarithmic.
+		if p-lo < hi-p {
+			quickSort(a, lo, p-1)
+			lo = p + 1
+		} else {
+			quickSort(a, p+1, hi)
+			hi = p - 1
+		}
+	}
+}
+
+// partition splits a[lo:hi+1] around the pivot at lo and returns the
+// pivot's final position.
+func partition(a *Array, lo, hi int) int {
+	i, j := lo, hi+1
+	for {
+		for i++; i < hi && a.Less(i, lo); i++ {
+		}
+		for j--; j > lo && a.Less(lo, j); j-- {
+		}
+		if i >= j {
+			break
+		}
+		a.Swap(i, j)
+	}
+	a.Swap(lo, j)
+	return j
+}
+
+// Heap sort arranges the array as a binary max-heap, then repeatedly
+// swaps the root, the largest element, to the end and restores the heap
+// in the rest. It takes n log n time in place, at the cost of jumping
+// about the array.
+func Heap(a *Array) {
+	n := a.Len()
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(a, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		a.Swap(0, end)
+		siftDown(a, 0, end)
+	}
+}
+
+// siftDown moves the element at i down the heap a[:n] until it is no
+// smaller than its children.
+func siftDown(a *Array, i, n int) {
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && a.Less(child, child+1) {
+			child++
+		}
+		if !a.Less(i, child) {
+			return
+		}
+		a.Swap(i, child)
+		i = child
+	}
+}
+
+// Radix sort never compares elements. It distributes them by their last
+// decimal digit, then the one before, and so on, keeping the order of the
+// previous pass among equal digits. That takes one pass of n writes per
+// digit, so it is linear when the values have few digits.
+func Radix(a *Array) {
+	n := a.Len()
+	if n < 2 {
+		return
+	}
+	// Sort by the distance from the minimum, so negative numbers work.
+	// The distance is unsigned, so it cannot overflow.
+	lo, hi := a.At(0), a.At(0)
+	for i := range n {
+		lo, hi = min(lo, a.At(i)), max(hi, a.At(i))
+	}
+	key := func(i int) uint { return uint(a.At(i)) - uint(lo) }
+	span := uint(hi) - uint(lo)
+	buf := make([]int, n)
+	for exp := uint(1); span/exp > 0; exp *= 10 {
+		var count [10]int
+		for i := range n {
+			count[key(i)/exp%10]++
+		}
+		for d := 1; d < 10; d++ {
+			count[d] += count[d-1]
+		}
+		for i := n - 1; i >= 0; i-- {
+			d := key(i) / exp % 10
+			count[d]--
+			buf[count[d]] = a.At(i)
+		}
+		for i, v := range buf {
+			a.Set(i, v)
+		}
+		if exp > span/10 {
+			break
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package sorting
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Frame is the state of the array after a step of a replay.
+type Frame struct {
+	// N counts the steps so far, from 1.
+	N    int
+	Step Step
+	// Data is the array after the step. It is shared between frames, so
+	// it must be copied to be kept.
+	Data   []int
+	Counts Counts
+}
+
+// Replay applies recorded steps to a copy of data and yields the array
+// after each one, with the running counts.
+func Replay(data []int, steps []Step) iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		f := Frame{Data: append([]int(nil), data...)}
+		for i, s := range steps {
+			Apply(f.Data, s)
+			f.N, f.Step = i+1, s
+			f.Counts.add(s)
+			if !yield(f) {
+				return
+			}
+		}
+	}
+}
+
+// ANSI colours for the elements a step touches.
+const (
+	colorCompare = "\x1b[33m" // yellow
+	colorSwap    = "\x1b[31m" // red
+	colorWrite   = "\x1b[32m" // green
+	colorReset   = "\x1b[0m"
+)
+
+// Render draws data as a bar chart height rows tall, one column per
+// element. If step is not nil, the elements it touched are marked: in
+// colour if color is set, otherwise with a row of ^ under the chart.
+func Render(w io.Writer, data []int, step *Step, height int, color bool) error {
+	if len(data) == 0 {
+		return nil
+	}
+	lo, hi := data[0], data[0]
+	for _, v := range data {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	// The smallest value gets one row, the largest all of them.
+	bar := func(v int) int {
+		if hi == lo {
+			return height
+		}
+		return 1 + (v-lo)*(height-1)/(hi-lo)
+	}
+	mark := func(i int) string {
+		if step == nil || i != step.I && (step.Op == Write || i != step.J) {
+			return ""
+		}
+		switch step.Op {
+		case Compare:
+			return colorCompare
+		case Swap:
+			return colorSwap
+		}
+		return colorWrite
+	}
+	bw := bufio.NewWriter(w)
+	for row := height; row >= 1; row-- {
+		for i, v := range data {
+			c := " "
+			if bar(v) >= row {
+				c = "█"
+			}
+			if m := mark(i); m != "" && color {
+				c = m + c + colorReset
+			}
+			bw.WriteString(c)
+		}
+		bw.WriteString("\n")
+	}
+	if step != nil && !color {
+		var marks strings.Builder
+		for i := range data {
+			if mark(i) != "" {
+				marks.WriteString("^")
+			} else {
+				marks.WriteString(" ")
+			}
+		}
+		bw.WriteString(strings.TrimRight(marks.String(), " ") + "\n")
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,56 @@
+package sorting
+
+import "math/rand/v2"
+
+// Shape is a kind of input. Algorithms differ most in how they react to
+// the shape of their input: insertion sort is linear on sorted data and
+// quadratic on reversed data, while merge sort does not care.
+type Shape struct {
+	Name string
+	// Make returns n values between 1 and n.
+	Make func(n int, r *rand.Rand) []int
+}
+
+// Shapes lists the input shapes BenchmarkSort runs.
+var Shapes = []Shape{
+	{"random", func(n int, r *rand.Rand) []int {
+		s := sorted(n)
+		r.Shuffle(n, func(i, j int) { s[i], s[j] = s[j], s[i] })
+		return s
+	}},
+	{"sorted", func(n int, r *rand.Rand) []int { return sorted(n) }},
+	{"reversed", func(n int, r *rand.Rand) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	}},
+	{"few-unique", func(n int, r *rand.Rand) []int {
+		// Four distinct values, spread over the range so they show as
+		// distinct bars.
+		s := make([]int, n)
+		for i := range s {
+			s[i] = (r.IntN(4) + 1) * max(n/4, 1)
+		}
+		return s
+	}},
+}
+
+func sorted(n int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = i + 1
+	}
+	return s
+}
+
+// LookupShape returns the shape called name.
+func LookupShape(name string) (Shape, bool) {
+	for _, s := range Shapes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Shape{}, false
+}
@@ -0,0 +1,171 @@
+// Package sorting implements the classic sorting algorithms on an
+// instrumented array, so that every comparison, swap and write can be
+// counted and replayed, step by step, as an animation.
+//
+// The algorithms only touch the data through Array's methods. Record
+// collects the steps they emit, and Replay applies them to a copy of the
+// input one at a time, which is how `basic sort show` animates a sort.
+package sorting
+
+import "fmt"
+
+// Op is the kind of a step.
+type Op uint8
+
+const (
+	// Compare compares the elements at I and J.
+	Compare Op = iota
+	// Swap exchanges the elements at I and J.
+	Swap
+	// Write stores Value at I, as merge and radix sort do when they copy
+	// elements back from a buffer.
+	Write
+)
+
+func (op Op) String() string {
+	switch op {
+	case Compare:
+		return "compare"
+	case Swap:
+		return "swap"
+	case Write:
+		return "write"
+	}
+	return fmt.Sprintf("Op(%d)", op)
+}
+
+// Step is one operation of a sort.
+type Step struct {
+	Op    Op
+	I, J  int
+	Value int
+}
+
+// Counts totals the operations of a sort.
+type Counts struct {
+	Compares, Swaps, Writes int
+}
+
+func (c Counts) String() string {
+	return fmt.Sprintf("%d compares, %d swaps, %d writes", c.Compares, c.Swaps, c.Writes)
+}
+
+// add counts one step.
+func (c *Counts) add(s Step) {
+	switch s.Op {
+	case Compare:
+		c.Compares++
+	case Swap:
+		c.Swaps++
+	case Write:
+		c.Writes++
+	}
+}
+
+// Array is the data being sorted. Reads are free, but every comparison,
+// swap and write is counted and passed to the emit function, if any.
+type Array struct {
+	data   []int
+	counts Counts
+	emit   func(Step)
+}
+
+// NewArray wraps data, which the sort will modify in place. emit may be
+// nil when only the counts are wanted.
+func NewArray(data []int, emit func(Step)) *Array {
+	return &Array{data: data, emit: emit}
+}
+
+// Len returns the number of elements.
+func (a *Array) Len() int { return len(a.data) }
+
+// At returns the element at i.
+func (a *Array) At(i int) int { return a.data[i] }
+
+// Counts returns the operations so far.
+func (a *Array) Counts() Counts { return a.counts }
+
+func (a *Array) step(s Step) {
+	a.counts.add(s)
+	if a.emit != nil {
+		a.emit(s)
+	}
+}
+
+// Less reports whether the element at i is less than the element at j.
+func (a *Array) Less(i, j int) bool {
+	a.step(Step{Op: Compare, I: i, J: j})
+	return a.data[i] < a.data[j]
+}
+
+// lessValues compares x and y, values that have been copied out of the
+// array from positions i and j. The step names those positions, so an
+// animation can show where the values came from.
+func (a *Array) lessValues(x, y, i, j int) bool {
+	a.step(Step{Op: Compare, I: i, J: j})
+	return x < y
+}
+
+// Swap exchanges the elements at i and j.
+func (a *Array) Swap(i, j int) {
+	a.step(Step{Op: Swap, I: i, J: j})
+	a.data[i], a.data[j] = a.data[j], a.data[i]
+}
+
+// Set stores v at i.
+func (a *Array) Set(i, v int) {
+	a.step(Step{Op: Write, I: i, Value: v})
+	a.data[i] = v
+}
+
+// Apply performs a recorded step on data, without counting it.
+func Apply(data []int, s Step) {
+	switch s.Op {
+	case Swap:
+		data[s.I], data[s.J] = data[s.J], data[s.I]
+	case Write:
+		data[s.I] = s.Value
+	}
+}
+
+// Algorithm is a named sorting algorithm.
+type Algorithm struct {
+	Name string
+	Sort func(a *Array)
+}
+
+// Algorithms lists every algorithm in the package, simplest first.
+var Algorithms = []Algorithm{
+	{"bubble", Bubble},
+	{"insertion", Insertion},
+	{"selection", Selection},
+	{"merge", Merge},
+	{"quick", Quick},
+	{"heap", Heap},
+	{"radix", Radix},
+}
+
+// Lookup returns the algorithm called name.
+func Lookup(name string) (Algorithm, bool) {
+	for _, alg := range Algorithms {
+		if alg.Name == name {
+			return alg, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// Sort sorts data in place with sort and returns the operation counts.
+func Sort(sort func(*Array), data []int) Counts {
+	a := NewArray(data, nil)
+	sort(a)
+	return a.counts
+}
+
+// Record sorts a copy of data and returns the steps it took. data itself
+// is left alone, ready to replay the steps on.
+func Record(sort func(*Array), data []int) []Step {
+	var steps []Step
+	sort(NewArray(append([]int(nil), data...), func(s Step) { steps = append(steps, s) }))
+	return steps
+}
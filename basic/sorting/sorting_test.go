@@ -0,0 +1,90 @@
+package sorting
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestAlgorithms sorts every input shape, and some awkward inputs, with
+// every algorithm, and checks that the result is sorted and that replaying
+// the recorded steps gives the same array and counts.
+func TestAlgorithms(t *testing.T) {
+	type input struct {
+		name string
+		data []int
+	}
+	inputs := []input{
+		{"empty", []int{}},
+		{"one", []int{7}},
+		{"two", []int{2, 1}},
+		{"equal", []int{5, 5, 5, 5, 5}},
+		{"negative", []int{3, -1, 0, -20, 14, -1, 9}},
+		{"wide", []int{1 << 62, -1 << 62, 0, 1<<63 - 1, -1 << 63}},
+		{"duplicate", []int{3, 1, 3, 1, 2, 3, 1, 2}},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, sh := range Shapes {
+		for _, n := range []int{10, 257} {
+			inputs = append(inputs, input{fmt.Sprintf("%s %d", sh.Name, n), sh.Make(n, r)})
+		}
+	}
+	for _, alg := range Algorithms {
+		t.Run(alg.Name, func(t *testing.T) {
+			for _, in := range inputs {
+				want := slices.Sorted(slices.Values(in.data))
+				got := slices.Clone(in.data)
+				counts := Sort(alg.Sort, got)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s: got %v, want %v", in.name, got, want)
+					continue
+				}
+				steps := Record(alg.Sort, in.data)
+				last := Frame{Data: slices.Clone(in.data)}
+				for f := range Replay(in.data, steps) {
+					last = f
+				}
+				if !slices.Equal(last.Data, want) {
+					t.Errorf("%s: replaying %d steps gives %v", in.name, len(steps), last.Data)
+				}
+				if last.Counts != counts {
+					t.Errorf("%s: replay counted %v, the sort %v", in.name, last.Counts, counts)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordLeavesInputAlone(t *testing.T) {
+	data := []int{3, 1, 2}
+	Record(Quick, data)
+	if !slices.Equal(data, []int{3, 1, 2}) {
+		t.Errorf("Record changed its input to %v", data)
+	}
+}
+
+// BenchmarkSort runs every algorithm on every input shape. Besides the
+// time, it reports the operations each sort makes, which shows what the
+// textbooks say without the noise of a timer.
+func BenchmarkSort(b *testing.B) {
+	const n = 1000
+	for _, alg := range Algorithms {
+		b.Run(alg.Name, func(b *testing.B) {
+			for _, sh := range Shapes {
+				b.Run(sh.Name, func(b *testing.B) {
+					in := sh.Make(n, rand.New(rand.NewPCG(1, 0)))
+					buf := make([]int, n)
+					var c Counts
+					for b.Loop() {
+						// Copying is linear, so it barely shows next to
+						// the sort.
+						copy(buf, in)
+						c = Sort(alg.Sort, buf)
+					}
+					b.ReportMetric(float64(c.Compares+c.Swaps+c.Writes), "ops/sort")
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Histogram counts values in bins of equal width. Bin i holds the values
+// from Min+i·Width up to, but not including, Min+(i+1)·Width; the last
+// bin also holds the maximum.
+type Histogram struct {
+	Min, Width float64
+	Counts     []int
+}
+
+// Sturges returns Sturges' rule for the number of bins for n values,
+// ⌈log₂ n⌉ + 1, which suits data that is roughly normal.
+func Sturges(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return 1 + int(math.Ceil(math.Log2(float64(n))))
+}
+
+// finite reports whether x is neither infinite nor NaN.
+func finite(x float64) bool { return !math.IsInf(x, 0) && !math.IsNaN(x) }
+
+// NewHistogram counts xs into bins bins spanning their range, or into
+// Sturges(len(xs)) bins if bins is not positive. NaNs and infinities,
+// which no bin of finite width can hold, are skipped. If all the values
+// are equal there is a single bin of width 0.
+func NewHistogram[T Number](xs []T, bins int) Histogram {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	n := 0
+	for _, v := range xs {
+		if x := float64(v); finite(x) {
+			lo, hi = min(lo, x), max(hi, x)
+			n++
+		}
+	}
+	if n == 0 {
+		return Histogram{}
+	}
+	if bins <= 0 {
+		bins = Sturges(n)
+	}
+	if lo == hi {
+		bins = 1
+	}
+	h := Histogram{Min: lo, Width: (hi - lo) / float64(bins), Counts: make([]int, bins)}
+	if math.IsInf(h.Width, 0) {
+		// The range overflows, though each bin's share of it does not.
+		h.Width = hi/float64(bins) - lo/float64(bins)
+	}
+	for _, v := range xs {
+		x := float64(v)
+		if !finite(x) {
+			continue
+		}
+		i := 0
+		if h.Width > 0 {
+			i = min(max(int(fraction(x, lo, h.Width)), 0), bins-1)
+		}
+		h.Counts[i]++
+	}
+	return h
+}
+
+// fraction returns (x-lo)/width, dividing first if x-lo overflows.
+func fraction(x, lo, width float64) float64 {
+	if d := x - lo; !math.IsInf(d, 0) {
+		return d / width
+	}
+	return x/width - lo/width
+}
+
+// Bin returns the bounds of bin i.
+func (h Histogram) Bin(i int) (lo, hi float64) {
+	return h.edge(i), h.edge(i + 1)
+}
+
+// edge returns the lower bound of bin i, halving the terms if the sum
+// overflows.
+func (h Histogram) edge(i int) float64 {
+	if e := h.Min + float64(i)*h.Width; !math.IsInf(e, 0) {
+		return e
+	}
+	return 2 * (h.Min/2 + float64(i)*(h.Width/2))
+}
+
+// eighths are the block characters one to eight eighths wide.
+var eighths = []rune("▏▎▍▌▋▊▉█")
+
+// bar returns a horizontal bar of length eighths of a character.
+func bar(length int) string {
+	s := strings.Repeat("█", length/8)
+	if length%8 > 0 {
+		s += string(eighths[length%8-1])
+	}
+	return s
+}
+
+// Write draws the histogram as one line per bin: the bin's bounds, its
+// count and a bar, with the fullest bin's bar width characters long.
+func (h Histogram) Write(w io.Writer, width int) error {
+	most := 0
+	for _, c := range h.Counts {
+		most = max(most, c)
+	}
+	label := func(x float64) string { return strconv.FormatFloat(x, 'g', 4, 64) }
+	var los, his []string
+	lw, hw, cw := 0, 0, len(strconv.Itoa(most))
+	for i := range h.Counts {
+		lo, hi := h.Bin(i)
+		los, his = append(los, label(lo)), append(his, label(hi))
+		lw, hw = max(lw, len(los[i])), max(hw, len(his[i]))
+	}
+	bw := bufio.NewWriter(w)
+	for i, c := range h.Counts {
+		closer := ")"
+		if i == len(h.Counts)-1 {
+			closer = "]"
+		}
+		length := 0
+		if most > 0 {
+			// Round to the nearest eighth, but show every non-empty bin.
+			length = max((8*width*c+most/2)/most, min(c, 1))
+		}
+		fmt.Fprintf(bw, "[%*s, %*s%s %*d %s\n", lw, los[i], hw, his[i], closer, cw, c, bar(length))
+	}
+	return bw.Flush()
+}
+
+// sparks are the block characters one to eight eighths high.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws xs as a line of block characters, one per value, from
+// ▁ for the smallest to █ for the largest. NaNs are drawn as spaces, and a
+// slice of equal values as a flat line at half height. The heights are
+// scaled to the finite values; -Inf is drawn at the bottom and +Inf at
+// the top.
+func Sparkline[T Number](xs []T) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range xs {
+		if x := float64(v); finite(x) {
+			lo, hi = min(lo, x), max(hi, x)
+		}
+	}
+	top := len(sparks) - 1
+	var b strings.Builder
+	for _, v := range xs {
+		x := float64(v)
+		switch {
+		case math.IsNaN(x):
+			b.WriteByte(' ')
+		case math.IsInf(x, -1):
+			b.WriteRune(sparks[0])
+		case math.IsInf(x, 1):
+			b.WriteRune(sparks[top])
+		case lo == hi:
+			b.WriteRune(sparks[3])
+		default:
+			// Halving both sides keeps hi-lo from overflowing.
+			f := (x/2 - lo/2) / (hi/2 - lo/2)
+			b.WriteRune(sparks[int(math.Round(f*float64(top)))])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// sortedFloats returns xs converted to float64 in increasing order. If
+// any value is NaN there is no order, and it returns nil, for which every
+// quantile is NaN.
+func sortedFloats[T Number](xs []T) []float64 {
+	sorted := make([]float64, len(xs))
+	for i, x := range xs {
+		sorted[i] = float64(x)
+		if math.IsNaN(sorted[i]) {
+			return nil
+		}
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// Quantile returns the q-quantile of xs, for q from 0 to 1: the value
+// below which a fraction q of the data lies. It sorts a copy of xs; to
+// take several quantiles of the same data, sort it once and use
+// QuantileSorted.
+func Quantile[T Number](xs []T, q float64) float64 {
+	return QuantileSorted(sortedFloats(xs), q)
+}
+
+// Median returns the middle value of xs, or the mean of the two middle
+// values if there is an even number of them.
+func Median[T Number](xs []T) float64 { return Quantile(xs, 0.5) }
+
+// QuantileSorted returns the q-quantile of sorted, which must be in
+// increasing order. Between two values it interpolates linearly: with n
+// values the q-quantile lies at position q(n-1), counting from 0. This is
+// the definition R and NumPy use by default (Hyndman and Fan's type 7),
+// and spreadsheets' PERCENTILE. A quantile that falls on a value is that
+// value, even next to an infinity.
+func QuantileSorted[T Number](sorted []T, q float64) float64 {
+	if len(sorted) == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	pos := min(max(q, 0), 1) * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return float64(sorted[len(sorted)-1])
+	}
+	lo, hi := float64(sorted[i]), float64(sorted[i+1])
+	f := pos - float64(i)
+	switch {
+	case f == 0 || lo == hi:
+		return lo
+	case math.IsInf(hi-lo, 0):
+		// An infinite neighbour, or a gap too wide for float64: weigh
+		// the two ends instead, which gives the infinity itself rather
+		// than NaN from 0·Inf or Inf - Inf.
+		return (1-f)*lo + f*hi
+	}
+	return lo + f*(hi-lo)
+}
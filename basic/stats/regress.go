@@ -0,0 +1,71 @@
+package stats
+
+import "math"
+
+// deviations returns the sums of squared and multiplied deviations of xs
+// and ys from their means. It panics if the slices differ in length,
+// since pairing them up would then be a mistake.
+func deviations[T Number](xs, ys []T) (sxx, syy, sxy float64) {
+	if len(xs) != len(ys) {
+		panic("stats: x and y have different lengths")
+	}
+	mx, my := Mean(xs), Mean(ys)
+	for i := range xs {
+		dx, dy := float64(xs[i])-mx, float64(ys[i])-my
+		sxx += dx * dx
+		syy += dy * dy
+		sxy += dx * dy
+	}
+	return sxx, syy, sxy
+}
+
+// Covariance returns the sample covariance of xs and ys, NaN for fewer
+// than two pairs.
+func Covariance[T Number](xs, ys []T) float64 {
+	_, _, sxy := deviations(xs, ys)
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	return sxy / float64(len(xs)-1)
+}
+
+// Correlation returns Pearson's correlation coefficient of xs and ys,
+// from -1 to 1. It is NaN if either has no spread, since then there is
+// nothing to correlate.
+func Correlation[T Number](xs, ys []T) float64 {
+	sxx, syy, sxy := deviations(xs, ys)
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	// Rounding can push a perfect correlation just past ±1.
+	return max(-1, min(1, sxy/math.Sqrt(sxx*syy)))
+}
+
+// Line is a straight line y = Intercept + Slope·x fitted to data.
+type Line struct {
+	Slope, Intercept float64
+	// R2 is the coefficient of determination: the fraction of the
+	// variance of y the line explains, from 0 to 1.
+	R2 float64
+}
+
+// At returns the line's y at x.
+func (l Line) At(x float64) float64 { return l.Intercept + l.Slope*x }
+
+// LinearRegression fits the least squares line through the points
+// (xs[i], ys[i]): the line that minimizes the sum of squared vertical
+// distances to them. Its fields are NaN if xs has no spread, since then
+// every slope fits as well as any other.
+func LinearRegression[T Number](xs, ys []T) Line {
+	sxx, syy, sxy := deviations(xs, ys)
+	if sxx == 0 {
+		nan := math.NaN()
+		return Line{nan, nan, nan}
+	}
+	slope := sxy / sxx
+	l := Line{Slope: slope, Intercept: Mean(ys) - slope*Mean(xs), R2: 1}
+	if syy > 0 {
+		l.R2 = min(1, sxy*sxy/(sxx*syy))
+	}
+	return l
+}
@@ -0,0 +1,98 @@
+package stats
+
+import "math"
+
+// Running keeps the count, mean, variance and range of a stream of values
+// without storing them. The zero value is ready to use.
+//
+// It updates the mean and the sum of squared deviations from it with each
+// value, as Welford described. This gives the variance in one pass,
+// without the cancellation of the sum-of-squares formula: a stream of
+// values near 1e9 that differ by 0.1 keeps its standard deviation of 0.1.
+type Running struct {
+	n        int
+	mean, m2 float64
+	min, max float64
+}
+
+// Add adds x to the stream.
+func (r *Running) Add(x float64) {
+	r.n++
+	if r.n == 1 {
+		r.min, r.max = x, x
+	} else {
+		r.min, r.max = min(r.min, x), max(r.max, x)
+	}
+	d := x - r.mean
+	r.mean += d / float64(r.n)
+	// The second factor uses the new mean, the first the old one; their
+	// product is the change in the sum of squared deviations.
+	r.m2 += d * (x - r.mean)
+}
+
+// Merge adds the values of other to the stream, as if each had been
+// added with Add, by the formula of Chan, Golub and LeVeque. Streams can
+// be summarized in parallel and merged at the end.
+func (r *Running) Merge(other Running) {
+	if other.n == 0 {
+		return
+	}
+	if r.n == 0 {
+		*r = other
+		return
+	}
+	n := float64(r.n + other.n)
+	d := other.mean - r.mean
+	r.mean += d * float64(other.n) / n
+	r.m2 += other.m2 + d*d*float64(r.n)*float64(other.n)/n
+	r.n += other.n
+	r.min, r.max = min(r.min, other.min), max(r.max, other.max)
+}
+
+// N returns the number of values added.
+func (r *Running) N() int { return r.n }
+
+// Mean returns the mean of the values, NaN if there are none.
+func (r *Running) Mean() float64 {
+	if r.n == 0 {
+		return math.NaN()
+	}
+	return r.mean
+}
+
+// Variance returns the sample variance of the values, NaN for fewer than
+// two.
+func (r *Running) Variance() float64 {
+	if r.n < 2 {
+		return math.NaN()
+	}
+	return r.m2 / float64(r.n-1)
+}
+
+// PopVariance returns the population variance of the values, NaN if
+// there are none.
+func (r *Running) PopVariance() float64 {
+	if r.n == 0 {
+		return math.NaN()
+	}
+	return r.m2 / float64(r.n)
+}
+
+// StdDev returns the sample standard deviation of the values.
+func (r *Running) StdDev() float64 { return math.Sqrt(r.Variance()) }
+
+// Min returns the smallest value, NaN if there are none.
+func (r *Running) Min() float64 {
+	if r.n == 0 {
+		return math.NaN()
+	}
+	return r.min
+}
+
+// Max returns the largest value, NaN if there are none.
+func (r *Running) Max() float64 {
+	if r.n == 0 {
+		return math.NaN()
+	}
+	return r.max
+}
@@ -0,0 +1,168 @@
+// Package stats computes descriptive statistics of numeric slices: sums
+// and means, variances, quantiles, modes, correlation and least squares
+// lines, with histograms and sparklines to look at the data in a
+// terminal.
+//
+// The functions are generic over the built-in numeric types and work in
+// float64. They are written to keep their precision where the textbook
+// formulas lose it: sums are compensated, variances are computed around
+// the mean rather than as the mean square minus the squared mean, and
+// Running updates its mean and variance in Welford's way, so it needs
+// only one pass. For data too large to sort, TDigest estimates quantiles
+// in a small, bounded amount of memory.
+//
+// A NaN anywhere in the input makes the functions of a sample return NaN,
+// as arithmetic does. Mode, Histogram and Sparkline, which count values,
+// skip NaNs instead.
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+// Number is the set of types the functions accept.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of xs, compensated with Neumaier's variant of
+// Kahan summation: the low-order bits lost by each addition are added up
+// separately and put back at the end. The result is as if the sum were
+// done with twice the precision, so adding 0.1 ten times gives exactly 1,
+// and 1 + 1e100 + 1 - 1e100 gives 2 rather than 0.
+func Sum[T Number](xs []T) float64 {
+	var sum, lost float64
+	for _, v := range xs {
+		x := float64(v)
+		t := sum + x
+		if math.Abs(sum) >= math.Abs(x) {
+			lost += (sum - t) + x
+		} else {
+			lost += (x - t) + sum
+		}
+		sum = t
+	}
+	if math.IsInf(sum, 0) {
+		// The lost bits of an infinite sum are NaN and mean nothing.
+		return sum
+	}
+	return sum + lost
+}
+
+// Mean returns the arithmetic mean of xs, or NaN if xs is empty.
+func Mean[T Number](xs []T) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	m := Sum(xs) / float64(len(xs))
+	if !math.IsInf(m, 0) {
+		return m
+	}
+	// An infinite value makes the mean infinite too.
+	for _, v := range xs {
+		if math.IsInf(float64(v), 0) {
+			return m
+		}
+	}
+	// Otherwise the sum overflowed, though the mean may not: update it
+	// one value at a time instead, which never holds more than the
+	// largest value.
+	m = 0
+	for i, v := range xs {
+		m += (float64(v) - m) / float64(i+1)
+	}
+	return m
+}
+
+// squares returns the sum of squared deviations of xs from their mean,
+// by the corrected two-pass algorithm. The deviations would sum to zero
+// in exact arithmetic. Subtracting the square of their actual sum over n
+// removes most of the rounding error in the mean.
+func squares[T Number](xs []T) float64 {
+	m := Mean(xs)
+	var ss, sum float64
+	for _, v := range xs {
+		d := float64(v) - m
+		ss += d * d
+		sum += d
+	}
+	return ss - sum*sum/float64(len(xs))
+}
+
+// Variance returns the sample variance of xs, with n-1 in the
+// denominator: the unbiased estimate of the variance of the population xs
+// was drawn from. It is NaN for fewer than two values.
+func Variance[T Number](xs []T) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	return squares(xs) / float64(len(xs)-1)
+}
+
+// PopVariance returns the population variance of xs, with n in the
+// denominator, for when xs is the whole population. It is NaN for an
+// empty slice.
+func PopVariance[T Number](xs []T) float64 {
+	if len(xs) == 0 {
+		return math.NaN()
+	}
+	return squares(xs) / float64(len(xs))
+}
+
+// StdDev returns the sample standard deviation of xs, the square root of
+// Variance.
+func StdDev[T Number](xs []T) float64 { return math.Sqrt(Variance(xs)) }
+
+// PopStdDev returns the population standard deviation of xs, the square
+// root of PopVariance.
+func PopStdDev[T Number](xs []T) float64 { return math.Sqrt(PopVariance(xs)) }
+
+// Mode returns the most frequent values of xs in increasing order, and
+// how often each occurs. There is more than one mode when values tie. It
+// returns nil and 0 for a slice with no values other than NaN.
+func Mode[T Number](xs []T) (modes []T, count int) {
+	counts := map[T]int{}
+	for _, x := range xs {
+		if !math.IsNaN(float64(x)) {
+			counts[x]++
+		}
+	}
+	for x, n := range counts {
+		switch {
+		case n > count:
+			modes, count = append(modes[:0], x), n
+		case n == count:
+			modes = append(modes, x)
+		}
+	}
+	slices.Sort(modes)
+	return modes, count
+}
+
+// Summary is the usual description of a sample.
+type Summary struct {
+	N            int
+	Mean, StdDev float64
+	Min, Max     float64
+	// Q1, Median and Q3 are the quartiles, as Quantile computes them.
+	Q1, Median, Q3 float64
+}
+
+// Describe returns the summary of xs. StdDev is the sample standard
+// deviation.
+func Describe[T Number](xs []T) Summary {
+	sorted := sortedFloats(xs)
+	return Summary{
+		N:      len(xs),
+		Mean:   Mean(xs),
+		StdDev: StdDev(xs),
+		Min:    QuantileSorted(sorted, 0),
+		Q1:     QuantileSorted(sorted, 0.25),
+		Median: QuantileSorted(sorted, 0.5),
+		Q3:     QuantileSorted(sorted, 0.75),
+		Max:    QuantileSorted(sorted, 1),
+	}
+}
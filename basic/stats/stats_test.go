@@ -0,0 +1,312 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// within checks that got is within tol of want, or that both are NaN.
+func within(t *testing.T, what string, got, want, tol float64) {
+	t.Helper()
+	if math.IsNaN(want) && math.IsNaN(got) {
+		return
+	}
+	if !(math.Abs(got-want) <= tol) {
+		t.Errorf("%s: got %v, want %v ± %v", what, got, want, tol)
+	}
+}
+
+func TestSum(t *testing.T) {
+	within(t, "0.1 ten times", Sum(slices.Repeat([]float64{0.1}, 10)), 1, 0)
+	within(t, "1 + 1e100 + 1 - 1e100", Sum([]float64{1, 1e100, 1, -1e100}), 2, 0)
+	within(t, "sum of ints", Sum([]int{1, 2, 3, 4, 5}), 15, 0)
+}
+
+func TestMean(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		xs   []float64
+		want float64
+	}{
+		{nil, math.NaN()},
+		{[]float64{1, 2, 3, 4}, 2.5},
+		{[]float64{math.MaxFloat64, math.MaxFloat64}, math.MaxFloat64},
+		{[]float64{math.MaxFloat64, math.MaxFloat64 / 2}, 0.75 * math.MaxFloat64},
+		// An infinite value makes the mean infinite, wherever it is.
+		{[]float64{inf, 1}, inf},
+		{[]float64{1, inf}, inf},
+		{[]float64{math.MaxFloat64, math.MaxFloat64, inf}, inf},
+		{[]float64{-inf, math.MaxFloat64, math.MaxFloat64}, -inf},
+		{[]float64{inf, -inf}, math.NaN()},
+		{[]float64{1, math.NaN()}, math.NaN()},
+	}
+	for _, tt := range tests {
+		got := Mean(tt.xs)
+		if got != tt.want && !(math.IsNaN(got) && math.IsNaN(tt.want)) {
+			t.Errorf("Mean(%v) = %v, want %v", tt.xs, got, tt.want)
+		}
+	}
+}
+
+// TestNIST checks two of NIST's StRD univariate data sets, with certified
+// mean and standard deviation. NumAcc4 is 1000000000.2 followed by
+// 1000000000.1 and 1000000000.3 500 times each: there the textbook formula
+// Σx² - n·mean², in float64, gives a standard deviation over 100.
+func TestNIST(t *testing.T) {
+	acc1 := []int{10000001, 10000003, 10000002}
+	acc4 := []float64{1000000000.2}
+	for range 500 {
+		acc4 = append(acc4, 1000000000.1, 1000000000.3)
+	}
+	var run Running
+	for _, x := range acc4 {
+		run.Add(x)
+	}
+	within(t, "NumAcc1 mean", Mean(acc1), 10000002, 0)
+	within(t, "NumAcc1 standard deviation", StdDev(acc1), 1, 0)
+	// The values themselves are only stored to within 6e-8.
+	within(t, "NumAcc4 mean", Mean(acc4), 1000000000.2, 1e-6)
+	within(t, "NumAcc4 standard deviation", StdDev(acc4), 0.1, 1e-6)
+	within(t, "NumAcc4 running mean", run.Mean(), 1000000000.2, 1e-6)
+	within(t, "NumAcc4 running standard deviation", run.StdDev(), 0.1, 1e-6)
+}
+
+// TestRunning checks that running statistics match the batch ones, whole
+// and merged.
+func TestRunning(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	xs := make([]float64, 10000)
+	for i := range xs {
+		xs[i] = 50 + 10*r.NormFloat64()
+	}
+	var all, a, b Running
+	for i, x := range xs {
+		all.Add(x)
+		if i < 3000 {
+			a.Add(x)
+		} else {
+			b.Add(x)
+		}
+	}
+	a.Merge(b)
+	for _, run := range []*Running{&all, &a} {
+		within(t, "n", float64(run.N()), float64(len(xs)), 0)
+		within(t, "mean", run.Mean(), Mean(xs), 1e-12)
+		within(t, "variance", run.Variance(), Variance(xs), 1e-9)
+		within(t, "population variance", run.PopVariance(), PopVariance(xs), 1e-9)
+		within(t, "min", run.Min(), slices.Min(xs), 0)
+		within(t, "max", run.Max(), slices.Max(xs), 0)
+	}
+}
+
+// TestQuantile checks the quantiles against R's type 7:
+// quantile(c(2, 4, 4, 4, 5, 5, 7, 9), c(.1, .25, .5, .75, .9)) in R gives
+// 3.4 4 4.5 5.5 7.6.
+func TestQuantile(t *testing.T) {
+	xs := []int{9, 4, 2, 5, 4, 7, 4, 5}
+	for q, want := range map[float64]float64{0: 2, 0.1: 3.4, 0.25: 4, 0.5: 4.5, 0.75: 5.5, 0.9: 7.6, 1: 9} {
+		within(t, fmt.Sprintf("quantile %v", q), Quantile(xs, q), want, 1e-12)
+	}
+	within(t, "median of an odd count", Median([]float64{3, 1, 2}), 2, 0)
+	within(t, "quantile with a NaN", Quantile([]float64{1, math.NaN()}, 0.5), math.NaN(), 0)
+}
+
+// TestQuantileExtremes checks that an infinity or a gap too wide for
+// float64 next to a quantile does not make it NaN.
+func TestQuantileExtremes(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		xs   []float64
+		q    float64
+		want float64
+	}{
+		{[]float64{-1, 5, inf}, 0.5, 5},
+		{[]float64{-1, 5, inf}, 0.75, inf},
+		{[]float64{-inf, 5, 7}, 0.25, -inf},
+		{[]float64{-inf, 5, 7}, 0.5, 5},
+		{[]float64{inf, inf}, 0.5, inf},
+		{[]float64{-1e308, 1e308}, 0.5, 0},
+		{[]float64{-1e308, 1e308}, 0.75, 5e307},
+	}
+	for _, tt := range tests {
+		if got := Quantile(tt.xs, tt.q); got != tt.want {
+			t.Errorf("Quantile(%v, %v) = %v, want %v", tt.xs, tt.q, got, tt.want)
+		}
+	}
+	s := Describe([]float64{1, inf})
+	if s.Min != 1 || s.Max != inf || s.Median != inf {
+		t.Errorf("Describe(1, +Inf) = %+v", s)
+	}
+}
+
+func TestMode(t *testing.T) {
+	if modes, n := Mode([]float64{3, 1, 2, 2, math.NaN(), 3, math.NaN(), 4}); !slices.Equal(modes, []float64{2, 3}) || n != 2 {
+		t.Errorf("modes of 3 1 2 2 NaN 3 NaN 4: got %v, %d times", modes, n)
+	}
+	if modes, n := Mode([]int{}); modes != nil || n != 0 {
+		t.Errorf("modes of nothing: got %v, %d times", modes, n)
+	}
+}
+
+// TestTDigest checks that the t-digest's quantiles are within 0.2% of
+// their rank, whole and merged.
+func TestTDigest(t *testing.T) {
+	for _, dist := range []string{"normal", "exponential", "uniform"} {
+		r := rand.New(rand.NewPCG(1, 2))
+		xs := make([]float64, 100000)
+		whole := NewTDigest(100)
+		parts := []*TDigest{NewTDigest(100), NewTDigest(100), NewTDigest(100)}
+		for i := range xs {
+			switch dist {
+			case "normal":
+				xs[i] = r.NormFloat64()
+			case "exponential":
+				xs[i] = r.ExpFloat64()
+			default:
+				xs[i] = r.Float64()
+			}
+			whole.Add(xs[i])
+			parts[i%3].Add(xs[i])
+		}
+		merged := NewTDigest(100)
+		for _, p := range parts {
+			merged.Merge(p)
+		}
+		slices.Sort(xs)
+		for _, td := range []*TDigest{whole, merged} {
+			if td.Count() != len(xs) || td.Centroids() > 100 {
+				t.Errorf("%s: %d values in %d centroids", dist, td.Count(), td.Centroids())
+			}
+			for _, q := range []float64{0, 0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1} {
+				// The rank of the estimate, as a fraction of the data,
+				// should be close to q.
+				rank := float64(sort.SearchFloat64s(xs, td.Quantile(q))) / float64(len(xs))
+				within(t, fmt.Sprintf("%s: rank of quantile %v", dist, q), rank, q, 0.002)
+			}
+			if td.Quantile(0) != xs[0] || td.Quantile(1) != xs[len(xs)-1] {
+				t.Errorf("%s: the extreme quantiles are not the minimum and maximum", dist)
+			}
+		}
+	}
+}
+
+// anscombe is Anscombe's quartet: four data sets with the same means,
+// variances, correlation and regression line to two or three places,
+// which look nothing alike when drawn. The first three share x.
+var anscombe = struct {
+	x, x4 []float64
+	y     [4][]float64
+}{
+	x:  []float64{10, 8, 13, 9, 11, 14, 6, 4, 12, 7, 5},
+	x4: []float64{8, 8, 8, 8, 8, 8, 8, 19, 8, 8, 8},
+	y: [4][]float64{
+		{8.04, 6.95, 7.58, 8.81, 8.33, 9.96, 7.24, 4.26, 10.84, 4.82, 5.68},
+		{9.14, 8.14, 8.74, 8.77, 9.26, 8.10, 6.13, 3.10, 9.13, 7.26, 4.74},
+		{7.46, 6.77, 12.74, 7.11, 7.81, 8.84, 6.08, 5.39, 8.15, 6.42, 5.73},
+		{6.58, 5.76, 7.71, 8.84, 8.47, 7.04, 5.25, 12.50, 5.56, 7.91, 6.89},
+	},
+}
+
+func TestAnscombe(t *testing.T) {
+	for i, y := range anscombe.y {
+		x := anscombe.x
+		if i == 3 {
+			x = anscombe.x4
+		}
+		l := LinearRegression(x, y)
+		set := fmt.Sprintf("set %d ", i+1)
+		within(t, set+"mean of x", Mean(x), 9, 1e-12)
+		within(t, set+"variance of x", Variance(x), 11, 1e-12)
+		within(t, set+"mean of y", Mean(y), 7.50, 0.005)
+		within(t, set+"variance of y", Variance(y), 4.125, 0.005)
+		within(t, set+"correlation", Correlation(x, y), 0.816, 0.001)
+		within(t, set+"slope", l.Slope, 0.500, 0.001)
+		within(t, set+"intercept", l.Intercept, 3.00, 0.005)
+		within(t, set+"R²", l.R2, 0.67, 0.005)
+	}
+}
+
+func TestExactLines(t *testing.T) {
+	xs := []int{1, 2, 3, 4, 5}
+	ys := []int{5, 7, 9, 11, 13}
+	l := LinearRegression(xs, ys)
+	flat := LinearRegression([]int{2, 2, 2}, []int{1, 2, 3})
+	within(t, "slope", l.Slope, 2, 1e-12)
+	within(t, "intercept", l.Intercept, 3, 1e-12)
+	within(t, "R²", l.R2, 1, 0)
+	within(t, "line at 10", l.At(10), 23, 1e-12)
+	within(t, "covariance", Covariance(xs, ys), 5, 1e-12)
+	within(t, "correlation of y = -x", Correlation(xs, []int{-1, -2, -3, -4, -5}), -1, 0)
+	within(t, "correlation with a constant", Correlation(xs, []int{4, 4, 4, 4, 4}), math.NaN(), 0)
+	within(t, "slope through a vertical line", flat.Slope, math.NaN(), 0)
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 5)
+	if !slices.Equal(h.Counts, []int{2, 2, 2, 2, 2}) {
+		t.Errorf("0 to 9 in 5 bins: %v", h.Counts)
+	}
+	h = NewHistogram([]float64{1, 1, 1, 2, 3, 3, math.NaN()}, 2)
+	var b strings.Builder
+	h.Write(&b, 4)
+	if want := "[1, 2) 3 ████\n[2, 3] 3 ████\n"; b.String() != want {
+		t.Errorf("got\n%swant\n%s", b.String(), want)
+	}
+	h = NewHistogram(append([]int{1}, slices.Repeat([]int{2}, 99)...), 2)
+	b.Reset()
+	h.Write(&b, 1)
+	if want := "[  1, 1.5)  1 ▏\n[1.5,   2] 99 █\n"; b.String() != want {
+		t.Errorf("a bin of 1 next to one of 99 should still show:\n%s", b.String())
+	}
+	if got := NewHistogram([]float64{5, 5, 5}, 10); len(got.Counts) != 1 || got.Counts[0] != 3 {
+		t.Errorf("equal values: %+v", got)
+	}
+	within(t, "Sturges' rule for 100 values", float64(Sturges(100)), 8, 0)
+}
+
+// TestHistogramExtremes checks infinities, which are skipped, and a range
+// too wide for float64.
+func TestHistogramExtremes(t *testing.T) {
+	inf := math.Inf(1)
+	h := NewHistogram([]float64{1, 2, inf, -inf}, 2)
+	if h.Min != 1 || h.Width != 0.5 || !slices.Equal(h.Counts, []int{1, 1}) {
+		t.Errorf("1 2 +Inf -Inf: %+v", h)
+	}
+	if got := NewHistogram([]float64{inf, math.NaN()}, 3); got.Counts != nil {
+		t.Errorf("no finite values: %+v", got)
+	}
+	h = NewHistogram([]float64{1e308, -1e308, 0, 5e307}, 4)
+	if !slices.Equal(h.Counts, []int{1, 0, 1, 2}) {
+		t.Errorf("-1e308 to 1e308: %+v", h)
+	}
+	if lo, hi := h.Bin(3); lo != 5e307 || hi != 1e308 {
+		t.Errorf("last bin of -1e308 to 1e308: [%v, %v]", lo, hi)
+	}
+	var b strings.Builder
+	if err := h.Write(&b, 4); err != nil || strings.Contains(b.String(), "Inf") {
+		t.Errorf("got %v\n%s", err, b.String())
+	}
+}
+
+// sparkline checks the sparkline of xs.
+func sparkline[T Number](t *testing.T, xs []T, want string) {
+	t.Helper()
+	if got := Sparkline(xs); got != want {
+		t.Errorf("sparkline of %v: got %s, want %s", xs, got, want)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	sparkline(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, "▁▂▃▄▅▆▇█")
+	sparkline(t, []float64{0, 10, math.NaN(), 5}, "▁█ ▅")
+	sparkline(t, []int{7, 7, 7}, "▄▄▄")
+	inf := math.Inf(1)
+	sparkline(t, []float64{1, inf}, "▄█")
+	sparkline(t, []float64{-inf, 0, 10, inf}, "▁▁██")
+	sparkline(t, []float64{1e308, -1e308, 0}, "█▁▅")
+}
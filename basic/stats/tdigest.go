@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// TDigest estimates quantiles of a stream of values in bounded memory,
+// by Dunning and Ertl's t-digest.
+//
+// It summarizes the values as centroids: clusters with a mean and a
+// weight, kept in order. A centroid may only grow as large as its
+// position allows. Near the median a cluster can hold a few percent of
+// the data, but towards the tails clusters must be small, down to single
+// values at the ends. So the extreme quantiles, which are usually the
+// ones that matter, come out much more accurate than the median, as a
+// fraction of the data. New values wait in a buffer and are merged into
+// the centroids in one sorted pass when it fills.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min, max    float64
+}
+
+type centroid struct {
+	mean, weight float64
+}
+
+// NewTDigest returns an empty digest. Compression bounds the number of
+// centroids, and so the memory used and the accuracy: about compression/2
+// centroids are kept. 100 is a good default and is used if compression is
+// not positive.
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = 100
+	}
+	return &TDigest{compression: compression, min: math.Inf(1), max: math.Inf(-1)}
+}
+
+// Add adds x to the digest. NaNs are ignored.
+func (t *TDigest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	t.add(centroid{x, 1})
+	t.min, t.max = min(t.min, x), max(t.max, x)
+}
+
+func (t *TDigest) add(c centroid) {
+	t.buffer = append(t.buffer, c)
+	t.count += c.weight
+	if len(t.buffer) >= int(5*t.compression) {
+		t.flush()
+	}
+}
+
+// Merge adds the values summarized by other to t. Digests of parts of a
+// stream can be built in parallel and merged at the end, with little more
+// error than a single digest of the whole.
+func (t *TDigest) Merge(other *TDigest) {
+	for _, c := range other.centroids {
+		t.add(c)
+	}
+	for _, c := range other.buffer {
+		t.add(c)
+	}
+	t.min, t.max = min(t.min, other.min), max(t.max, other.max)
+}
+
+// Count returns the number of values added.
+func (t *TDigest) Count() int { return int(t.count) }
+
+// Centroids returns the number of centroids the values are summarized in.
+func (t *TDigest) Centroids() int {
+	t.flush()
+	return len(t.centroids)
+}
+
+// scale is the t-digest's k₁ scale function. It maps a quantile to a
+// number of centroids so that its slope, and with it the number of
+// centroids per unit of quantile, grows without bound towards 0 and 1.
+// A centroid may span at most 1 on this scale.
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// flush merges the buffer into the centroids: it sorts both together and
+// walks through them, merging each centroid into the one before while
+// the result spans at most 1 on the scale.
+func (t *TDigest) flush() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	slices.SortFunc(all, func(a, b centroid) int { return cmp.Compare(a.mean, b.mean) })
+	t.buffer = t.buffer[:0]
+
+	merged := all[:1]
+	before := 0.0 // the weight of the centroids before the current one
+	for _, c := range all[1:] {
+		cur := &merged[len(merged)-1]
+		if t.scale((before+cur.weight+c.weight)/t.count)-t.scale(before/t.count) <= 1 {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		before += cur.weight
+		merged = append(merged, c)
+	}
+	t.centroids = merged
+}
+
+// Quantile returns an estimate of the q-quantile of the values, for q
+// from 0 to 1, or NaN if there are none. It interpolates between the
+// means of neighbouring centroids, taking each mean to sit at the middle
+// of its centroid's weight, and between the outermost centroids and the
+// exact minimum and maximum.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.flush()
+	if len(t.centroids) == 0 || math.IsNaN(q) {
+		return math.NaN()
+	}
+	q = min(max(q, 0), 1)
+	target := q * t.count
+
+	first, last := t.centroids[0], t.centroids[len(t.centroids)-1]
+	if target <= first.weight/2 {
+		if first.weight == 1 {
+			return t.min
+		}
+		return t.min + (first.mean-t.min)*target/(first.weight/2)
+	}
+	if target >= t.count-last.weight/2 {
+		if last.weight == 1 {
+			return t.max
+		}
+		return t.max - (t.max-last.mean)*(t.count-target)/(last.weight/2)
+	}
+
+	// centre is the weight below the middle of centroid i.
+	centre := first.weight / 2
+	for i := 1; i < len(t.centroids); i++ {
+		a, b := t.centroids[i-1], t.centroids[i]
+		next := centre + (a.weight+b.weight)/2
+		if target <= next {
+			return a.mean + (b.mean-a.mean)*(target-centre)/(next-centre)
+		}
+		centre = next
+	}
+	return last.mean
+}
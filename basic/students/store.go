@@ -0,0 +1,246 @@
+package students
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"unicode/utf8"
+)
+
+// The record file format stores the name → age map compactly:
+//
+//	header:  magic "STDB", version (uvarint)
+//	record:  body length (uvarint), body, CRC-32 (IEEE) of body (4 bytes LE)
+//	body:    fields, each a key (uvarint) followed by its value
+//
+// A field key is number<<1 | kind, where kind 0 means a varint value and
+// kind 1 a length-prefixed byte string. Version 1 defines field 1 (name,
+// UTF-8 bytes) and field 2 (age, zig-zag varint). A later version may only
+// add fields, so readers accept files of any version and skip the fields
+// they do not know.
+const (
+	magic          = "STDB"
+	formatVersion  = 1
+	maxRecordBytes = 1 << 16
+
+	kindVarint = 0
+	kindBytes  = 1
+
+	fieldName = 1
+	fieldAge  = 2
+)
+
+// ErrFormat is wrapped by every error about malformed record files.
+var ErrFormat = errors.New("students: malformed record file")
+
+// FormatError locates a problem in a record file.
+type FormatError struct {
+	Offset int64 // byte offset of the record or header
+	Record int   // 0-based record index, -1 for the header
+	Msg    string
+}
+
+func (e *FormatError) Error() string {
+	if e.Record < 0 {
+		return fmt.Sprintf("students: header: %s", e.Msg)
+	}
+	return fmt.Sprintf("students: record %d at offset %d: %s", e.Record, e.Offset, e.Msg)
+}
+
+func (e *FormatError) Unwrap() error { return ErrFormat }
+
+// Save writes m to w in the record file format, in name order so equal maps
+// produce identical files.
+func Save(w io.Writer, m map[string]int) error {
+	bw := bufio.NewWriter(w)
+	buf := binary.AppendUvarint([]byte(magic), formatVersion)
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	var body []byte
+	for _, name := range SortedNames(m) {
+		if !utf8.ValidString(name) {
+			return fmt.Errorf("students: name %q is not valid UTF-8", name)
+		}
+		body = binary.AppendUvarint(body[:0], fieldName<<1|kindBytes)
+		body = binary.AppendUvarint(body, uint64(len(name)))
+		body = append(body, name...)
+		body = binary.AppendUvarint(body, fieldAge<<1|kindVarint)
+		body = binary.AppendVarint(body, int64(m[name]))
+		if len(body) > maxRecordBytes {
+			return fmt.Errorf("students: record for %.20q exceeds %d bytes", name, maxRecordBytes)
+		}
+
+		buf = binary.AppendUvarint(buf[:0], uint64(len(body)))
+		buf = append(buf, body...)
+		buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(body))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Load reads a record file written by Save.
+func Load(r io.Reader) (map[string]int, error) {
+	cr := &countingReader{r: bufio.NewReader(r)}
+	head := make([]byte, len(magic))
+	if _, err := io.ReadFull(cr, head); err != nil || string(head) != magic {
+		return nil, &FormatError{Record: -1, Msg: "missing STDB magic"}
+	}
+	version, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return nil, &FormatError{Record: -1, Msg: "missing version"}
+	}
+	if version == 0 {
+		return nil, &FormatError{Record: -1, Msg: "version 0"}
+	}
+
+	m := map[string]int{}
+	body := make([]byte, 0, 64)
+	for i := 0; ; i++ {
+		start := cr.n
+		size, err := binary.ReadUvarint(cr)
+		if err == io.EOF {
+			return m, nil
+		}
+		bad := func(format string, args ...any) error {
+			return &FormatError{Offset: start, Record: i, Msg: fmt.Sprintf(format, args...)}
+		}
+		if err != nil {
+			return nil, bad("bad length: %v", err)
+		}
+		if size > maxRecordBytes {
+			return nil, bad("length %d exceeds %d bytes", size, maxRecordBytes)
+		}
+		body = slices.Grow(body[:0], int(size))[:size]
+		var sum [4]byte
+		if _, err := io.ReadFull(cr, body); err != nil {
+			return nil, bad("truncated body")
+		}
+		if _, err := io.ReadFull(cr, sum[:]); err != nil {
+			return nil, bad("truncated checksum")
+		}
+		if got, want := crc32.ChecksumIEEE(body), binary.LittleEndian.Uint32(sum[:]); got != want {
+			return nil, bad("checksum mismatch (%08x != %08x)", got, want)
+		}
+		name, age, err := decodeRecord(body)
+		if err != nil {
+			return nil, bad("%v", err)
+		}
+		m[name] = age
+	}
+}
+
+// decodeRecord extracts the known fields of one record body, skipping any
+// others.
+func decodeRecord(body []byte) (name string, age int, err error) {
+	var haveName, haveAge bool
+	for len(body) > 0 {
+		key, n := binary.Uvarint(body)
+		if n <= 0 {
+			return "", 0, errors.New("bad field key")
+		}
+		body = body[n:]
+		num, kind := key>>1, key&1
+
+		var ival int64
+		var bval []byte
+		if kind == kindVarint {
+			ival, n = binary.Varint(body)
+			if n <= 0 {
+				return "", 0, fmt.Errorf("bad varint in field %d", num)
+			}
+			body = body[n:]
+		} else {
+			size, n := binary.Uvarint(body)
+			if n <= 0 || size > uint64(len(body)-n) {
+				return "", 0, fmt.Errorf("bad length in field %d", num)
+			}
+			bval = body[n : n+int(size)]
+			body = body[n+int(size):]
+		}
+
+		switch {
+		case num == fieldName && kind == kindBytes:
+			if !utf8.Valid(bval) {
+				return "", 0, errors.New("name is not valid UTF-8")
+			}
+			name, haveName = string(bval), true
+		case num == fieldAge && kind == kindVarint:
+			age, haveAge = int(ival), true
+		}
+	}
+	if !haveName || !haveAge {
+		return "", 0, errors.New("record lacks a name or an age")
+	}
+	return name, age, nil
+}
+
+// countingReader tracks the offset for error messages.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// SaveFile writes m to path atomically: the data goes to a temporary file
+// that replaces path only once it is complete.
+func SaveFile(path string, m map[string]int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := Save(tmp, m); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadFile reads a record file from path.
+func LoadFile(path string) (map[string]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// DefaultPath is the record file that `basic students save` updates and
+// `basic students dump` prints unless given another: a file in the user's
+// cache directory, falling back to the temp directory.
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "golang_learning", "students.db")
+}
@@ -0,0 +1,167 @@
+package students
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"maps"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// seedMaps are the maps the round-trip tests and the fuzzer start from.
+var seedMaps = []map[string]int{
+	{},
+	{"Alice": 30, "Bob": 25},
+	{"سارا": 22, "": 0, "neg": -7, "big": 1 << 40},
+	{strings.Repeat("a", 100): 3},
+	{strings.Repeat("long name ", 1000): 99, "short": 1},
+}
+
+func save(t testing.TB, m map[string]int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Save(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, m := range seedMaps {
+		got, err := Load(bytes.NewReader(save(t, m)))
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if !maps.Equal(got, m) {
+			t.Errorf("got %v, want %v", got, m)
+		}
+	}
+}
+
+func TestSaveIsDeterministic(t *testing.T) {
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+	if !bytes.Equal(save(t, m), save(t, maps.Clone(m))) {
+		t.Error("equal maps saved to different bytes")
+	}
+}
+
+// record builds one record around body, with a correct checksum.
+func record(body []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(len(body)))
+	out = append(out, body...)
+	return binary.LittleEndian.AppendUint32(out, crc32.ChecksumIEEE(body))
+}
+
+// TestFutureVersion reads a file as a later version might write it: a
+// higher version number and a field this reader has never heard of.
+func TestFutureVersion(t *testing.T) {
+	var body []byte
+	body = binary.AppendUvarint(body, fieldName<<1|kindBytes)
+	body = binary.AppendUvarint(body, 5)
+	body = append(body, "Alice"...)
+	body = binary.AppendUvarint(body, 3<<1|kindBytes) // field 3: email
+	body = binary.AppendUvarint(body, 17)
+	body = append(body, "alice@example.com"...)
+	body = binary.AppendUvarint(body, 4<<1|kindVarint) // field 4: a number
+	body = binary.AppendVarint(body, 12345)
+	body = binary.AppendUvarint(body, fieldAge<<1|kindVarint)
+	body = binary.AppendVarint(body, 30)
+
+	file := binary.AppendUvarint([]byte(magic), formatVersion+1)
+	file = append(file, record(body)...)
+	got, err := Load(bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, map[string]int{"Alice": 30}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestCorruptFiles(t *testing.T) {
+	good := save(t, map[string]int{"Alice": 30, "Bob": 25})
+	flip := bytes.Clone(good)
+	flip[len(flip)-6] ^= 1 // a byte of Bob's body
+	tests := []struct {
+		name   string
+		data   []byte
+		record int
+	}{
+		{"empty", nil, -1},
+		{"wrong magic", []byte("STDX\x01"), -1},
+		{"no version", []byte(magic), -1},
+		{"version 0", []byte(magic + "\x00"), -1},
+		{"truncated body", good[:len(good)-6], 1},
+		{"truncated checksum", good[:len(good)-2], 1},
+		{"flipped bit", flip, 1},
+		{"huge length", append(bytes.Clone(good[:5]), 0xff, 0xff, 0xff, 0x7f), 0},
+		{"no age", append(bytes.Clone(good[:5]), record([]byte{fieldName<<1 | kindBytes, 1, 'x'})...), 0},
+		{"bad UTF-8", append(bytes.Clone(good[:5]), record([]byte{fieldName<<1 | kindBytes, 1, 0xff, fieldAge << 1, 2})...), 0},
+	}
+	for _, tt := range tests {
+		_, err := Load(bytes.NewReader(tt.data))
+		var fe *FormatError
+		if !errors.As(err, &fe) || !errors.Is(err, ErrFormat) {
+			t.Errorf("%s: got %v, want a *FormatError", tt.name, err)
+			continue
+		}
+		if fe.Record != tt.record {
+			t.Errorf("%s: error in record %d (%v), want %d", tt.name, fe.Record, err, tt.record)
+		}
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "students.db")
+	m := map[string]int{"Alice": 30}
+	if err := SaveFile(path, m); err != nil {
+		t.Fatal(err)
+	}
+	m["Bob"] = 25
+	if err := SaveFile(path, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(got, m) {
+		t.Errorf("got %v, want %v", got, m)
+	}
+	if left, _ := filepath.Glob(path + ".tmp*"); len(left) > 0 {
+		t.Errorf("temporary files left behind: %v", left)
+	}
+}
+
+// FuzzLoad checks that Load never panics, whatever the input, and that
+// whatever it accepts saves and loads back to the same map.
+func FuzzLoad(f *testing.F) {
+	for _, m := range seedMaps {
+		data := save(f, m)
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, err := Load(bytes.NewReader(data))
+		if err != nil {
+			if !errors.Is(err, ErrFormat) {
+				t.Fatalf("error %v does not wrap ErrFormat", err)
+			}
+			return
+		}
+		var buf bytes.Buffer
+		if err := Save(&buf, m); err != nil {
+			t.Fatalf("saving a loaded map: %v", err)
+		}
+		again, err := Load(&buf)
+		if err != nil {
+			t.Fatalf("loading a saved map: %v", err)
+		}
+		if !maps.Equal(again, m) {
+			t.Fatalf("round trip changed %v to %v", m, again)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+// Package students loads the name → age data used by the maps lesson from
+// external files.
+package students
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/amiiralihassanpour/golang_learning/validate"
+)
+
+// Student is one imported row.
+type Student struct {
+	Name string `validate:"required"`
+	Age  int    `validate:"min=0,max=150"`
+}
+
+// DuplicatePolicy decides what happens when a name appears twice.
+type DuplicatePolicy int
+
+const (
+	// Overwrite keeps the last row for a name.
+	Overwrite DuplicatePolicy = iota
+	// Skip keeps the first row for a name.
+	Skip
+	// Fail stops the import with a *DuplicateError.
+	Fail
+)
+
+// ParseDuplicatePolicy converts "overwrite", "skip" or "fail".
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	switch strings.ToLower(s) {
+	case "overwrite":
+		return Overwrite, nil
+	case "skip":
+		return Skip, nil
+	case "fail":
+		return Fail, nil
+	}
+	return 0, fmt.Errorf("unknown duplicate policy %q (want overwrite, skip or fail)", s)
+}
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case Overwrite:
+		return "overwrite"
+	case Skip:
+		return "skip"
+	case Fail:
+		return "fail"
+	}
+	return fmt.Sprintf("DuplicatePolicy(%d)", int(p))
+}
+
+// Options configures Import. The zero value reads comma-separated files
+// with "name" and "age" header columns and overwrites duplicates.
+type Options struct {
+	// Comma is the field separator; 0 means ','.
+	Comma rune
+	// Headers maps header text in the file to the fields "name" and "age",
+	// e.g. {"Student Name": "name"}. Headers are matched case-insensitively;
+	// columns named "name" and "age" are recognised without a mapping.
+	Headers    map[string]string
+	Duplicates DuplicatePolicy
+}
+
+// RowError describes a row that could not be imported. Line and Column are
+// 1-based positions in the file.
+type RowError struct {
+	Line   int
+	Column int
+	Field  string
+	Reason string
+}
+
+func (e *RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Reason)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Field, e.Reason)
+}
+
+// DuplicateError is returned with the Fail policy.
+type DuplicateError struct {
+	Name      string
+	Line      int
+	FirstLine int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("line %d: duplicate name %q (first seen on line %d)", e.Line, e.Name, e.FirstLine)
+}
+
+// Summary holds statistics about an import.
+type Summary struct {
+	Rows        int // data rows read, excluding the header
+	Imported    int // rows stored in the map
+	Overwritten int // rows that replaced an earlier row with the same name
+	Skipped     int // duplicate rows ignored by the Skip policy
+	Invalid     int // rows rejected with a RowError
+	MinAge      int
+	MaxAge      int
+	MeanAge     float64
+}
+
+// Result is the outcome of Import.
+type Result struct {
+	Students map[string]int
+	Errors   []*RowError
+	Summary  Summary
+}
+
+// Import reads CSV from r. Rows that cannot be converted are reported in
+// Result.Errors and do not stop the import; only I/O errors, a missing
+// header column or a duplicate under the Fail policy do.
+func Import(r io.Reader, opts Options) (*Result, error) {
+	r, err := decodeText(r)
+	if err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty file: missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	nameCol, ageCol, err := mapHeader(header, opts.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Students: map[string]int{}}
+	firstLine := map[string]int{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			res.Summary.Rows++
+			res.Summary.Invalid++
+			res.Errors = append(res.Errors, &RowError{Line: perr.Line, Column: perr.Column, Reason: perr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Summary.Rows++
+		line, _ := cr.FieldPos(0)
+
+		s, rerr := convertRow(cr, record, nameCol, ageCol)
+		if rerr != nil {
+			res.Summary.Invalid++
+			res.Errors = append(res.Errors, rerr)
+			continue
+		}
+
+		if first, dup := firstLine[s.Name]; dup {
+			switch opts.Duplicates {
+			case Fail:
+				return res, &DuplicateError{Name: s.Name, Line: line, FirstLine: first}
+			case Skip:
+				res.Summary.Skipped++
+				continue
+			case Overwrite:
+				res.Summary.Overwritten++
+			}
+		} else {
+			firstLine[s.Name] = line
+		}
+		res.Students[s.Name] = s.Age
+	}
+	res.Summary.summarize(res.Students)
+	return res, nil
+}
+
+// convertRow turns one record into a Student, pointing errors at the
+// offending field.
+func convertRow(cr *csv.Reader, record []string, nameCol, ageCol int) (Student, *RowError) {
+	fieldErr := func(col int, field, reason string) *RowError {
+		if col >= len(record) {
+			col = len(record) - 1
+		}
+		line, column := cr.FieldPos(col)
+		return &RowError{Line: line, Column: column, Field: field, Reason: reason}
+	}
+	if nameCol >= len(record) || ageCol >= len(record) {
+		return Student{}, fieldErr(len(record), "", fmt.Sprintf("row has %d fields, expected at least %d", len(record), max(nameCol, ageCol)+1))
+	}
+
+	s := Student{Name: strings.TrimSpace(record[nameCol])}
+	ageText := strings.TrimSpace(record[ageCol])
+	age, err := strconv.Atoi(ageText)
+	if err != nil {
+		reason := fmt.Sprintf("invalid number %q", ageText)
+		if ageText == "" {
+			reason = "missing value"
+		}
+		return Student{}, fieldErr(ageCol, "age", reason)
+	}
+	s.Age = age
+
+	if err := validate.Struct(s); err != nil {
+		var verrs validate.ValidationErrors
+		if errors.As(err, &verrs) {
+			e := verrs[0]
+			col := nameCol
+			if e.Field == "Age" {
+				col = ageCol
+			}
+			return Student{}, fieldErr(col, strings.ToLower(e.Field), e.Translate(rowMessages))
+		}
+		return Student{}, fieldErr(0, "", err.Error())
+	}
+	return s, nil
+}
+
+// rowMessages words validation failures relative to the field, which
+// RowError already names.
+var rowMessages = validate.Catalog{
+	"":         "failed the {tag} rule",
+	"required": "missing value",
+	"min":      "must be at least {param}, got {value}",
+	"max":      "must be at most {param}, got {value}",
+}
+
+func mapHeader(header []string, aliases map[string]string) (nameCol, ageCol int, err error) {
+	lookup := map[string]string{"name": "name", "age": "age"}
+	for from, to := range aliases {
+		lookup[strings.ToLower(strings.TrimSpace(from))] = strings.ToLower(to)
+	}
+	nameCol, ageCol = -1, -1
+	for i, h := range header {
+		switch lookup[strings.ToLower(strings.TrimSpace(h))] {
+		case "name":
+			nameCol = i
+		case "age":
+			ageCol = i
+		}
+	}
+	var missing []string
+	if nameCol < 0 {
+		missing = append(missing, "name")
+	}
+	if ageCol < 0 {
+		missing = append(missing, "age")
+	}
+	if len(missing) > 0 {
+		return 0, 0, fmt.Errorf("header %q has no %s column (use a header mapping)", strings.Join(header, ","), strings.Join(missing, " or "))
+	}
+	return nameCol, ageCol, nil
+}
+
+func (s *Summary) summarize(m map[string]int) {
+	s.Imported = len(m)
+	if len(m) == 0 {
+		return
+	}
+	s.MinAge, s.MaxAge = math.MaxInt, math.MinInt
+	total := 0
+	for _, age := range m {
+		s.MinAge = min(s.MinAge, age)
+		s.MaxAge = max(s.MaxAge, age)
+		total += age
+	}
+	s.MeanAge = float64(total) / float64(len(m))
+}
+
+// decodeText strips a UTF-8 byte order mark and converts UTF-16 input
+// (recognised by its BOM, as written by spreadsheet programs) to UTF-8.
+func decodeText(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	bom, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(bom, []byte{0xEF, 0xBB, 0xBF}):
+		br.Discard(3)
+		return br, nil
+	case bytes.HasPrefix(bom, []byte{0xFF, 0xFE}), bytes.HasPrefix(bom, []byte{0xFE, 0xFF}):
+		data, err := io.ReadAll(br)
+		if err != nil {
+			return nil, err
+		}
+		if len(data)%2 != 0 {
+			return nil, errors.New("UTF-16 input has an odd number of bytes")
+		}
+		little := data[0] == 0xFF
+		units := make([]uint16, 0, len(data)/2-1)
+		for i := 2; i < len(data); i += 2 {
+			if little {
+				units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+			} else {
+				units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+			}
+		}
+		return strings.NewReader(string(utf16.Decode(units))), nil
+	}
+	return br, nil
+}
+
+// SortedNames returns the keys of m in alphabetical order, for stable
+// output.
+func SortedNames(m map[string]int) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
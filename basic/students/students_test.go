@@ -0,0 +1,184 @@
+package students
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// messyOptions reads testdata/messy.csv, which is separated by semicolons
+// and names its name column "Student Name".
+var messyOptions = Options{Comma: ';', Headers: map[string]string{"Student Name": "name"}}
+
+func importFile(t *testing.T, name string, opts Options) (*Result, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	return Import(f, opts)
+}
+
+// positions returns every row error as "line:column: message".
+func positions(errs []*RowError) []string {
+	var out []string
+	for _, e := range errs {
+		out = append(out, e.Error())
+	}
+	return out
+}
+
+func TestImport(t *testing.T) {
+	res, err := importFile(t, "students.csv", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]int{"Alice": 30, "Bob": 25, "Cara": 41}; !maps.Equal(res.Students, want) {
+		t.Errorf("got %v, want %v", res.Students, want)
+	}
+	if len(res.Errors) > 0 {
+		t.Errorf("errors in a clean file: %q", positions(res.Errors))
+	}
+}
+
+func TestRowErrors(t *testing.T) {
+	res, err := importFile(t, "messy.csv", messyOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`4:8: age: invalid number "abc"`,
+		"5:5: age: must be at least 0, got -4",
+		"6:1: name: missing value",
+		"8:1: row has 1 fields, expected at least 2",
+		`9:5: extraneous or missing " in quoted-field`,
+	}
+	if got := positions(res.Errors); !slices.Equal(got, want) {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	tests := []struct {
+		policy DuplicatePolicy
+		alice  int
+		sum    Summary
+	}{
+		{Overwrite, 31, Summary{Rows: 9, Imported: 3, Overwritten: 1, Invalid: 5, MinAge: 22, MaxAge: 31, MeanAge: 78.0 / 3}},
+		{Skip, 30, Summary{Rows: 9, Imported: 3, Skipped: 1, Invalid: 5, MinAge: 22, MaxAge: 30, MeanAge: 77.0 / 3}},
+	}
+	for _, tt := range tests {
+		opts := messyOptions
+		opts.Duplicates = tt.policy
+		res, err := importFile(t, "messy.csv", opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.policy, err)
+		}
+		if got := res.Students["Alice"]; got != tt.alice {
+			t.Errorf("%s: Alice is %d, want %d", tt.policy, got, tt.alice)
+		}
+		if res.Summary != tt.sum {
+			t.Errorf("%s: summary %+v, want %+v", tt.policy, res.Summary, tt.sum)
+		}
+	}
+
+	opts := messyOptions
+	opts.Duplicates = Fail
+	_, err := importFile(t, "messy.csv", opts)
+	var de *DuplicateError
+	if !errors.As(err, &de) {
+		t.Fatalf("fail: got %v, want a *DuplicateError", err)
+	}
+	if *de != (DuplicateError{Name: "Alice", Line: 7, FirstLine: 2}) {
+		t.Errorf("fail: got %+v", *de)
+	}
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	for _, p := range []DuplicatePolicy{Overwrite, Skip, Fail} {
+		if got, err := ParseDuplicatePolicy(strings.ToUpper(p.String())); err != nil || got != p {
+			t.Errorf("%s: got %v, %v", p, got, err)
+		}
+	}
+	if _, err := ParseDuplicatePolicy("merge"); err == nil {
+		t.Error("merge accepted")
+	}
+}
+
+// utf16Bytes encodes s as UTF-16 with a byte order mark.
+func utf16Bytes(s string, little bool) []byte {
+	var out []byte
+	for _, u := range utf16.Encode([]rune("\uFEFF" + s)) {
+		if little {
+			out = append(out, byte(u), byte(u>>8))
+		} else {
+			out = append(out, byte(u>>8), byte(u))
+		}
+	}
+	return out
+}
+
+func TestEncodings(t *testing.T) {
+	const text = "name,age\nسارا,22\nBob,25\n"
+	want := map[string]int{"سارا": 22, "Bob": 25}
+	inputs := map[string][]byte{
+		"UTF-8":            []byte(text),
+		"UTF-8 with a BOM": append([]byte{0xEF, 0xBB, 0xBF}, text...),
+		"UTF-16 LE":        utf16Bytes(text, true),
+		"UTF-16 BE":        utf16Bytes(text, false),
+	}
+	for name, data := range inputs {
+		res, err := Import(strings.NewReader(string(data)), Options{})
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !maps.Equal(res.Students, want) {
+			t.Errorf("%s: got %v", name, res.Students)
+		}
+	}
+	for _, little := range []bool{true, false} {
+		data := utf16Bytes(text, little)
+		if _, err := Import(strings.NewReader(string(data[:len(data)-1])), Options{}); err == nil {
+			t.Errorf("UTF-16 with an odd byte count (little endian %v) was accepted", little)
+		}
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	res, err := Import(strings.NewReader("Years, FULL NAME\n30,Alice\n"), Options{Headers: map[string]string{"full name": "Name", "years": "age"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !maps.Equal(res.Students, map[string]int{"Alice": 30}) {
+		t.Errorf("aliases: got %v", res.Students)
+	}
+	for input, missing := range map[string]string{
+		"name,email\nAl,a@x.io\n": "no age column",
+		"student,age\nAl,3\n":     "no name column",
+		"id\n1\n":                 "no name or age column",
+	} {
+		if _, err := Import(strings.NewReader(input), Options{}); err == nil || !strings.Contains(err.Error(), missing) {
+			t.Errorf("%q: got %v, want an error containing %q", input, err, missing)
+		}
+	}
+	if _, err := Import(strings.NewReader(""), Options{}); err == nil {
+		t.Error("an empty file was accepted")
+	}
+}
+
+func TestSummaryOfNothing(t *testing.T) {
+	res, err := Import(strings.NewReader("name,age\n,3\n"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Summary{Rows: 1, Invalid: 1}); res.Summary != want {
+		t.Errorf("got %+v, want %+v", res.Summary, want)
+	}
+}
@@ -0,0 +1,71 @@
+package sudoku
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Result is the outcome of solving one puzzle of a batch.
+type Result struct {
+	Solution Grid
+	Stats    Stats
+	Err      error
+}
+
+// SolveAll solves a batch of puzzles with up to workers goroutines and
+// returns the results in the same order. Each worker takes the next
+// unsolved puzzle from a shared counter, so a few slow puzzles do not hold
+// up the others as they would if the batch were split into fixed chunks.
+func SolveAll(puzzles []Grid, workers int) []Result {
+	results := make([]Result, len(puzzles))
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), len(puzzles)) {
+		wg.Go(func() {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(puzzles) {
+					return
+				}
+				r := &results[i]
+				r.Solution, r.Stats, r.Err = Solve(puzzles[i])
+			}
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// Puzzle is a puzzle from a collection.
+type Puzzle struct {
+	Name string
+	Grid Grid
+}
+
+// ReadPuzzles reads a collection of puzzles, one per line in the 81
+// character format, optionally followed by a name. Blank lines and lines
+// starting with # are skipped.
+func ReadPuzzles(r io.Reader) ([]Puzzle, error) {
+	var puzzles []Puzzle
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cells, name, _ := strings.Cut(text, " ")
+		g, err := Parse(cells)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if name = strings.TrimSpace(name); name == "" {
+			name = fmt.Sprintf("line %d", line)
+		}
+		puzzles = append(puzzles, Puzzle{name, g})
+	}
+	return puzzles, sc.Err()
+}
@@ -0,0 +1,124 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Difficulty rates a puzzle by the techniques it needs.
+type Difficulty int
+
+const (
+	// Easy puzzles fall to naked singles alone: there is always a cell
+	// with only one possible digit.
+	Easy Difficulty = iota + 1
+	// Medium puzzles also need hidden singles: a digit with only one
+	// possible cell in a row, column or box.
+	Medium
+	// Hard puzzles need a few guesses once the singles run out.
+	Hard
+	// Expert puzzles need many guesses.
+	Expert
+)
+
+// expertGuesses is the number of guesses at which a puzzle counts as
+// Expert rather than Hard.
+const expertGuesses = 10
+
+var difficultyNames = []string{Easy: "easy", Medium: "medium", Hard: "hard", Expert: "expert"}
+
+func (d Difficulty) String() string {
+	if d >= Easy && d <= Expert {
+		return difficultyNames[d]
+	}
+	return fmt.Sprintf("Difficulty(%d)", d)
+}
+
+// ParseDifficulty returns the difficulty called name.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for d := Easy; d <= Expert; d++ {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("sudoku: unknown difficulty %q", name)
+}
+
+// Rate returns the difficulty of a puzzle, which should be proper. It
+// tries the techniques in order, like a person would: naked singles, then
+// hidden singles too, then guessing.
+func Rate(g Grid) Difficulty {
+	for _, d := range []Difficulty{Easy, Medium} {
+		sv := &solver{hidden: d == Medium}
+		if s, ok := sv.start(g); ok && s.full() {
+			return d
+		}
+	}
+	sv := &solver{hidden: true, limit: 1}
+	sv.run(g)
+	if sv.stats.Guesses >= expertGuesses {
+		return Expert
+	}
+	return Hard
+}
+
+func (s *state) full() bool {
+	for _, c := range s {
+		if c.Len() != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// GenOptions controls Generate.
+type GenOptions struct {
+	// Symmetric removes clues in pairs opposite each other through the
+	// centre, as published puzzles usually do.
+	Symmetric bool
+	// Difficulty, if set, is the rating the puzzle must have.
+	Difficulty Difficulty
+	// Attempts bounds the puzzles tried to reach Difficulty; it defaults
+	// to 100.
+	Attempts int
+}
+
+// Generate makes a puzzle with exactly one solution. It fills a grid at
+// random, by solving the empty grid with the digits tried in random order,
+// then empties its cells in random order, putting back each one whose
+// removal would allow a second solution. The result is minimal: no clue
+// can be removed without losing uniqueness.
+func Generate(r *rand.Rand, opts GenOptions) (Grid, error) {
+	attempts := opts.Attempts
+	if attempts <= 0 {
+		attempts = 100
+	}
+	for range attempts {
+		g := generate(r, opts.Symmetric)
+		if opts.Difficulty == 0 || Rate(g) == opts.Difficulty {
+			return g, nil
+		}
+	}
+	return Grid{}, fmt.Errorf("sudoku: no %s puzzle in %d attempts", opts.Difficulty, attempts)
+}
+
+func generate(r *rand.Rand, symmetric bool) Grid {
+	sv := &solver{hidden: true, limit: 1, rng: r}
+	sv.run(Grid{})
+	g := sv.first.grid()
+	for _, c := range r.Perm(81) {
+		row, col := c/9, c%9
+		if g[row][col] == 0 {
+			continue
+		}
+		saved := g
+		g[row][col] = 0
+		if symmetric {
+			g[8-row][8-col] = 0
+		}
+		if CountSolutions(g, 2) != 1 {
+			g = saved
+		}
+	}
+	return g
+}
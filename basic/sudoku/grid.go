@@ -0,0 +1,164 @@
+// Package sudoku solves, rates and generates 9×9 Sudoku puzzles.
+//
+// A Grid is a [9][9]int8 array, with 0 for an empty cell. Because arrays
+// are values in Go, assigning a Grid or passing it to a function copies
+// all 81 cells; the solvers rely on that, copying their state before each
+// guess so that a wrong guess is undone by simply dropping the copy.
+//
+// Solve combines constraint propagation with search, in the style of
+// Peter Norvig's solver: candidates are kept as bitmask Sets, filling a
+// cell removes its digit from the cell's 20 peers, and a digit with only
+// one place left in a row, column or box goes there. When that stalls it
+// guesses in the cell with the fewest candidates. Backtrack is the plain
+// depth-first search it improves on.
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Grid is a puzzle or a solution: Grid[row][col] is a digit from 1 to 9,
+// or 0 for an empty cell.
+type Grid [9][9]int8
+
+// ErrInvalid is returned for a grid with a digit twice in a row, column or
+// box.
+var ErrInvalid = errors.New("sudoku: grid breaks the rules")
+
+// ErrNoSolution is returned for a valid grid that cannot be completed.
+var ErrNoSolution = errors.New("sudoku: no solution")
+
+// Parse reads the common one-line format: 81 characters, row by row, with
+// the digits 1-9 for clues and '.' or '0' for empty cells. Whitespace
+// between the cells is ignored, so a grid laid out in nine lines parses
+// too.
+func Parse(s string) (Grid, error) {
+	var g Grid
+	n := 0
+	for _, c := range s {
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case n == 81:
+			return g, fmt.Errorf("sudoku: more than 81 cells")
+		case c >= '1' && c <= '9':
+			g[n/9][n%9] = int8(c - '0')
+		case c == '.' || c == '0':
+		default:
+			return g, fmt.Errorf("sudoku: unexpected %q at cell %d", c, n+1)
+		}
+		n++
+	}
+	if n != 81 {
+		return g, fmt.Errorf("sudoku: got %d cells, want 81", n)
+	}
+	return g, nil
+}
+
+// MustParse is like Parse but panics on error.
+func MustParse(s string) Grid {
+	g, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// String returns the grid in the one-line format, with '.' for empty
+// cells.
+func (g Grid) String() string {
+	var b strings.Builder
+	for r := range 9 {
+		for c := range 9 {
+			if d := g[r][c]; d == 0 {
+				b.WriteByte('.')
+			} else {
+				b.WriteByte(byte('0' + d))
+			}
+		}
+	}
+	return b.String()
+}
+
+// Format lays the grid out in nine lines with the boxes marked:
+//
+//	5 3 . | . 7 . | . . .
+//	6 . . | 1 9 5 | . . .
+//	...
+func (g Grid) Format() string {
+	var b strings.Builder
+	for r := range 9 {
+		if r == 3 || r == 6 {
+			b.WriteString("------+-------+------\n")
+		}
+		for c := range 9 {
+			if c == 3 || c == 6 {
+				b.WriteString("| ")
+			}
+			if d := g[r][c]; d == 0 {
+				b.WriteByte('.')
+			} else {
+				b.WriteByte(byte('0' + d))
+			}
+			if c < 8 {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Clues returns the number of filled cells.
+func (g Grid) Clues() int {
+	n := 0
+	for r := range 9 {
+		for c := range 9 {
+			if g[r][c] != 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Valid reports whether every digit is between 0 and 9 and no digit
+// appears twice in a row, column or box.
+func (g Grid) Valid() bool {
+	var rows, cols, boxes [9]Set
+	for r := range 9 {
+		for c := range 9 {
+			d := g[r][c]
+			if d == 0 {
+				continue
+			}
+			b := r/3*3 + c/3
+			if d < 0 || d > 9 || rows[r].Has(d) || cols[c].Has(d) || boxes[b].Has(d) {
+				return false
+			}
+			rows[r] = rows[r].Add(d)
+			cols[c] = cols[c].Add(d)
+			boxes[b] = boxes[b].Add(d)
+		}
+	}
+	return true
+}
+
+// Solved reports whether the grid is full and valid.
+func (g Grid) Solved() bool {
+	return g.Clues() == 81 && g.Valid()
+}
+
+// Extends reports whether g keeps every clue of puzzle.
+func (g Grid) Extends(puzzle Grid) bool {
+	for r := range 9 {
+		for c := range 9 {
+			if puzzle[r][c] != 0 && puzzle[r][c] != g[r][c] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package sudoku
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// Set is a set of digits from 1 to 9, stored as a bitmask: digit d is bit
+// d. Adding, removing and testing a digit are single bit operations, and
+// the size is one popcount instruction, which is why solvers keep their
+// candidates this way rather than in slices or maps.
+type Set uint16
+
+// AllDigits holds every digit from 1 to 9.
+const AllDigits Set = 0b11_1111_1110
+
+// Has reports whether d is in the set.
+func (s Set) Has(d int8) bool { return s&(1<<d) != 0 }
+
+// Add returns the set with d added.
+func (s Set) Add(d int8) Set { return s | 1<<d }
+
+// Remove returns the set without d.
+func (s Set) Remove(d int8) Set { return s &^ (1 << d) }
+
+// Len returns the number of digits in the set.
+func (s Set) Len() int { return bits.OnesCount16(uint16(s)) }
+
+// Min returns the smallest digit in the set, or 0 if it is empty.
+func (s Set) Min() int8 {
+	if s == 0 {
+		return 0
+	}
+	return int8(bits.TrailingZeros16(uint16(s)))
+}
+
+// All yields the digits in increasing order.
+func (s Set) All() iter.Seq[int8] {
+	return func(yield func(int8) bool) {
+		for s != 0 {
+			d := s.Min()
+			if !yield(d) {
+				return
+			}
+			s = s.Remove(d)
+		}
+	}
+}
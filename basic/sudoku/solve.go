@@ -0,0 +1,260 @@
+package sudoku
+
+import "math/rand/v2"
+
+// Cells are numbered 0 to 80, row by row. units[c] lists the row, column
+// and box of cell c, and peers[c] the 20 other cells in them.
+var units, peers = buildUnits()
+
+func buildUnits() (units [81][3][9]int8, peers [81][20]int8) {
+	for c := range 81 {
+		r, col := c/9, c%9
+		br, bc := r/3*3, col/3*3
+		for i := range 9 {
+			units[c][0][i] = int8(r*9 + i)
+			units[c][1][i] = int8(i*9 + col)
+			units[c][2][i] = int8((br+i/3)*9 + bc + i%3)
+		}
+		n := 0
+		for p := range 81 {
+			pr, pc := p/9, p%9
+			if p != c && (pr == r || pc == col || pr/3 == r/3 && pc/3 == col/3) {
+				peers[c][n] = int8(p)
+				n++
+			}
+		}
+	}
+	return units, peers
+}
+
+// Stats describes the work a solver did.
+type Stats struct {
+	// Guesses counts the digits tried in cells that had more than one
+	// candidate. A puzzle solved by logic alone needs none.
+	Guesses int
+	// DeadEnds counts the times the search ran into a contradiction and
+	// had to back up.
+	DeadEnds int
+}
+
+// state is the candidates of every cell. A cell is filled when it has one
+// candidate left. The whole state is an array, so copying it is cheap and
+// gives the search an independent copy to try a guess on.
+type state [81]Set
+
+// solver searches for solutions with constraint propagation.
+type solver struct {
+	// hidden enables the hidden single rule: a digit with only one place
+	// left in a unit goes there. Without it only naked singles, cells
+	// with one candidate, are propagated.
+	hidden bool
+	// rng, if set, shuffles the order digits are tried in, which is how
+	// the generator makes random grids.
+	rng *rand.Rand
+	// limit stops the search after that many solutions.
+	limit     int
+	solutions int
+	first     state
+	stats     Stats
+}
+
+// start turns a grid into a state, propagating each clue. It reports
+// false if the clues contradict each other.
+func (sv *solver) start(g Grid) (state, bool) {
+	var s state
+	for c := range s {
+		s[c] = AllDigits
+	}
+	for c := range 81 {
+		if d := g[c/9][c%9]; d != 0 && !sv.assign(&s, c, d) {
+			return s, false
+		}
+	}
+	return s, true
+}
+
+// assign fills cell c with d by eliminating every other candidate.
+func (sv *solver) assign(s *state, c int, d int8) bool {
+	for other := range s[c].Remove(d).All() {
+		if !sv.eliminate(s, c, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// eliminate removes d from the candidates of cell c and propagates the
+// consequences. It reports false on a contradiction: a cell with no
+// candidates, or a digit with no place in a unit.
+func (sv *solver) eliminate(s *state, c int, d int8) bool {
+	if !s[c].Has(d) {
+		return true
+	}
+	s[c] = s[c].Remove(d)
+	switch s[c].Len() {
+	case 0:
+		return false
+	case 1:
+		// A naked single: c is filled, so its digit leaves its peers.
+		only := s[c].Min()
+		for _, p := range peers[c] {
+			if !sv.eliminate(s, int(p), only) {
+				return false
+			}
+		}
+	}
+	if !sv.hidden {
+		return true
+	}
+	// d has lost a place in each unit of c; if only one is left, d goes
+	// there.
+	for _, u := range units[c] {
+		place, n := -1, 0
+		for _, p := range u {
+			if s[p].Has(d) {
+				place, n = int(p), n+1
+			}
+		}
+		if n == 0 {
+			return false
+		}
+		if n == 1 && s[place].Len() > 1 && !sv.assign(s, place, d) {
+			return false
+		}
+	}
+	return true
+}
+
+// search completes s by guessing in the cell with the fewest candidates,
+// which keeps the search tree narrow.
+func (sv *solver) search(s state) {
+	best, fewest := -1, 10
+	for c := range s {
+		if n := s[c].Len(); n > 1 && n < fewest {
+			best, fewest = c, n
+		}
+	}
+	if best < 0 {
+		if sv.solutions == 0 {
+			sv.first = s
+		}
+		sv.solutions++
+		return
+	}
+	digits := make([]int8, 0, 9)
+	for d := range s[best].All() {
+		digits = append(digits, d)
+	}
+	if sv.rng != nil {
+		sv.rng.Shuffle(len(digits), func(i, j int) { digits[i], digits[j] = digits[j], digits[i] })
+	}
+	for _, d := range digits {
+		sv.stats.Guesses++
+		next := s
+		if !sv.assign(&next, best, d) {
+			sv.stats.DeadEnds++
+			continue
+		}
+		sv.search(next)
+		if sv.solutions >= sv.limit {
+			return
+		}
+	}
+}
+
+// run solves g and reports whether the clues were consistent.
+func (sv *solver) run(g Grid) bool {
+	if !g.Valid() {
+		return false
+	}
+	s, ok := sv.start(g)
+	if !ok {
+		return true
+	}
+	sv.search(s)
+	return true
+}
+
+func (s *state) grid() Grid {
+	var g Grid
+	for c, cand := range s {
+		if cand.Len() == 1 {
+			g[c/9][c%9] = cand.Min()
+		}
+	}
+	return g
+}
+
+// Solve returns a solution of g using constraint propagation and search.
+// It returns ErrInvalid if g breaks the rules and ErrNoSolution if it
+// cannot be completed. A proper puzzle has exactly one solution; to check
+// that, use CountSolutions.
+func Solve(g Grid) (Grid, Stats, error) {
+	sv := &solver{hidden: true, limit: 1}
+	if !sv.run(g) {
+		return Grid{}, sv.stats, ErrInvalid
+	}
+	if sv.solutions == 0 {
+		return Grid{}, sv.stats, ErrNoSolution
+	}
+	return sv.first.grid(), sv.stats, nil
+}
+
+// CountSolutions counts the solutions of g, stopping at limit. A puzzle is
+// proper when CountSolutions(g, 2) is 1.
+func CountSolutions(g Grid, limit int) int {
+	sv := &solver{hidden: true, limit: limit}
+	if !sv.run(g) {
+		return 0
+	}
+	return sv.solutions
+}
+
+// Backtrack solves g by plain depth-first search: it fills the empty
+// cells in order, trying each digit the cell's row, column and box allow,
+// and backs up when a cell has none. It is simple and fine for most
+// newspaper puzzles, but on hard ones it explores far more positions than
+// Solve, which only guesses once logic runs out.
+func Backtrack(g Grid) (Grid, Stats, error) {
+	if !g.Valid() {
+		return Grid{}, Stats{}, ErrInvalid
+	}
+	var rows, cols, boxes [9]Set
+	var empty []int
+	for c := range 81 {
+		r, col := c/9, c%9
+		if d := g[r][col]; d != 0 {
+			rows[r], cols[col], boxes[r/3*3+col/3] = rows[r].Add(d), cols[col].Add(d), boxes[r/3*3+col/3].Add(d)
+		} else {
+			empty = append(empty, c)
+		}
+	}
+	var stats Stats
+	var fill func(i int) bool
+	fill = func(i int) bool {
+		if i == len(empty) {
+			return true
+		}
+		c := empty[i]
+		r, col, b := c/9, c%9, c/27*3+c%9/3
+		free := AllDigits &^ (rows[r] | cols[col] | boxes[b])
+		for d := range free.All() {
+			if free.Len() > 1 {
+				stats.Guesses++
+			}
+			g[r][col] = d
+			rows[r], cols[col], boxes[b] = rows[r].Add(d), cols[col].Add(d), boxes[b].Add(d)
+			if fill(i + 1) {
+				return true
+			}
+			rows[r], cols[col], boxes[b] = rows[r].Remove(d), cols[col].Remove(d), boxes[b].Remove(d)
+		}
+		g[r][col] = 0
+		stats.DeadEnds++
+		return false
+	}
+	if !fill(0) {
+		return Grid{}, stats, ErrNoSolution
+	}
+	return g, stats, nil
+}
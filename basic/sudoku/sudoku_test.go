@@ -0,0 +1,208 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// corpus reads testdata/puzzles.txt: generated puzzles of every
+// difficulty, and well-known hard ones.
+func corpus(tb testing.TB) []Puzzle {
+	tb.Helper()
+	f, err := os.Open(filepath.Join("testdata", "puzzles.txt"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer f.Close()
+	puzzles, err := ReadPuzzles(f)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return puzzles
+}
+
+// grids returns the grids of the corpus, in order.
+func grids(tb testing.TB) []Grid {
+	tb.Helper()
+	puzzles := corpus(tb)
+	out := make([]Grid, len(puzzles))
+	for i, p := range puzzles {
+		out[i] = p.Grid
+	}
+	return out
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	for _, p := range corpus(t) {
+		if got := MustParse(p.Grid.String()); got != p.Grid {
+			t.Errorf("%s: printed as %s, parsed back as %s", p.Name, p.Grid, got)
+		}
+		if got := MustParse(strings.NewReplacer("|", "", "-", "", "+", "").Replace(p.Grid.Format())); got != p.Grid {
+			t.Errorf("%s: the boxed layout parses as %s", p.Name, got)
+		}
+	}
+	if _, err := Parse(strings.Repeat(".", 80)); err == nil {
+		t.Error("80 cells parsed")
+	}
+}
+
+func TestCorpusHasUniqueSolutions(t *testing.T) {
+	for _, p := range corpus(t) {
+		if n := CountSolutions(p.Grid, 2); n != 1 {
+			t.Errorf("%s has %d solutions", p.Name, n)
+		}
+	}
+}
+
+func TestSolversAgree(t *testing.T) {
+	for _, p := range corpus(t) {
+		a, _, err := Solve(p.Grid)
+		if err != nil {
+			t.Errorf("%s: %v", p.Name, err)
+			continue
+		}
+		if !a.Solved() || !a.Extends(p.Grid) {
+			t.Errorf("%s: propagation gives\n%s", p.Name, a.Format())
+		}
+		b, _, err := Backtrack(p.Grid)
+		if err != nil || b != a {
+			t.Errorf("%s: backtracking gives %s, %v", p.Name, b, err)
+		}
+	}
+}
+
+func TestCorpusRatings(t *testing.T) {
+	for _, p := range corpus(t) {
+		level, _, _ := strings.Cut(p.Name, "-")
+		want, err := ParseDifficulty(level)
+		if err != nil {
+			continue
+		}
+		if got := Rate(p.Grid); got != want {
+			t.Errorf("%s rates as %s", p.Name, got)
+		}
+	}
+}
+
+func TestBrokenGrids(t *testing.T) {
+	twice := MustParse("11" + strings.Repeat(".", 79))
+	if _, _, err := Solve(twice); !errors.Is(err, ErrInvalid) {
+		t.Errorf("a repeated digit gives %v", err)
+	}
+	// The top left cell can be neither 1-8, by its row, nor 9, by its
+	// column: the clues agree with the rules but have no solution.
+	stuck := MustParse(".12345678" + strings.Repeat(".", 9) + "9" + strings.Repeat(".", 62))
+	for name, solve := range map[string]func(Grid) (Grid, Stats, error){"propagation": Solve, "backtracking": Backtrack} {
+		if _, _, err := solve(stuck); !errors.Is(err, ErrNoSolution) {
+			t.Errorf("%s gives %v for an unsolvable grid", name, err)
+		}
+	}
+	if n := CountSolutions(Grid{}, 2); n != 2 {
+		t.Errorf("the empty grid has %d solutions, want at least 2", n)
+	}
+}
+
+func TestSolveAll(t *testing.T) {
+	puzzles, gs := corpus(t), grids(t)
+	for _, workers := range []int{1, 3, 64} {
+		for i, r := range SolveAll(gs, workers) {
+			want, _, _ := Solve(gs[i])
+			if r.Err != nil || r.Solution != want {
+				t.Errorf("%d workers: %s solved as %s, %v", workers, puzzles[i].Name, r.Solution, r.Err)
+			}
+		}
+	}
+}
+
+// TestGenerate checks that generated puzzles have one solution, the
+// requested rating and no clue that could be taken away.
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, opts := range []GenOptions{{}, {Symmetric: true}, {Difficulty: Easy}, {Difficulty: Hard}} {
+		g, err := Generate(r, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := CountSolutions(g, 2); n != 1 {
+			t.Errorf("%s has %d solutions", g, n)
+		}
+		if opts.Difficulty != 0 && Rate(g) != opts.Difficulty {
+			t.Errorf("%s rates %s, want %s", g, Rate(g), opts.Difficulty)
+		}
+		for row := range 9 {
+			for col := range 9 {
+				if g[row][col] == 0 {
+					continue
+				}
+				h := g
+				h[row][col] = 0
+				if opts.Symmetric {
+					h[8-row][8-col] = 0
+				}
+				if CountSolutions(h, 2) == 1 {
+					t.Errorf("%s: clue at row %d, column %d is not needed", g, row+1, col+1)
+				}
+				if opts.Symmetric && g[8-row][8-col] == 0 {
+					t.Errorf("%s is not symmetric", g)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkSolve times both solvers on each difficulty of the corpus. One
+// op solves every puzzle of that difficulty once.
+func BenchmarkSolve(b *testing.B) {
+	groups := map[Difficulty][]Grid{}
+	for _, g := range grids(b) {
+		d := Rate(g)
+		groups[d] = append(groups[d], g)
+	}
+	solvers := []struct {
+		name  string
+		solve func(Grid) (Grid, Stats, error)
+	}{
+		{"propagate", Solve},
+		{"backtrack", Backtrack},
+	}
+	for _, s := range solvers {
+		for d := Easy; d <= Expert; d++ {
+			gs := groups[d]
+			if len(gs) == 0 {
+				continue
+			}
+			b.Run(s.name+"/"+d.String(), func(b *testing.B) {
+				for b.Loop() {
+					for _, g := range gs {
+						s.solve(g)
+					}
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(gs)), "ns/puzzle")
+			})
+		}
+	}
+}
+
+// BenchmarkSolveAll times the batch solver on the whole corpus with more
+// and more workers, up to GOMAXPROCS.
+func BenchmarkSolveAll(b *testing.B) {
+	gs := grids(b)
+	procs := runtime.GOMAXPROCS(0)
+	for workers := 1; ; workers *= 2 {
+		workers = min(workers, procs)
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				SolveAll(gs, workers)
+			}
+		})
+		if workers == procs {
+			return
+		}
+	}
+}
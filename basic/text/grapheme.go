@@ -0,0 +1,185 @@
+package text
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// breakClass is the grapheme cluster break property of a rune, from
+// Unicode Standard Annex #29, plus the Extended_Pictographic property the
+// emoji rules need.
+type breakClass uint8
+
+const (
+	classOther breakClass = iota
+	classCR
+	classLF
+	classControl
+	classExtend
+	classZWJ
+	classRegional
+	classPrepend
+	classSpacingMark
+	classL
+	classV
+	classT
+	classLV
+	classLVT
+	classPictographic
+)
+
+// classOf approximates the break property from the unicode package's
+// categories and a few ranges. It covers combining marks, ZWNJ and ZWJ,
+// variation selectors, skin tones, flags, Hangul and the common emoji
+// blocks, which is what mixed Latin, Persian, CJK and emoji text needs.
+func classOf(r rune) breakClass {
+	switch {
+	case r == '\r':
+		return classCR
+	case r == '\n':
+		return classLF
+	case r == 0x200D:
+		return classZWJ
+	case r == 0x200C, // zero width non-joiner, as in Persian می‌خواهم
+		0xFE00 <= r && r <= 0xFE0F,   // variation selectors
+		0x1F3FB <= r && r <= 0x1F3FF, // skin tone modifiers
+		0xE0020 <= r && r <= 0xE007F, // tags
+		unicode.In(r, unicode.Mn, unicode.Me):
+		return classExtend
+	case 0x1F1E6 <= r && r <= 0x1F1FF:
+		return classRegional
+	case 0x0600 <= r && r <= 0x0605, r == 0x06DD, r == 0x070F, r == 0x08E2:
+		return classPrepend
+	case 0x1100 <= r && r <= 0x115F, 0xA960 <= r && r <= 0xA97C:
+		return classL
+	case 0x1160 <= r && r <= 0x11A7, 0xD7B0 <= r && r <= 0xD7C6:
+		return classV
+	case 0x11A8 <= r && r <= 0x11FF, 0xD7CB <= r && r <= 0xD7FB:
+		return classT
+	case 0xAC00 <= r && r <= 0xD7A3:
+		// Precomposed syllables: every 28th one has no final consonant.
+		if (r-0xAC00)%28 == 0 {
+			return classLV
+		}
+		return classLVT
+	case unicode.Is(unicode.Mc, r):
+		return classSpacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return classControl
+	case isPictographic(r):
+		return classPictographic
+	}
+	return classOther
+}
+
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	case 0x2194 <= r && r <= 0x21AA, 0x2300 <= r && r <= 0x23FF, 0x25AA <= r && r <= 0x25FE,
+		0x2600 <= r && r <= 0x27BF, 0x2934 <= r && r <= 0x2935, 0x2B05 <= r && r <= 0x2B55:
+		return true
+	case 0x1F000 <= r && r <= 0x1FAFF, 0x1FC00 <= r && r <= 0x1FFFD:
+		return true
+	}
+	return false
+}
+
+// segmenter holds what the break rules need to remember about the
+// cluster so far.
+type segmenter struct {
+	prev breakClass
+	// regional counts the regional indicators in a row, which pair up into
+	// flags.
+	regional int
+	// pictographic is set after a pictographic rune followed only by
+	// Extend and ZWJ, where a ZWJ joins the next pictograph (👩‍💻).
+	pictographic bool
+}
+
+// breaks reports whether there is a cluster boundary before a rune of
+// class cur, and updates the state.
+func (s *segmenter) breaks(cur breakClass) bool {
+	prev := s.prev
+	var b bool
+	switch {
+	case prev == classCR && cur == classLF: // GB3
+		b = false
+	case prev == classCR, prev == classLF, prev == classControl: // GB4
+		b = true
+	case cur == classCR, cur == classLF, cur == classControl: // GB5
+		b = true
+	case prev == classL && (cur == classL || cur == classV || cur == classLV || cur == classLVT): // GB6
+		b = false
+	case (prev == classLV || prev == classV) && (cur == classV || cur == classT): // GB7
+		b = false
+	case (prev == classLVT || prev == classT) && cur == classT: // GB8
+		b = false
+	case cur == classExtend, cur == classZWJ, cur == classSpacingMark: // GB9, GB9a
+		b = false
+	case prev == classPrepend: // GB9b
+		b = false
+	case prev == classZWJ && cur == classPictographic && s.pictographic: // GB11
+		b = false
+	case prev == classRegional && cur == classRegional: // GB12, GB13
+		b = s.regional%2 == 0
+	default: // GB999
+		b = true
+	}
+
+	switch cur {
+	case classPictographic:
+		s.pictographic = true
+	case classExtend, classZWJ:
+	default:
+		s.pictographic = false
+	}
+	if cur == classRegional {
+		s.regional++
+	} else {
+		s.regional = 0
+	}
+	s.prev = cur
+	return b
+}
+
+// FirstGrapheme returns the first grapheme cluster of s: what a reader
+// sees as one character, such as "e" with a combining accent, a flag made
+// of two regional indicators or a ZWJ emoji sequence.
+func FirstGrapheme(s string) string {
+	if s == "" {
+		return ""
+	}
+	var seg segmenter
+	r, n := utf8.DecodeRuneInString(s)
+	seg.breaks(classOf(r))
+	for i := n; i < len(s); i += n {
+		r, n = utf8.DecodeRuneInString(s[i:])
+		if seg.breaks(classOf(r)) {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// Graphemes splits s into grapheme clusters.
+func Graphemes(s string) []string {
+	var out []string
+	for s != "" {
+		g := FirstGrapheme(s)
+		out = append(out, g)
+		s = s[len(g):]
+	}
+	return out
+}
+
+// GraphemeCount returns the number of grapheme clusters in s.
+func GraphemeCount(s string) int {
+	n := 0
+	for s != "" {
+		s = s[len(FirstGrapheme(s)):]
+		n++
+	}
+	return n
+}
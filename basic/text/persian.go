@@ -0,0 +1,99 @@
+package text
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Digit zeros of the scripts ToASCIIDigits understands. Each script
+// encodes 0 to 9 as ten consecutive runes.
+const (
+	persianZero     = '۰' // U+06F0, extended Arabic-Indic, used in Iran and Afghanistan
+	arabicIndicZero = '٠' // U+0660, used in most Arabic-speaking countries
+	fullwidthZero   = '０' // U+FF10
+)
+
+func digitValue(r rune) (int, bool) {
+	for _, zero := range []rune{'0', persianZero, arabicIndicZero, fullwidthZero} {
+		if zero <= r && r <= zero+9 {
+			return int(r - zero), true
+		}
+	}
+	return 0, false
+}
+
+// ToPersianDigits writes the ASCII and Arabic-Indic digits of s as
+// Persian digits: "Room 101" becomes "Room ۱۰۱".
+func ToPersianDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case '0' <= r && r <= '9':
+			return persianZero + r - '0'
+		case arabicIndicZero <= r && r <= arabicIndicZero+9:
+			return persianZero + r - arabicIndicZero
+		}
+		return r
+	}, s)
+}
+
+// ToASCIIDigits writes the Persian, Arabic-Indic and fullwidth digits of
+// s as ASCII digits, so strconv can parse them.
+func ToASCIIDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if v, ok := digitValue(r); ok {
+			return '0' + rune(v)
+		}
+		return r
+	}, s)
+}
+
+// ParseInt parses a decimal integer written in any digits ToASCIIDigits
+// understands, such as "۱۴۰۳".
+func ParseInt(s string) (int, error) {
+	return strconv.Atoi(ToASCIIDigits(strings.TrimSpace(s)))
+}
+
+// persianLetters maps Arabic letters to the Persian letters that look the
+// same in most positions but are different runes. Text typed on an Arabic
+// keyboard uses the first ones, so "علي" and "علی" would not match.
+var persianLetters = strings.NewReplacer(
+	"ي", "ی", // ي Arabic yeh -> ی Farsi yeh
+	"ى", "ی", // ى alef maksura -> ی Farsi yeh
+	"ك", "ک", // ك Arabic kaf -> ک keheh
+	"ـ", "", // ـ tatweel, a stretching stroke with no meaning
+)
+
+// NormalizePersian prepares Persian text for comparing and searching: it
+// composes it to NFC, replaces Arabic yeh and kaf with their Persian
+// forms, removes tatweel and writes all digits as Persian digits. The
+// zero width non-joiner between parts of a word is kept: it changes how
+// the word is drawn.
+func NormalizePersian(s string) string {
+	return ToPersianDigits(persianLetters.Replace(NFC(s)))
+}
+
+// StripHarakat removes the Arabic short vowel and other diacritic marks,
+// which Persian text rarely writes: "کِتاب" becomes "کتاب". Letters with a
+// mark of their own, such as آ, are kept whole.
+func StripHarakat(s string) string {
+	return NFC(strings.Map(func(r rune) rune {
+		switch {
+		case 0x0653 <= r && r <= 0x0655:
+			return r // madda and hamza, which NFD splits off آ, أ and إ
+		case 0x064B <= r && r <= 0x065F, r == 0x0670:
+			return -1
+		}
+		return r
+	}, NFD(s)))
+}
+
+// persianAlphabet is the 32 letters of the Persian alphabet, the four
+// Persian adds to Arabic (پ چ ژ گ) among them, and the forms of alef and
+// hamza.
+const persianAlphabet = "ابپتثجچحخدذرزژسشصضطظعغفقکگلمنوهی" + "آأإءؤئ"
+
+// IsPersianLetter reports whether r is a letter of the Persian alphabet.
+// Arabic yeh and kaf are not: NormalizePersian replaces them.
+func IsPersianLetter(r rune) bool {
+	return strings.ContainsRune(persianAlphabet, r)
+}
@@ -0,0 +1,187 @@
+// Package text works on strings the way a reader sees them rather than
+// the way Go stores them.
+//
+// A Go string is a sequence of bytes, usually UTF-8. len counts bytes,
+// indexing returns a byte, and range decodes one rune (code point) at a
+// time. Neither bytes nor runes are what a reader calls a character: "é"
+// may be one rune or an "e" followed by a combining accent, and a flag or
+// a family emoji is several runes. Those user-perceived characters are
+// grapheme clusters, and Reverse, Truncate and Width work on them so that
+// they never split one.
+//
+// Normalisation comes from golang.org/x/text/unicode/norm, display width
+// from golang.org/x/text/width and text direction from
+// golang.org/x/text/unicode/bidi. persian.go handles Persian and Arabic
+// letters and digits.
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/bidi"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Rune is one rune of a string as range sees it.
+type Rune struct {
+	// Offset is the byte index range reports for the rune.
+	Offset int
+	Rune   rune
+	// Bytes is the rune's UTF-8 encoding, between 1 and 4 bytes.
+	Bytes []byte
+}
+
+// Runes lists the runes of s with their byte offsets. An invalid byte
+// comes back as utf8.RuneError with Bytes holding that one byte.
+func Runes(s string) []Rune {
+	var out []Rune
+	for i, r := range s {
+		n := utf8.RuneLen(r)
+		if r == utf8.RuneError {
+			_, n = utf8.DecodeRuneInString(s[i:])
+		}
+		out = append(out, Rune{Offset: i, Rune: r, Bytes: []byte(s[i : i+n])})
+	}
+	return out
+}
+
+// ReverseRunes reverses s rune by rune. It is the usual first attempt and
+// is wrong for text with combining marks or emoji sequences: the accent
+// of "é" ends up on the letter before it. Reverse does it properly.
+func ReverseRunes(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// Reverse reverses the grapheme clusters of s, keeping each one intact.
+func Reverse(s string) string {
+	g := Graphemes(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(g) - 1; i >= 0; i-- {
+		b.WriteString(g[i])
+	}
+	return b.String()
+}
+
+// Truncate shortens s to at most n grapheme clusters. If s is longer,
+// the result ends with tail, which counts towards n.
+func Truncate(s string, n int, tail string) string {
+	if GraphemeCount(s) <= n {
+		return s
+	}
+	keep := max(n-GraphemeCount(tail), 0)
+	end := 0
+	for range keep {
+		end += len(FirstGrapheme(s[end:]))
+	}
+	return s[:end] + tail
+}
+
+// TruncateWidth shortens s to fit in w terminal columns. If s is wider,
+// the result ends with tail, which counts towards w.
+func TruncateWidth(s string, w int, tail string) string {
+	if Width(s) <= w {
+		return s
+	}
+	room := w - Width(tail)
+	end := 0
+	for end < len(s) {
+		g := FirstGrapheme(s[end:])
+		if gw := GraphemeWidth(g); gw > room {
+			break
+		} else {
+			room -= gw
+		}
+		end += len(g)
+	}
+	return s[:end] + tail
+}
+
+// Width returns the number of terminal columns s takes up, assuming a
+// terminal that draws East Asian wide characters and emoji two columns
+// wide and combining marks on the character before them.
+func Width(s string) int {
+	w := 0
+	for s != "" {
+		g := FirstGrapheme(s)
+		w += GraphemeWidth(g)
+		s = s[len(g):]
+	}
+	return w
+}
+
+// GraphemeWidth returns the width of one grapheme cluster: that of its
+// first rune, except that emoji presentation (a variation selector 16 or
+// a flag) makes it two columns.
+func GraphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case g == "":
+		return 0
+	case strings.ContainsRune(g, 0xFE0F), classOf(r) == classRegional && utf8.RuneCountInString(g) > 1:
+		return 2
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Mn, unicode.Me):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// NFC returns s in normalisation form C, where "e" and a combining acute
+// accent become the single rune "é". Most text arrives this way.
+func NFC(s string) string { return norm.NFC.String(s) }
+
+// NFD returns s in normalisation form D, where every composed character
+// is split into a base and combining marks.
+func NFD(s string) string { return norm.NFD.String(s) }
+
+// Equal reports whether a and b are the same text, whether or not they
+// use the same composed or decomposed form. == compares bytes and would
+// say "é" and "é" differ.
+func Equal(a, b string) bool { return norm.NFC.String(a) == norm.NFC.String(b) }
+
+// Direction is the overall direction of a piece of text.
+type Direction int
+
+const (
+	Neutral Direction = iota
+	LeftToRight
+	RightToLeft
+)
+
+func (d Direction) String() string {
+	switch d {
+	case LeftToRight:
+		return "left-to-right"
+	case RightToLeft:
+		return "right-to-left"
+	}
+	return "neutral"
+}
+
+// Dir returns the direction of the first strongly directional rune in s,
+// which is how the Unicode bidirectional algorithm picks the direction of
+// a paragraph. "سلام world" is right-to-left; digits and punctuation are
+// neutral.
+func Dir(s string) Direction {
+	for _, r := range s {
+		p, _ := bidi.LookupRune(r)
+		switch p.Class() {
+		case bidi.L:
+			return LeftToRight
+		case bidi.R, bidi.AL:
+			return RightToLeft
+		}
+	}
+	return Neutral
+}
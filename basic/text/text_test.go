@@ -0,0 +1,84 @@
+package text
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestMixedScript checks the package on Latin, Persian, CJK, Hangul and
+// emoji input, where bytes, runes and characters all differ.
+func TestMixedScript(t *testing.T) {
+	count := func(s string) string { return strconv.Itoa(GraphemeCount(s)) }
+	width := func(s string) string { return strconv.Itoa(Width(s)) }
+	tests := []struct {
+		name      string
+		got, want string
+	}{
+		{"reverse Latin and Persian", Reverse("Hello, سلام"), "مالس ,olleH"},
+		{"reverse keeps a combining accent on its letter", Reverse("cafe\u0301!"), "!e\u0301fac"},
+		{"reverse keeps flags whole", Reverse("🇮🇷🇩🇪"), "🇩🇪🇮🇷"},
+		{"reverse keeps a ZWJ sequence whole", Reverse("a👩\u200d💻b"), "b👩\u200d💻a"},
+		{"reverse keeps a skin tone on its emoji", Reverse("👍🏽ok"), "ko👍🏽"},
+		{"reversing runes moves the accent", ReverseRunes("e\u0301a"), "a\u0301e"},
+		{"ZWNJ stays inside a Persian word", count("می\u200cخواهم"), "7"},
+		{"decomposed Hangul syllables", count(NFD("한국어")), "3"},
+		{"CR LF is one character", count("a\r\nb"), "3"},
+		{"width of Persian", width("سلام"), "4"},
+		{"width of CJK", width("日本語"), "6"},
+		{"width of emoji with skin tone", width("👍🏽"), "2"},
+		{"width of a decomposed accent", width("e\u0301"), "1"},
+		{"width of a flag", width("🇮🇷"), "2"},
+		{"truncate Persian", Truncate("سلام دنیا", 4, "…"), "سلا…"},
+		{"truncate never splits an accent", Truncate("cafe\u0301s", 4, ""), "cafe\u0301"},
+		{"truncate to a width", TruncateWidth("日本語テキスト", 7, "…"), "日本語…"},
+		{"truncate to a width leaves short text alone", TruncateWidth("abc", 3, "…"), "abc"},
+		{"NFC composes é", NFC("e\u0301"), "é"},
+		{"NFC composes alef with madda", NFC("ا\u0653"), "آ"},
+		{"NFD splits é", NFD("é"), "e\u0301"},
+		{"Equal ignores the normal form", strconv.FormatBool(Equal("café", "cafe\u0301")), "true"},
+		{"Arabic yeh and kaf become Persian", NormalizePersian("علي كريمي"), "علی کریمی"},
+		{"tatweel is removed", NormalizePersian("سـلام"), "سلام"},
+		{"harakat are stripped, madda kept", StripHarakat("ک\u0650تاب\u0650 آبی"), "کتاب آبی"},
+		{"Arabic-Indic digits become Persian", NormalizePersian("٢٠٢٥"), "۲۰۲۵"},
+		{"Latin digits become Persian", ToPersianDigits("Room 101"), "Room ۱۰۱"},
+		{"mixed digits become ASCII", ToASCIIDigits("۱۴۰۳/٠٥/12 ３"), "1403/05/12 3"},
+		{"direction of Persian first", Dir("سلام world").String(), "right-to-left"},
+		{"direction of Latin first", Dir("123 hello سلام").String(), "left-to-right"},
+		{"direction of digits only", Dir("۱۲۳ 456").String(), "neutral"},
+		{"gaf is a Persian letter", strconv.FormatBool(IsPersianLetter('گ')), "true"},
+		{"Arabic yeh is not", strconv.FormatBool(IsPersianLetter('ي')), "false"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s:\ngot  %+q\nwant %+q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"۱۴۰۳", 1403, true},
+		{" ٢٥ ", 25, true},
+		{"-۷", -7, true},
+		{"12", 12, true},
+		{"۱۲a", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		n, err := ParseInt(tt.in)
+		if (err == nil) != tt.ok || n != tt.want {
+			t.Errorf("ParseInt(%q) = %d, %v; want %d, ok %v", tt.in, n, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRunes(t *testing.T) {
+	rs := Runes("aé")
+	if len(rs) != 2 || rs[1].Offset != 1 || rs[1].Rune != 'é' || len(rs[1].Bytes) != 2 {
+		t.Errorf("got %+v", rs)
+	}
+}
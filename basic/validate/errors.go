@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is one failed rule.
+type FieldError struct {
+	// Namespace is the path from the validated struct, e.g.
+	// "Class.Students[1].Age".
+	Namespace string
+	// Field is the last element of Namespace, e.g. "Age".
+	Field string
+	// Tag is the rule that failed and Param its parameter, e.g. "max" and
+	// "150".
+	Tag   string
+	Param string
+	// Value is the offending value.
+	Value any
+}
+
+// Error returns the message from the English catalog.
+func (e *FieldError) Error() string {
+	return e.Translate(English)
+}
+
+// Translate renders the error with the message template for its tag from
+// c, falling back to English and then to the generic message of c, or of
+// English if c has none.
+func (e *FieldError) Translate(c Catalog) string {
+	tmpl, ok := c[e.Tag]
+	if !ok {
+		tmpl, ok = English[e.Tag]
+	}
+	if !ok {
+		tmpl, ok = c[""]
+	}
+	if !ok {
+		tmpl = English[""]
+	}
+	return strings.NewReplacer(
+		"{field}", e.Namespace,
+		"{tag}", e.Tag,
+		"{param}", e.Param,
+		"{value}", fmt.Sprint(e.Value),
+	).Replace(tmpl)
+}
+
+// ValidationErrors lists every failed rule of one Struct call.
+type ValidationErrors []*FieldError
+
+func (es ValidationErrors) Error() string {
+	return strings.Join(es.Translate(English), "; ")
+}
+
+// Translate renders every error with c.
+func (es ValidationErrors) Translate(c Catalog) []string {
+	out := make([]string, len(es))
+	for i, e := range es {
+		out[i] = e.Translate(c)
+	}
+	return out
+}
+
+// Catalog maps rule names to message templates. Templates may use the
+// placeholders {field}, {tag}, {param} and {value}; the empty key holds the
+// fallback for rules without their own message. Provide a Catalog for
+// another language and pass it to Translate:
+//
+//	persian := validate.Catalog{
+//		"required": "{field} الزامی است",
+//		"max":      "{field} باید حداکثر {param} باشد",
+//	}
+//	for _, msg := range errs.Translate(persian) { ... }
+type Catalog map[string]string
+
+// English is the default catalog.
+var English = Catalog{
+	"":           "{field} failed the {tag} rule",
+	"required":   "{field} is required",
+	"len":        "{field} must have length {param}",
+	"min":        "{field} must be at least {param}",
+	"max":        "{field} must be at most {param}",
+	"eq":         "{field} must be equal to {param}",
+	"ne":         "{field} must not be equal to {param}",
+	"gt":         "{field} must be greater than {param}",
+	"gte":        "{field} must be at least {param}",
+	"lt":         "{field} must be less than {param}",
+	"lte":        "{field} must be at most {param}",
+	"oneof":      "{field} must be one of [{param}]",
+	"email":      "{field} must be a valid email address",
+	"url":        "{field} must be a valid URL",
+	"alpha":      "{field} may only contain letters",
+	"numeric":    "{field} must be numeric",
+	"alphanum":   "{field} may only contain letters and digits",
+	"contains":   "{field} must contain {param}",
+	"startswith": "{field} must start with {param}",
+	"endswith":   "{field} must end with {param}",
+	"eqfield":    "{field} must be equal to {param}",
+	"nefield":    "{field} must not be equal to {param}",
+	"gtfield":    "{field} must be greater than {param}",
+	"gtefield":   "{field} must be greater than or equal to {param}",
+	"ltfield":    "{field} must be less than {param}",
+	"ltefield":   "{field} must be less than or equal to {param}",
+}
@@ -0,0 +1,202 @@
+package validate
+
+import (
+	"cmp"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// builtinRules are available on every Validator:
+//
+//	required            not the zero value (nil, "", 0, false, empty slice or map)
+//	len=n               length (runes for strings) or value equals n
+//	min=n, max=n        length or value is at least / at most n
+//	eq, ne, gt, gte, lt, lte=n   numeric comparisons; lengths for strings, slices and maps
+//	oneof=a b c         value is one of the space-separated words
+//	email, url          well-formed e-mail address / absolute URL
+//	alpha, numeric, alphanum     only letters / digits / both
+//	contains, startswith, endswith=s   substring checks
+//	eqfield, nefield, gtfield, gtefield, ltfield, ltefield=Field
+//	                    compare with a sibling field of the same struct
+var builtinRules = map[string]Func{
+	"required": func(f Field) bool { return f.Value.IsValid() && !f.Value.IsZero() },
+	"len":      sizeRule(func(n, p float64) bool { return n == p }),
+	"min":      sizeRule(func(n, p float64) bool { return n >= p }),
+	"max":      sizeRule(func(n, p float64) bool { return n <= p }),
+	"eq":       sizeRule(func(n, p float64) bool { return n == p }),
+	"ne":       sizeRule(func(n, p float64) bool { return n != p }),
+	"gt":       sizeRule(func(n, p float64) bool { return n > p }),
+	"gte":      sizeRule(func(n, p float64) bool { return n >= p }),
+	"lt":       sizeRule(func(n, p float64) bool { return n < p }),
+	"lte":      sizeRule(func(n, p float64) bool { return n <= p }),
+	"oneof":    oneof,
+	"email":    stringRule(isEmail),
+	"url":      stringRule(isURL),
+	"alpha": stringRule(func(s string) bool {
+		return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }) < 0
+	}),
+	"numeric": stringRule(func(s string) bool { _, err := strconv.ParseFloat(s, 64); return err == nil }),
+	"alphanum": stringRule(func(s string) bool {
+		return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) < 0
+	}),
+	"contains": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && strings.Contains(f.Value.String(), f.Param)
+	},
+	"startswith": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && strings.HasPrefix(f.Value.String(), f.Param)
+	},
+	"endswith": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && strings.HasSuffix(f.Value.String(), f.Param)
+	},
+	"eqfield":  func(f Field) bool { return equal(f.Value, sibling(f)) },
+	"nefield":  func(f Field) bool { return !equal(f.Value, sibling(f)) },
+	"gtfield":  orderRule(func(c int) bool { return c > 0 }),
+	"gtefield": orderRule(func(c int) bool { return c >= 0 }),
+	"ltfield":  orderRule(func(c int) bool { return c < 0 }),
+	"ltefield": orderRule(func(c int) bool { return c <= 0 }),
+}
+
+// numericParams and fieldParams are checked when a plan is compiled, so a
+// typo such as max=abc is reported once instead of failing every value.
+var (
+	numericParams = []string{"len", "min", "max", "eq", "ne", "gt", "gte", "lt", "lte"}
+	fieldParams   = []string{"eqfield", "nefield", "gtfield", "gtefield", "ltfield", "ltefield"}
+)
+
+func checkParam(owner reflect.Type, rule, param string) error {
+	switch {
+	case slices.Contains(numericParams, rule):
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("%s needs a numeric parameter, got %q", rule, param)
+		}
+	case slices.Contains(fieldParams, rule):
+		if _, ok := owner.FieldByName(param); !ok {
+			return fmt.Errorf("%s refers to unknown field %q", rule, param)
+		}
+	case rule == "oneof" && strings.TrimSpace(param) == "":
+		return fmt.Errorf("oneof needs at least one value")
+	}
+	return nil
+}
+
+// size returns the number a size rule compares: the value of a number, the
+// rune count of a string and the length of a slice, array or map.
+func size(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return float64(v.Len()), true
+	}
+	return 0, false
+}
+
+func sizeRule(ok func(n, param float64) bool) Func {
+	return func(f Field) bool {
+		n, valid := size(f.Value)
+		if !valid {
+			return false
+		}
+		p, _ := strconv.ParseFloat(f.Param, 64)
+		return ok(n, p)
+	}
+}
+
+func stringRule(ok func(string) bool) Func {
+	return func(f Field) bool {
+		return f.Value.Kind() == reflect.String && ok(f.Value.String())
+	}
+}
+
+func oneof(f Field) bool {
+	var s string
+	switch f.Value.Kind() {
+	case reflect.String:
+		s = f.Value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = fmt.Sprint(f.Value.Interface())
+	default:
+		return false
+	}
+	return slices.Contains(strings.Fields(f.Param), s)
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Name == "" && addr.Address == s
+}
+
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
+
+// sibling returns the field of the parent struct named by the parameter.
+func sibling(f Field) reflect.Value {
+	other := f.Parent.FieldByName(f.Param)
+	for other.Kind() == reflect.Pointer && !other.IsNil() {
+		other = other.Elem()
+	}
+	return other
+}
+
+func orderRule(ok func(cmp int) bool) Func {
+	return func(f Field) bool {
+		c, ordered := order(f.Value, sibling(f))
+		return ordered && ok(c)
+	}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// order compares numbers by value, strings lexically and time.Time values
+// chronologically. ok is false for values that have no order.
+func order(a, b reflect.Value) (c int, ok bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+	switch {
+	case a.Type() == timeType && b.Type() == timeType:
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case isNumber(a) && isNumber(b):
+		na, _ := size(a)
+		nb, _ := size(b)
+		return cmp.Compare(na, nb), true
+	}
+	return 0, false
+}
+
+func equal(a, b reflect.Value) bool {
+	if c, ok := order(a, b); ok {
+		return c == 0
+	}
+	return a.IsValid() && b.IsValid() && a.CanInterface() && b.CanInterface() &&
+		reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
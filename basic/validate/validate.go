@@ -0,0 +1,361 @@
+// Package validate checks struct values against rules written in struct
+// tags:
+//
+//	type Student struct {
+//		Name  string   `validate:"required"`
+//		Age   int      `validate:"min=0,max=150"`
+//		Email string   `validate:"omitempty,email"`
+//		Level string   `validate:"oneof=beginner intermediate advanced"`
+//		Tags  []string `validate:"max=5,dive,required"`
+//	}
+//
+//	err := validate.Struct(Student{Name: "Alice", Age: 30})
+//
+// Rules are separated by commas. "dive" applies the rules after it to every
+// element of a slice, array or map; "keys" ... "endkeys" right after dive
+// validate map keys. Nested structs and pointers to structs are validated
+// recursively; a struct reached twice through the same pointer, as in a
+// cyclic list, is validated only the first time. See rules.go for the built-in rules and Register for adding
+// your own.
+//
+// The rules for each struct type are parsed once and cached, so repeated
+// validation only pays for the checks themselves.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Func is a custom rule. It reports whether the field is valid.
+type Func func(f Field) bool
+
+// Field is what a rule sees of the value being checked.
+type Field struct {
+	// Value is the field itself, with pointers already dereferenced.
+	Value reflect.Value
+	// Param is the text after "=" in the tag, e.g. "150" for max=150.
+	Param string
+	// Parent is the struct that contains the field, for cross-field rules.
+	Parent reflect.Value
+}
+
+// Validator holds the rule set and the per-type plan cache. The zero value
+// is not usable; call New. A Validator is safe for concurrent use.
+type Validator struct {
+	tagName string
+
+	mu    sync.RWMutex
+	funcs map[string]Func
+
+	plans sync.Map // reflect.Type -> *structPlan
+}
+
+// New returns a Validator with the built-in rules that reads the
+// "validate" struct tag.
+func New() *Validator {
+	v := &Validator{tagName: "validate", funcs: map[string]Func{}}
+	for name, fn := range builtinRules {
+		v.funcs[name] = fn
+	}
+	return v
+}
+
+var std = New()
+
+// Struct validates s with the default Validator.
+func Struct(s any) error {
+	return std.Struct(s)
+}
+
+// Register adds or replaces a rule on the default Validator.
+func Register(name string, fn Func) error {
+	return std.Register(name, fn)
+}
+
+// Register adds or replaces a rule. Registering drops the plan cache, so it
+// is best done once at start-up.
+func (v *Validator) Register(name string, fn Func) error {
+	if name == "" || strings.ContainsAny(name, ",=") || reserved[name] {
+		return fmt.Errorf("validate: invalid rule name %q", name)
+	}
+	v.mu.Lock()
+	v.funcs[name] = fn
+	v.mu.Unlock()
+	v.plans.Clear()
+	return nil
+}
+
+// reserved are tag words with built-in meaning that cannot be overridden.
+var reserved = map[string]bool{"omitempty": true, "dive": true, "keys": true, "endkeys": true, "-": true}
+
+// Struct validates s, which must be a struct or a pointer to one. It
+// returns nil, a ValidationErrors listing every failed rule, or a *TagError
+// if a struct tag is malformed.
+func (v *Validator) Struct(s any) error {
+	rv := reflect.ValueOf(s)
+	ptr := reflect.Value{}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("validate: nil %s", rv.Type())
+		}
+		ptr, rv = rv, rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected a struct, got %s", rv.Type())
+	}
+	r := &run{v: v}
+	r.enter(ptr)
+	r.structValue(rv, rv.Type().Name())
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.errs) > 0 {
+		return r.errs
+	}
+	return nil
+}
+
+// TagError reports a malformed validate tag.
+type TagError struct {
+	Type  reflect.Type
+	Field string
+	Tag   string
+	Msg   string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("validate: %s.%s: bad tag %q: %s", e.Type, e.Field, e.Tag, e.Msg)
+}
+
+// structPlan is the compiled form of one struct type.
+type structPlan struct {
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	index int
+	name  string
+	chain *chain
+}
+
+// chain is the list of rules for one value. dive holds the rules for the
+// elements and keys the rules for map keys.
+type chain struct {
+	omitempty bool
+	rules     []rule
+	keys      *chain
+	dive      *chain
+}
+
+type rule struct {
+	tag   string
+	param string
+	fn    Func
+}
+
+func (v *Validator) plan(t reflect.Type) (*structPlan, error) {
+	if p, ok := v.plans.Load(t); ok {
+		return p.(*structPlan), nil
+	}
+	// Only t's own fields are compiled here; nested struct types get their
+	// plans when validation reaches them, so recursive types need no special
+	// handling.
+	p := &structPlan{}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get(v.tagName)
+		if tag == "-" {
+			continue
+		}
+		c, err := v.compileChain(t, sf, strings.Split(tag, ","), tag)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" && !hasStruct(sf.Type) {
+			continue
+		}
+		p.fields = append(p.fields, fieldPlan{index: i, name: sf.Name, chain: c})
+	}
+	actual, _ := v.plans.LoadOrStore(t, p)
+	return actual.(*structPlan), nil
+}
+
+// hasStruct reports whether values of t may contain a struct to descend
+// into.
+func hasStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+func (v *Validator) compileChain(owner reflect.Type, sf reflect.StructField, words []string, tag string) (*chain, error) {
+	c := &chain{}
+	bad := func(msg string) error {
+		return &TagError{Type: owner, Field: sf.Name, Tag: tag, Msg: msg}
+	}
+	for i := 0; i < len(words); i++ {
+		word := strings.TrimSpace(words[i])
+		name, param, _ := strings.Cut(word, "=")
+		switch name {
+		case "":
+			continue
+		case "omitempty":
+			c.omitempty = true
+		case "dive":
+			rest := words[i+1:]
+			if len(rest) > 0 && strings.TrimSpace(rest[0]) == "keys" {
+				end := -1
+				for j, w := range rest {
+					if strings.TrimSpace(w) == "endkeys" {
+						end = j
+						break
+					}
+				}
+				if end < 0 {
+					return nil, bad("keys without endkeys")
+				}
+				keys, err := v.compileChain(owner, sf, rest[1:end], tag)
+				if err != nil {
+					return nil, err
+				}
+				c.keys = keys
+				rest = rest[end+1:]
+			}
+			dive, err := v.compileChain(owner, sf, rest, tag)
+			if err != nil {
+				return nil, err
+			}
+			c.dive = dive
+			return c, nil
+		case "keys", "endkeys":
+			return nil, bad(name + " must directly follow dive")
+		default:
+			fn, ok := v.funcs[name]
+			if !ok {
+				return nil, bad(fmt.Sprintf("unknown rule %q", name))
+			}
+			if err := checkParam(owner, name, param); err != nil {
+				return nil, bad(err.Error())
+			}
+			c.rules = append(c.rules, rule{tag: name, param: param, fn: fn})
+		}
+	}
+	return c, nil
+}
+
+// run is the state of one Struct call.
+type run struct {
+	v    *Validator
+	errs ValidationErrors
+	err  error // a *TagError found in a nested type
+	// seen holds the pointers to structs already validated, so that a
+	// cyclic value, such as a list node pointing to itself, is walked
+	// only once.
+	seen map[visit]bool
+}
+
+// visit is a pointer to a struct. The type is part of the key because a
+// struct and its first field share an address.
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// enter records that the struct ptr points to is being validated, and
+// reports whether this is the first time. A value that was not reached
+// through a pointer cannot be part of a cycle, so it is always new.
+func (r *run) enter(ptr reflect.Value) bool {
+	if !ptr.IsValid() || ptr.Kind() != reflect.Pointer {
+		return true
+	}
+	k := visit{ptr.Type(), ptr.Pointer()}
+	if r.seen[k] {
+		return false
+	}
+	if r.seen == nil {
+		r.seen = map[visit]bool{}
+	}
+	r.seen[k] = true
+	return true
+}
+
+func (r *run) structValue(rv reflect.Value, ns string) {
+	p, err := r.v.plan(rv.Type())
+	if err != nil {
+		r.err = err
+		return
+	}
+	for _, f := range p.fields {
+		r.value(f.chain, rv.Field(f.index), rv, ns+"."+f.name, f.name)
+	}
+}
+
+// value checks one value against c. ns is the full path of the value, such
+// as "Class.Students[0].Age", and name the last part of it.
+func (r *run) value(c *chain, fv, parent reflect.Value, ns, name string) {
+	if c.omitempty && fv.IsZero() {
+		return
+	}
+	v, ptr := fv, reflect.Value{}
+	for (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if v.Kind() == reflect.Pointer {
+			ptr = v
+		}
+		v = v.Elem()
+	}
+	for _, rl := range c.rules {
+		if rl.fn(Field{Value: v, Param: rl.param, Parent: parent}) {
+			continue
+		}
+		r.errs = append(r.errs, &FieldError{
+			Namespace: ns,
+			Field:     name,
+			Tag:       rl.tag,
+			Param:     rl.param,
+			Value:     interfaceOf(v),
+		})
+		if rl.tag == "required" {
+			// Further rules would only repeat that the value is missing.
+			return
+		}
+	}
+
+	if c.dive != nil {
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				suffix := fmt.Sprintf("[%d]", i)
+				r.value(c.dive, v.Index(i), parent, ns+suffix, name+suffix)
+			}
+		case reflect.Map:
+			iter := v.MapRange()
+			for iter.Next() {
+				suffix := fmt.Sprintf("[%v]", iter.Key())
+				if c.keys != nil {
+					r.value(c.keys, iter.Key(), parent, ns+suffix, name+suffix)
+				}
+				r.value(c.dive, iter.Value(), parent, ns+suffix, name+suffix)
+			}
+		}
+		return
+	}
+	if v.Kind() == reflect.Struct && r.enter(ptr) {
+		r.structValue(v, ns)
+	}
+}
+
+func interfaceOf(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
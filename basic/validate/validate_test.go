@@ -0,0 +1,258 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failed returns the namespace and tag of every error in err, as
+// "Namespace tag".
+func failed(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v, want ValidationErrors", err)
+	}
+	var out []string
+	for _, e := range errs {
+		out = append(out, e.Namespace+" "+e.Tag)
+	}
+	return out
+}
+
+type student struct {
+	Name  string   `validate:"required,alpha"`
+	Age   int      `validate:"min=0,max=150"`
+	Email string   `validate:"omitempty,email"`
+	Level string   `validate:"oneof=beginner intermediate advanced"`
+	Tags  []string `validate:"max=3,dive,required"`
+}
+
+func TestRules(t *testing.T) {
+	ok := student{Name: "Alice", Age: 30, Level: "beginner"}
+	tests := []struct {
+		name string
+		edit func(*student)
+		want []string
+	}{
+		{"valid", func(*student) {}, nil},
+		{"required stops the chain", func(s *student) { s.Name = "" }, []string{"student.Name required"}},
+		{"alpha", func(s *student) { s.Name = "R2D2" }, []string{"student.Name alpha"}},
+		{"alpha accepts Persian", func(s *student) { s.Name = "سارا" }, nil},
+		{"min", func(s *student) { s.Age = -1 }, []string{"student.Age min"}},
+		{"max", func(s *student) { s.Age = 151 }, []string{"student.Age max"}},
+		{"omitempty skips", func(s *student) { s.Email = "" }, nil},
+		{"email", func(s *student) { s.Email = "alice" }, []string{"student.Email email"}},
+		{"oneof", func(s *student) { s.Level = "expert" }, []string{"student.Level oneof"}},
+		{"max counts elements", func(s *student) { s.Tags = []string{"a", "b", "c", "d"} }, []string{"student.Tags max"}},
+		{"dive", func(s *student) { s.Tags = []string{"go", "", ""} }, []string{"student.Tags[1] required", "student.Tags[2] required"}},
+		{"every failure", func(s *student) { s.Name, s.Age = "", 200 }, []string{"student.Name required", "student.Age max"}},
+	}
+	for _, tt := range tests {
+		s := ok
+		tt.edit(&s)
+		if got := failed(t, Struct(s)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiveKeys(t *testing.T) {
+	type grades struct {
+		Scores map[string]int `validate:"min=1,dive,keys,alpha,endkeys,min=0,max=100"`
+	}
+	if err := Struct(grades{Scores: map[string]int{"math": 90, "art": 100}}); err != nil {
+		t.Errorf("valid map: %v", err)
+	}
+	got := failed(t, Struct(grades{Scores: map[string]int{"math2": 90, "art": 101}}))
+	slices.Sort(got)
+	want := []string{"grades.Scores[art] max", "grades.Scores[math2] alpha"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := failed(t, Struct(grades{})); !slices.Equal(got, []string{"grades.Scores min"}) {
+		t.Errorf("empty map: got %q", got)
+	}
+}
+
+func TestNested(t *testing.T) {
+	type class struct {
+		Teacher  *student
+		Students []student `validate:"min=1,dive"`
+	}
+	c := class{
+		Teacher:  &student{Name: "Reza", Age: 40, Level: "advanced"},
+		Students: []student{{Name: "Alice", Age: 30, Level: "beginner"}, {Name: "Bob", Age: -2, Level: "beginner"}},
+	}
+	if got := failed(t, Struct(&c)); !slices.Equal(got, []string{"class.Students[1].Age min"}) {
+		t.Errorf("got %q", got)
+	}
+	c.Teacher.Level = ""
+	if got := failed(t, Struct(c)); !slices.Equal(got, []string{"class.Teacher.Level oneof", "class.Students[1].Age min"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	type node struct {
+		Name string `validate:"required"`
+		Next *node
+		Kids []*node `validate:"dive"`
+	}
+	n := &node{}
+	n.Next = n
+	n.Kids = []*node{n, {Name: "leaf", Next: n}}
+	done := make(chan error, 1)
+	go func() { done <- Struct(n) }()
+	select {
+	case err := <-done:
+		// Each node is checked once, by the first path that reaches it.
+		if got := failed(t, err); !slices.Equal(got, []string{"node.Name required"}) {
+			t.Errorf("got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Struct did not return on a cyclic value")
+	}
+}
+
+func TestCrossField(t *testing.T) {
+	type course struct {
+		Start    time.Time
+		End      time.Time `validate:"gtfield=Start"`
+		MinSize  int
+		MaxSize  int    `validate:"gtefield=MinSize"`
+		Password string `validate:"required"`
+		Confirm  string `validate:"eqfield=Password"`
+	}
+	start := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	c := course{Start: start, End: start.AddDate(0, 3, 0), MinSize: 5, MaxSize: 5, Password: "x", Confirm: "x"}
+	if err := Struct(c); err != nil {
+		t.Errorf("valid course: %v", err)
+	}
+	c.End, c.MaxSize, c.Confirm = start, 4, "y"
+	want := []string{"course.End gtfield", "course.MaxSize gtefield", "course.Confirm eqfield"}
+	if got := failed(t, Struct(c)); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	v := New()
+	type user struct {
+		Username string `validate:"lowercase"`
+	}
+	var te *TagError
+	if err := v.Struct(user{"alice"}); !errors.As(err, &te) {
+		t.Fatalf("unknown rule: got %v, want a *TagError", err)
+	}
+	err := v.Register("lowercase", func(f Field) bool {
+		return f.Value.String() == strings.ToLower(f.Value.String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Registering drops the cached plan that failed to compile.
+	if err := v.Struct(user{"alice"}); err != nil {
+		t.Errorf("lowercase name: %v", err)
+	}
+	if got := failed(t, v.Struct(user{"Alice"})); !slices.Equal(got, []string{"user.Username lowercase"}) {
+		t.Errorf("got %q", got)
+	}
+	// The rule belongs to v only.
+	if err := Struct(user{"alice"}); !errors.As(err, &te) {
+		t.Errorf("default validator: got %v, want a *TagError", err)
+	}
+	for _, name := range []string{"", "dive", "omitempty", "a,b", "a=b"} {
+		if err := v.Register(name, func(Field) bool { return true }); err == nil {
+			t.Errorf("Register(%q) succeeded", name)
+		}
+	}
+}
+
+func TestTagErrors(t *testing.T) {
+	tests := []any{
+		struct {
+			A int `validate:"max=abc"`
+		}{},
+		struct {
+			A []int `validate:"keys,min=1"`
+		}{},
+		struct {
+			A map[string]int `validate:"dive,keys,required"`
+		}{},
+		struct {
+			A int `validate:"eqfield=Missing"`
+		}{},
+	}
+	for _, s := range tests {
+		var te *TagError
+		if err := Struct(s); !errors.As(err, &te) {
+			t.Errorf("%T: got %v, want a *TagError", s, err)
+		}
+	}
+}
+
+func TestPlanCache(t *testing.T) {
+	v := New()
+	s := student{Name: "Alice", Age: 30, Level: "beginner"}
+	if err := v.Struct(s); err != nil {
+		t.Fatal(err)
+	}
+	typ := reflect.TypeFor[student]()
+	first, ok := v.plans.Load(typ)
+	if !ok {
+		t.Fatal("no plan cached after Struct")
+	}
+	v.Struct(&s)
+	if again, _ := v.plans.Load(typ); again != first {
+		t.Error("the plan was compiled again")
+	}
+	// With the plan cached, a call only pays for the checks.
+	cached := testing.AllocsPerRun(100, func() { v.Struct(&s) })
+	uncached := testing.AllocsPerRun(100, func() {
+		v.plans.Clear()
+		v.Struct(&s)
+	})
+	if cached >= uncached {
+		t.Errorf("Struct allocates %v times with a cached plan and %v without", cached, uncached)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	err := Struct(student{Age: 200, Level: "beginner"})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatal(err)
+	}
+	if got, want := err.Error(), "student.Name is required; student.Age must be at most 150"; got != want {
+		t.Errorf("English:\ngot  %s\nwant %s", got, want)
+	}
+	persian := Catalog{
+		"required": "{field} الزامی است",
+		"":         "{field} نامعتبر است ({tag})",
+	}
+	got := errs.Translate(persian)
+	want := []string{"student.Name الزامی است", "student.Age must be at most 150"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Persian: got %q, want %q", got, want)
+	}
+	// A rule without a message in either catalog gets c's generic one.
+	custom := &FieldError{Namespace: "user.Username", Tag: "lowercase"}
+	if got := custom.Translate(persian); got != "user.Username نامعتبر است (lowercase)" {
+		t.Errorf("generic Persian message: got %q", got)
+	}
+	if got := custom.Translate(Catalog{}); got != "user.Username failed the lowercase rule" {
+		t.Errorf("generic English message: got %q", got)
+	}
+	// A catalog without a fallback uses the English message.
+	if got := errs[1].Translate(Catalog{}); got != "student.Age must be at most 150" {
+		t.Errorf("fallback: got %q", got)
+	}
+}
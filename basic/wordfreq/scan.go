@@ -0,0 +1,136 @@
+package wordfreq
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ScanWords is a bufio.SplitFunc that returns the words of the input,
+// following the word boundary rules of Unicode Standard Annex #29 in a
+// simplified form:
+//
+//   - a word is a run of letters, digits, combining marks and underscores
+//   - an apostrophe or a full stop between two letters stays in the word
+//     (don't, e.g), and so does a full stop or comma between two digits
+//     (3.14, 1,000)
+//   - the zero width non-joiner and joiner stay in the word, so the
+//     Persian می‌خواهم is one word
+//   - Chinese characters and hiragana are one word each, since those
+//     scripts do not separate words with spaces
+//
+// Everything else, including hyphens, separates words. Unlike
+// bufio.ScanWords it never splits a multi-byte rune across reads.
+func ScanWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		if !atEOF && !utf8.FullRune(data[start:]) {
+			return start, nil, nil
+		}
+		r, n := utf8.DecodeRune(data[start:])
+		if c := wordClassOf(r); c != notWord && c != mark {
+			break
+		}
+		start += n
+	}
+	if start == len(data) {
+		return start, nil, nil
+	}
+
+	i := start
+	first, n := utf8.DecodeRune(data[i:])
+	i += n
+	ideographic := wordClassOf(first) == ideograph
+	for i < len(data) {
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			return start, nil, nil
+		}
+		r, n := utf8.DecodeRune(data[i:])
+		class := wordClassOf(r)
+		switch {
+		case class == mark:
+			i += n
+			continue
+		case ideographic, class == ideograph:
+			return i, data[start:i], nil
+		case class == letter || class == digit:
+			i += n
+			continue
+		}
+		// A separator can still be inside a word, depending on the rune
+		// after it.
+		if i+n == len(data) || !utf8.FullRune(data[i+n:]) {
+			if !atEOF {
+				return start, nil, nil
+			}
+			return i, data[start:i], nil
+		}
+		prev, _ := utf8.DecodeLastRune(data[start:i])
+		next, _ := utf8.DecodeRune(data[i+n:])
+		if joins(prev, r, next) {
+			i += n
+			continue
+		}
+		return i, data[start:i], nil
+	}
+	if atEOF {
+		return len(data), data[start:], nil
+	}
+	return start, nil, nil
+}
+
+type wordClass uint8
+
+const (
+	notWord wordClass = iota
+	letter
+	digit
+	// mark is a combining mark or a zero width (non-)joiner; it extends
+	// whatever comes before it.
+	mark
+	ideograph
+)
+
+// asciiClass is wordClassOf for ASCII, which most text is mostly made of
+// and which would otherwise spend its time in the unicode tables.
+var asciiClass = func() (t [utf8.RuneSelf]wordClass) {
+	for r := range rune(utf8.RuneSelf) {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_':
+			t[r] = letter
+		case '0' <= r && r <= '9':
+			t[r] = digit
+		}
+	}
+	return t
+}()
+
+func wordClassOf(r rune) wordClass {
+	if 0 <= r && r < utf8.RuneSelf {
+		return asciiClass[r]
+	}
+	switch {
+	case r == 0x200C, r == 0x200D, unicode.IsMark(r):
+		return mark
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return ideograph
+	case unicode.IsLetter(r), r == '_':
+		return letter
+	case unicode.IsDigit(r):
+		return digit
+	}
+	return notWord
+}
+
+// joins reports whether sep between prev and next is part of a word.
+func joins(prev, sep, next rune) bool {
+	p, n := wordClassOf(prev), wordClassOf(next)
+	switch sep {
+	case '\'', '’', '·':
+		return p == letter && n == letter
+	case '.':
+		return p == n && (p == letter || p == digit)
+	case ',', '٫', '٬': // the last two are the Arabic decimal and thousands separators
+		return p == digit && n == digit
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package wordfreq
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"strings"
+
+	"golang.org/x/text/cases"
+)
+
+// StopWords is a set of words to leave out of the counts, such as "the"
+// and "and", which would otherwise top every list.
+type StopWords map[string]bool
+
+//go:embed stopwords/english.txt
+var english string
+
+// English returns a list of common English function words.
+func English() StopWords {
+	s, _ := ReadStopWords(strings.NewReader(english))
+	return s
+}
+
+// ReadStopWords reads a stop word list: one word per line, with blank
+// lines and lines starting with # ignored.
+func ReadStopWords(r io.Reader) (StopWords, error) {
+	s := StopWords{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		s[line] = true
+	}
+	return s, sc.Err()
+}
+
+// folded returns the stop words case-folded, to match folded words.
+func (s StopWords) folded() StopWords {
+	fold := cases.Fold()
+	out := make(StopWords, len(s))
+	for w := range s {
+		out[fold.String(w)] = true
+	}
+	return out
+}
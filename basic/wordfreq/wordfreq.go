@@ -0,0 +1,289 @@
+// Package wordfreq counts how often words occur in text.
+//
+// A Counter streams its input with bufio.Scanner and splits it into words
+// with ScanWords. With one worker it counts into a single map on the
+// calling goroutine. With more, one goroutine reads batches of lines and
+// the workers count them into maps of their own, each split into shards
+// by a hash of the word. At the end shard k of every worker is merged
+// into shard k of the result, one goroutine per shard, so no map is ever
+// shared and no lock is needed.
+package wordfreq
+
+import (
+	"bufio"
+	"cmp"
+	"hash/maphash"
+	"io"
+	"runtime"
+	"slices"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+)
+
+// DefaultShards is the number of shards when Options.Shards is 0.
+const DefaultShards = 64
+
+// batchSize is roughly how many bytes of lines go to a worker at a time.
+const batchSize = 64 << 10
+
+// maxToken is the longest line the sharded reader, or word the
+// single-threaded one, accepts.
+const maxToken = 16 << 20
+
+// Options configures a Counter.
+type Options struct {
+	// Workers is the number of counting goroutines; 0 means GOMAXPROCS.
+	// With 1 the counting happens on the calling goroutine.
+	Workers int
+	// Shards is the number of shards per map; 0 means DefaultShards.
+	Shards int
+	// Fold counts words regardless of case, using Unicode case folding:
+	// "Go", "GO" and "go" are one word, and so are "Straße" and "STRASSE".
+	Fold bool
+	// Stop lists words that are not counted.
+	Stop StopWords
+}
+
+// Entry is a word and its count.
+type Entry struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// Counter accumulates word counts over any number of inputs.
+type Counter struct {
+	opts    Options
+	seed    maphash.Seed
+	shards  []map[string]int
+	total   int
+	stopped int
+}
+
+// New returns an empty Counter.
+func New(opts Options) *Counter {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.Shards <= 0 {
+		opts.Shards = DefaultShards
+	}
+	if opts.Workers == 1 {
+		opts.Shards = 1
+	}
+	if opts.Fold && opts.Stop != nil {
+		opts.Stop = opts.Stop.folded()
+	}
+	c := &Counter{opts: opts, seed: maphash.MakeSeed(), shards: make([]map[string]int, opts.Shards)}
+	for i := range c.shards {
+		c.shards[i] = map[string]int{}
+	}
+	return c
+}
+
+// Add counts the words read from r.
+func (c *Counter) Add(r io.Reader) error {
+	if c.opts.Workers == 1 {
+		return c.addSerial(r)
+	}
+	return c.addSharded(r)
+}
+
+func (c *Counter) addSerial(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxToken)
+	sc.Split(ScanWords)
+	w := c.newWorker()
+	for sc.Scan() {
+		w.count(sc.Bytes())
+	}
+	c.merge([]*worker{w})
+	return sc.Err()
+}
+
+func (c *Counter) addSharded(r io.Reader) error {
+	batches := make(chan []byte, c.opts.Workers)
+	workers := make([]*worker, c.opts.Workers)
+	var wg sync.WaitGroup
+	for i := range workers {
+		w := c.newWorker()
+		workers[i] = w
+		wg.Go(func() {
+			for batch := range batches {
+				w.countAll(batch)
+			}
+		})
+	}
+
+	// Lines keep words whole: no word spans two batches.
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxToken)
+	batch := make([]byte, 0, batchSize+1024)
+	for sc.Scan() {
+		batch = append(batch, sc.Bytes()...)
+		batch = append(batch, '\n')
+		if len(batch) >= batchSize {
+			batches <- batch
+			batch = make([]byte, 0, batchSize+1024)
+		}
+	}
+	if len(batch) > 0 {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+	c.merge(workers)
+	return sc.Err()
+}
+
+// merge adds the workers' counts to the Counter, shard by shard in
+// parallel.
+func (c *Counter) merge(workers []*worker) {
+	var wg sync.WaitGroup
+	for k, shard := range c.shards {
+		wg.Go(func() {
+			for _, w := range workers {
+				for word, n := range w.shards[k] {
+					shard[word] += *n
+				}
+			}
+		})
+	}
+	wg.Wait()
+	for _, w := range workers {
+		c.total += w.total
+		c.stopped += w.stopped
+	}
+}
+
+// worker counts into maps only it touches. The maps hold pointers so
+// that counting a word seen before does not allocate: m[string(b)] only
+// avoids copying b into a new string when it is a plain lookup.
+type worker struct {
+	c       *Counter
+	shards  []map[string]*int
+	fold    cases.Caser
+	buf     []byte
+	total   int
+	stopped int
+}
+
+func (c *Counter) newWorker() *worker {
+	w := &worker{c: c, shards: make([]map[string]*int, len(c.shards))}
+	for i := range w.shards {
+		w.shards[i] = map[string]*int{}
+	}
+	if c.opts.Fold {
+		w.fold = cases.Fold()
+	}
+	return w
+}
+
+// countAll counts every word in data.
+func (w *worker) countAll(data []byte) {
+	for len(data) > 0 {
+		advance, word, _ := ScanWords(data, true)
+		if advance == 0 {
+			break
+		}
+		data = data[advance:]
+		if word != nil {
+			w.count(word)
+		}
+	}
+}
+
+func (w *worker) count(word []byte) {
+	if w.c.opts.Fold {
+		word = w.foldWord(word)
+	}
+	if w.c.opts.Stop[string(word)] {
+		w.stopped++
+		return
+	}
+	w.total++
+	shard := w.shards[0]
+	if len(w.shards) > 1 {
+		shard = w.shards[maphash.Bytes(w.c.seed, word)%uint64(len(w.shards))]
+	}
+	if n := shard[string(word)]; n != nil {
+		*n++
+		return
+	}
+	n := 1
+	shard[string(word)] = &n
+}
+
+// foldWord case-folds word, lowering ASCII words by hand into a reused
+// buffer since the Caser allocates.
+func (w *worker) foldWord(word []byte) []byte {
+	w.buf = w.buf[:0]
+	for _, b := range word {
+		if b >= utf8.RuneSelf {
+			w.fold.Reset()
+			return w.fold.Bytes(word)
+		}
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		w.buf = append(w.buf, b)
+	}
+	return w.buf
+}
+
+// Total returns the number of words counted, not including stop words.
+func (c *Counter) Total() int { return c.total }
+
+// Stopped returns the number of stop words skipped.
+func (c *Counter) Stopped() int { return c.stopped }
+
+// Unique returns the number of different words counted.
+func (c *Counter) Unique() int {
+	n := 0
+	for _, s := range c.shards {
+		n += len(s)
+	}
+	return n
+}
+
+// Count returns how often word was counted. With Options.Fold, word must
+// already be folded.
+func (c *Counter) Count(word string) int {
+	if len(c.shards) == 1 {
+		return c.shards[0][word]
+	}
+	return c.shards[maphash.String(c.seed, word)%uint64(len(c.shards))][word]
+}
+
+// Top returns the n most frequent words, or all of them if n <= 0. Ties
+// are broken alphabetically, in byte order. Each shard picks its own top
+// n in parallel, and the overall top n is among those.
+func (c *Counter) Top(n int) []Entry {
+	tops := make([][]Entry, len(c.shards))
+	var wg sync.WaitGroup
+	for k, shard := range c.shards {
+		wg.Go(func() {
+			entries := make([]Entry, 0, len(shard))
+			for word, count := range shard {
+				entries = append(entries, Entry{word, count})
+			}
+			tops[k] = top(entries, n)
+		})
+	}
+	wg.Wait()
+	return top(slices.Concat(tops...), n)
+}
+
+func top(entries []Entry, n int) []Entry {
+	slices.SortFunc(entries, func(a, b Entry) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Word, b.Word)
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
@@ -0,0 +1,142 @@
+package wordfreq
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+)
+
+// words splits s with ScanWords, reading one byte at a time so every
+// multi-byte rune arrives in pieces.
+func words(s string) []string {
+	sc := bufio.NewScanner(iotest.OneByteReader(strings.NewReader(s)))
+	sc.Split(ScanWords)
+	var out []string
+	for sc.Scan() {
+		out = append(out, sc.Text())
+	}
+	return out
+}
+
+func TestScanWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Hello, world!", []string{"Hello", "world"}},
+		{"don't say ‘can’t’", []string{"don't", "say", "can’t"}},
+		{"'quoted' words", []string{"quoted", "words"}},
+		{"pi is 3.14, e.g. about 1,000.", []string{"pi", "is", "3.14", "e.g", "about", "1,000"}},
+		{"a.1 and 1.a", []string{"a", "1", "and", "1", "a"}},
+		{"well-known snake_case", []string{"well", "known", "snake_case"}},
+		{"من می‌خواهم بروم", []string{"من", "می‌خواهم", "بروم"}},
+		{"۳٫۱۴ است", []string{"۳٫۱۴", "است"}},
+		{"café au lait", []string{"café", "au", "lait"}},
+		{"日本語のtext", []string{"日", "本", "語", "の", "text"}},
+		{"", nil},
+		{" \n\t-- ", nil},
+	}
+	for _, tt := range tests {
+		if got := words(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// counters returns a single-threaded and a sharded Counter with opts.
+func counters(opts Options) map[string]*Counter {
+	serial, sharded := opts, opts
+	serial.Workers = 1
+	sharded.Workers, sharded.Shards = 4, 8
+	return map[string]*Counter{"serial": New(serial), "sharded": New(sharded)}
+}
+
+func TestCount(t *testing.T) {
+	const text = "The cat and the hat.\nTHE CAT sat; Straße STRASSE strasse.\n"
+	for name, c := range counters(Options{Fold: true, Stop: StopWords{"and": true}}) {
+		if err := c.Add(strings.NewReader(text)); err != nil {
+			t.Fatal(err)
+		}
+		if c.Total() != 10 || c.Stopped() != 1 || c.Unique() != 5 {
+			t.Errorf("%s: total %d, stopped %d, unique %d", name, c.Total(), c.Stopped(), c.Unique())
+		}
+		for word, want := range map[string]int{"the": 3, "cat": 2, "strasse": 3, "and": 0} {
+			if got := c.Count(word); got != want {
+				t.Errorf("%s: Count(%q) = %d, want %d", name, word, got, want)
+			}
+		}
+	}
+}
+
+func TestTop(t *testing.T) {
+	// b, a and c tie with two each; ties go alphabetically.
+	const text = "b c a d b a c e"
+	want := []Entry{{"a", 2}, {"b", 2}, {"c", 2}, {"d", 1}}
+	for name, c := range counters(Options{}) {
+		if err := c.Add(strings.NewReader(text)); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Top(4); !slices.Equal(got, want) {
+			t.Errorf("%s: Top(4) = %v, want %v", name, got, want)
+		}
+		if got := c.Top(0); len(got) != 5 || got[4] != (Entry{"e", 1}) {
+			t.Errorf("%s: Top(0) = %v", name, got)
+		}
+	}
+}
+
+func TestReadStopWords(t *testing.T) {
+	stop, err := ReadStopWords(strings.NewReader("# comment\nthe\n\n  A  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stop) != 2 || !stop["the"] || !stop["A"] {
+		t.Errorf("got %v", stop)
+	}
+	if en := English(); !en["the"] || en["cat"] {
+		t.Error("English() has the wrong words")
+	}
+}
+
+// benchText is about 4 MB of words drawn from a Zipf distribution, the
+// way words are spread in real text. It is built on first use.
+var benchText = sync.OnceValue(func() []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(r, 1.1, 1, 50000)
+	var b bytes.Buffer
+	for b.Len() < 4<<20 {
+		for range 12 {
+			fmt.Fprintf(&b, "w%d ", zipf.Uint64())
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+})
+
+func benchmarkCount(b *testing.B, opts Options) {
+	text := benchText()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		if err := New(opts).Add(bytes.NewReader(text)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountSerial(b *testing.B) {
+	benchmarkCount(b, Options{Workers: 1})
+}
+
+// BenchmarkCountSharded uses GOMAXPROCS workers, at least two so the
+// sharded path runs; compare with -cpu 1,4.
+func BenchmarkCountSharded(b *testing.B) {
+	benchmarkCount(b, Options{Workers: max(runtime.GOMAXPROCS(0), 2)})
+}